@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/database"
+	"github.com/sblackwood23/fantasy-draft-app/internal/export"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+// runExport writes an event's draft results in the requested format
+// Example: draftctl export -event 1 -format csv -o masters.csv
+func runExport(ctx context.Context, db *database.DB, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	eventID := fs.Int("event", 0, "event ID to export (required)")
+	format := fs.String("format", export.FormatCSV, "output format: csv, json or html")
+	out := fs.String("o", "", "output file (defaults to stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *eventID == 0 {
+		return errors.New("-event is required")
+	}
+	if *format != export.FormatCSV && *format != export.FormatJSON && *format != export.FormatHTML {
+		return fmt.Errorf("unsupported format %q (want csv, json or html)", *format)
+	}
+
+	event, err := repository.NewEventRepository(db.Pool).GetByID(ctx, *eventID)
+	if err != nil {
+		return fmt.Errorf("failed to get event %d: %w", *eventID, err)
+	}
+
+	users, err := repository.NewUserRepository(db.Pool).GetByEvent(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get teams: %w", err)
+	}
+
+	picks, err := repository.NewDraftResultRepository(db.Pool).GetPicksByEvent(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get draft results: %w", err)
+	}
+
+	doc := export.NewDocument(event, users, picks, time.Now())
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := doc.Write(w, *format); err != nil {
+		return err
+	}
+
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "Exported %d picks for %q to %s\n", len(doc.Picks), event.Name, *out)
+	}
+	return nil
+}
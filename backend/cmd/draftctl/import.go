@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/database"
+	"github.com/sblackwood23/fantasy-draft-app/internal/importer"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+// runImportPlayers upserts a CSV or JSON player list, optionally attaching it to an event
+// Example: draftctl import-players -file field.csv -event 1
+func runImportPlayers(ctx context.Context, db *database.DB, args []string) error {
+	fs := flag.NewFlagSet("import-players", flag.ContinueOnError)
+	file := fs.String("file", "", "CSV or JSON player list (required)")
+	format := fs.String("format", "", "input format: csv or json (defaults from the file extension)")
+	eventID := fs.Int("event", 0, "event ID to attach the imported players to")
+	verbose := fs.Bool("v", false, "print the outcome of every row")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := importer.Parse(f, *format)
+	if err != nil {
+		return err
+	}
+
+	if *eventID != 0 {
+		if _, err := repository.NewEventRepository(db.Pool).GetByID(ctx, *eventID); err != nil {
+			return fmt.Errorf("failed to get event %d: %w", *eventID, err)
+		}
+	}
+
+	im := importer.NewImporter(db.Pool, repository.NewPlayerRepository(db.Pool), repository.NewEventPlayerRepository(db.Pool), repository.NewRankingRepository(db.Pool))
+	result, err := im.ImportPlayers(ctx, rows, *eventID)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range result.Rows {
+		if !*verbose && row.Action != importer.ActionSkipped {
+			continue
+		}
+		line := fmt.Sprintf("  line %d: %s %s", row.Line, row.Action, row.Name)
+		if row.Reason != "" {
+			line += " (" + row.Reason + ")"
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Printf("Imported %d rows: %d created, %d updated, %d skipped\n", len(result.Rows), result.Created, result.Updated, result.Skipped)
+	if *eventID != 0 {
+		fmt.Printf("Added %d players to event %d\n", result.Attached, *eventID)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/database"
+)
+
+// draftctl is the command-line tool for commissioner tasks that run outside the server
+// Usage: draftctl <command> [flags]
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	commands := map[string]func(ctx context.Context, db *database.DB, args []string) error{
+		"export":         runExport,
+		"import-players": runImportPlayers,
+	}
+
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	db, err := database.New(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := run(ctx, db, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+		db.Close()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: draftctl <command> [flags]
+
+Commands:
+  export            Export an event's draft results as CSV, JSON or an HTML draft board
+  import-players    Create or update players from a CSV or JSON list
+
+Run "draftctl <command> -h" for the command's flags.`)
+}
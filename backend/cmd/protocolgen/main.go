@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/draft"
+)
+
+// protocolgen writes the JSON Schema for the draft WebSocket protocol
+// Run via `go generate ./internal/draft` to refresh the frontend copy
+func main() {
+	out := flag.String("o", "", "output file (defaults to stdout)")
+	flag.Parse()
+
+	data, err := json.MarshalIndent(draft.ProtocolSchema(), "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to encode protocol schema: %v", err)
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		log.Fatalf("Failed to write protocol schema: %v", err)
+	}
+	fmt.Printf("Wrote protocol schema to %s\n", *out)
+}
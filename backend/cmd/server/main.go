@@ -2,8 +2,7 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,23 +10,36 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/auth"
 	"github.com/sblackwood23/fantasy-draft-app/internal/database"
 	"github.com/sblackwood23/fantasy-draft-app/internal/draft"
 	"github.com/sblackwood23/fantasy-draft-app/internal/handlers"
+	"github.com/sblackwood23/fantasy-draft-app/internal/importer"
+	"github.com/sblackwood23/fantasy-draft-app/internal/logging"
+	"github.com/sblackwood23/fantasy-draft-app/internal/metrics"
+	"github.com/sblackwood23/fantasy-draft-app/internal/notify"
 	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+	"github.com/sblackwood23/fantasy-draft-app/internal/webhook"
 )
 
+// sessionTTL is how long a draft room session token stays valid
+const sessionTTL = 24 * time.Hour
+
 func main() {
 	ctx := context.Background()
 
+	// JSON logs at the level in LOG_LEVEL
+	logging.Setup()
+
 	// Initialize database connection
 	db, err := database.New(ctx)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		slog.Error("Failed to connect to database", "err", err)
+		os.Exit(1)
 	}
 	defer db.Close()
 
-	fmt.Println("Database connected successfully")
+	slog.Info("Database connected successfully")
 
 	// Initialize repositories
 	eventRepo := repository.NewEventRepository(db.Pool)
@@ -35,18 +47,61 @@ func main() {
 	userRepo := repository.NewUserRepository(db.Pool)
 	eventPlayerRepo := repository.NewEventPlayerRepository(db.Pool)
 	draftResultRepo := repository.NewDraftResultRepository(db.Pool)
+	chatMessageRepo := repository.NewChatMessageRepository(db.Pool)
+	rankingRepo := repository.NewRankingRepository(db.Pool)
+	auditRepo := repository.NewAuditRepository(db.Pool)
+	leagueRepo := repository.NewLeagueRepository(db.Pool)
+	templateRepo := repository.NewTemplateRepository(db.Pool)
+	webhookRepo := repository.NewWebhookRepository(db.Pool)
+	notificationPrefsRepo := repository.NewNotificationPrefsRepository(db.Pool)
 
 	// Initialize services
-	draftService := draft.NewDraftService(draftResultRepo, eventRepo)
+	playerImporter := importer.NewImporter(db.Pool, playerRepo, eventPlayerRepo, rankingRepo)
+
+	// Session tokens are signed with SESSION_SECRET; without it they last until the server restarts
+	sessionKey := []byte(os.Getenv("SESSION_SECRET"))
+	if len(sessionKey) == 0 {
+		slog.Warn("SESSION_SECRET not set; draft room sessions will not survive a restart")
+		if sessionKey, err = auth.NewSessionKey(); err != nil {
+			slog.Error("Failed to generate session key", "err", err)
+			os.Exit(1)
+		}
+	}
+	sessions := auth.NewSessionSigner(sessionKey, sessionTTL)
+
+	// Turn notifications go to webhooks always, and by email once SMTP is configured
+	notifiers := []draft.Notifier{notify.NewWebhookNotifier(webhookRepo)}
+	if smtpConfig, ok := notify.SMTPConfigFromEnv(); ok {
+		notifiers = append(notifiers, notify.NewEmailNotifier(smtpConfig))
+	} else {
+		slog.Info("SMTP_HOST not set; email notifications are disabled")
+	}
+	draftService := draft.NewDraftService(draftResultRepo, eventRepo, eventRepo, chatMessageRepo, eventRepo, rankingRepo, userRepo, webhookRepo, sessions, notificationPrefsRepo, notifiers...)
+	draftService.RegisterMetrics(metrics.Default)
+	guard := handlers.NewGuard(auditRepo, os.Getenv("ADMIN_TOKEN"), sessions, draftService)
+
+	// Deliver queued webhooks in the background until shutdown
+	dispatchCtx, stopDispatch := context.WithCancel(ctx)
+	defer stopDispatch()
+	// WEBHOOK_ALLOW_PRIVATE_NETWORKS=true lets deliveries reach local servers, for testing only
+	allowPrivate := os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS") == "true"
+	go webhook.NewDispatcher(webhookRepo, allowPrivate).Run(dispatchCtx)
 
 	// Initialize dependencies
 	deps := &Dependencies{
-		Event:       handlers.NewEventHandler(eventRepo),
-		Player:      handlers.NewPlayerHandler(playerRepo),
-		User:        handlers.NewUserHandler(userRepo),
-		EventPlayer: handlers.NewEventPlayerHandler(eventPlayerRepo),
-		DraftRoom:   handlers.NewDraftRoomHandler(eventPlayerRepo, eventRepo, userRepo, draftService),
-		Draft:       draftService,
+		Event:        handlers.NewEventHandler(eventRepo, guard),
+		Player:       handlers.NewPlayerHandler(playerRepo),
+		PlayerImport: handlers.NewPlayerImportHandler(playerImporter, eventRepo),
+		User:         handlers.NewUserHandler(userRepo, eventRepo, guard),
+		EventPlayer:  handlers.NewEventPlayerHandler(eventPlayerRepo, eventRepo, guard),
+		DraftRoom:    handlers.NewDraftRoomHandler(eventPlayerRepo, eventRepo, userRepo, draftService, guard, sessions),
+		DraftResult:  handlers.NewDraftResultHandler(draftResultRepo, eventRepo, userRepo),
+		Ranking:      handlers.NewRankingHandler(rankingRepo, playerImporter),
+		League:       handlers.NewLeagueHandler(leagueRepo, eventRepo, draftResultRepo, guard),
+		Template:     handlers.NewTemplateHandler(templateRepo),
+		Webhook:      handlers.NewWebhookHandler(webhookRepo, eventRepo, guard),
+		Notification: handlers.NewNotificationPrefsHandler(notificationPrefsRepo, userRepo, guard),
+		Draft:        draftService,
 	}
 
 	r := chi.NewRouter()
@@ -56,7 +111,7 @@ func main() {
 
 	// Start server
 	port := ":8080"
-	fmt.Printf("Server starting on port %s\n", port)
+	slog.Info("Server starting", "addr", port)
 
 	// Graceful shutdown handling
 	server := &http.Server{
@@ -71,23 +126,25 @@ func main() {
 	// Start server in goroutine
 	go func() {
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", err)
+			slog.Error("Server error", "err", err)
+			os.Exit(1)
 		}
 	}()
 
 	// Wait for interrupt signal
 	<-stop
-	fmt.Println("\nShutting down server...")
+	slog.Info("Shutting down server")
 
 	// Graceful shutdown with 5 second timeout
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Server shutdown error: %v", err)
+		slog.Error("Server shutdown error", "err", err)
+		os.Exit(1)
 	}
 
-	fmt.Println("Server stopped gracefully")
+	slog.Info("Server stopped gracefully")
 }
 
 // healthCheckHandler returns server and database health status
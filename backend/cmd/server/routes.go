@@ -7,27 +7,37 @@ import (
 	"github.com/sblackwood23/fantasy-draft-app/internal/database"
 	"github.com/sblackwood23/fantasy-draft-app/internal/draft"
 	"github.com/sblackwood23/fantasy-draft-app/internal/handlers"
+	"github.com/sblackwood23/fantasy-draft-app/internal/logging"
+	"github.com/sblackwood23/fantasy-draft-app/internal/metrics"
 )
 
 // Dependencies contains all handlers and services needed for route registration
 type Dependencies struct {
-	Event       *handlers.EventHandler
-	Player      *handlers.PlayerHandler
-	User        *handlers.UserHandler
-	EventPlayer *handlers.EventPlayerHandler
-	DraftRoom   *handlers.DraftRoomHandler
-	Draft       *draft.DraftService
+	Event        *handlers.EventHandler
+	Player       *handlers.PlayerHandler
+	PlayerImport *handlers.PlayerImportHandler
+	User         *handlers.UserHandler
+	EventPlayer  *handlers.EventPlayerHandler
+	DraftRoom    *handlers.DraftRoomHandler
+	DraftResult  *handlers.DraftResultHandler
+	Ranking      *handlers.RankingHandler
+	League       *handlers.LeagueHandler
+	Template     *handlers.TemplateHandler
+	Webhook      *handlers.WebhookHandler
+	Notification *handlers.NotificationPrefsHandler
+	Draft        *draft.DraftService
 }
 
 func setupRoutes(r *chi.Mux, db *database.DB, deps *Dependencies) {
 	// Middleware
-	r.Use(middleware.Logger)
+	r.Use(logging.RequestLogger)
+	r.Use(metrics.InstrumentHTTP(metrics.Default))
 	r.Use(middleware.Recoverer)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:5173"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Admin-Token", "X-Request-ID"},
+		ExposedHeaders:   []string{"Link", "X-Request-ID"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -35,6 +45,9 @@ func setupRoutes(r *chi.Mux, db *database.DB, deps *Dependencies) {
 	// Health check
 	r.Get("/health", healthCheckHandler(db))
 
+	// Prometheus metrics
+	r.Get("/metrics", metrics.Default.Handler().ServeHTTP)
+
 	// WebSocket route for draft
 	r.Get("/ws/draft", deps.Draft.HandleWebSocket)
 
@@ -44,28 +57,88 @@ func setupRoutes(r *chi.Mux, db *database.DB, deps *Dependencies) {
 	r.Post("/events", deps.Event.CreateEvent)
 	r.Put("/events/{id}", deps.Event.UpdateEvent)
 	r.Delete("/events/{id}", deps.Event.DeleteEvent)
+	r.Get("/events/{id}/audit", deps.Event.GetAuditLog)
+	r.Post("/events/{id}/clone", deps.Event.CloneEvent)
+
+	// Webhooks routes
+	r.Get("/events/{id}/webhooks", deps.Webhook.ListWebhooks)
+	r.Post("/events/{id}/webhooks", deps.Webhook.CreateWebhook)
+	r.Put("/events/{id}/webhooks/{webhookID}", deps.Webhook.UpdateWebhook)
+	r.Delete("/events/{id}/webhooks/{webhookID}", deps.Webhook.DeleteWebhook)
+	r.Get("/events/{id}/webhooks/{webhookID}/deliveries", deps.Webhook.GetWebhookDeliveries)
+	r.Post("/events/{id}/webhooks/{webhookID}/ping", deps.Webhook.PingWebhook)
+
+	// Event templates routes
+	r.Get("/templates", deps.Template.ListTemplates)
+	r.Post("/templates", deps.Template.CreateTemplate)
+	r.Get("/templates/{id}", deps.Template.GetTemplate)
+	r.Delete("/templates/{id}", deps.Template.DeleteTemplate)
+	r.Post("/templates/{id}/events", deps.Template.CreateEventFromTemplate)
 
 	// Players routes
 	r.Get("/players/{id}", deps.Player.GetPlayer)
 	r.Get("/players", deps.Player.ListPlayers)
 	r.Post("/players", deps.Player.CreatePlayer)
+	r.Post("/players/import", deps.PlayerImport.ImportPlayers)
 	r.Put("/players/{id}", deps.Player.UpdatePlayer)
 	r.Delete("/players/{id}", deps.Player.DeletePlayer)
 
+	// Rankings routes
+	r.Get("/rankings", deps.Ranking.ListSources)
+	r.Get("/rankings/{source}", deps.Ranking.GetRankings)
+	r.Put("/rankings/{source}", deps.Ranking.ImportRankings)
+	r.Delete("/rankings/{source}", deps.Ranking.DeleteRankings)
+
+	// Leagues routes
+	r.Get("/leagues", deps.League.ListLeagues)
+	r.Post("/leagues", deps.League.CreateLeague)
+	r.Get("/leagues/{id}", deps.League.GetLeague)
+	r.Put("/leagues/{id}", deps.League.UpdateLeague)
+	r.Delete("/leagues/{id}", deps.League.DeleteLeague)
+	r.Get("/leagues/{id}/members", deps.League.ListMembers)
+	r.Post("/leagues/{id}/members", deps.League.AddMember)
+	r.Delete("/leagues/{id}/members/{memberID}", deps.League.RemoveMember)
+	r.Put("/leagues/{id}/members/{memberID}/teams/{userID}", deps.League.LinkMemberTeam)
+	r.Delete("/leagues/{id}/members/{memberID}/teams/{userID}", deps.League.UnlinkMemberTeam)
+	r.Get("/leagues/{id}/members/{memberID}/history", deps.League.GetMemberHistory)
+	r.Get("/leagues/{id}/events", deps.League.ListLeagueEvents)
+	r.Post("/leagues/{id}/events", deps.League.CreateLeagueEvent)
+	r.Get("/leagues/{id}/history", deps.League.GetLeagueHistory)
+
 	// Users routes
 	r.Get("/users/{id}", deps.User.GetUser)
 	r.Get("/users", deps.User.ListUsers)
 	r.Post("/users", deps.User.CreateUser)
 	r.Put("/users/{id}", deps.User.UpdateUser)
 	r.Delete("/users/{id}", deps.User.DeleteUser)
+	r.Get("/users/{id}/profile", deps.User.GetTeamProfile)
+	r.Put("/users/{id}/profile", deps.User.UpdateTeamProfile)
+	r.Get("/users/{id}/avatar", deps.User.GetTeamAvatar)
+	r.Put("/users/{id}/avatar", deps.User.UploadTeamAvatar)
+	r.Delete("/users/{id}/avatar", deps.User.DeleteTeamAvatar)
+	r.Get("/users/{id}/notifications", deps.Notification.GetNotificationPrefs)
+	r.Put("/users/{id}/notifications", deps.Notification.UpdateNotificationPrefs)
 
 	// Event players routes
 	r.Get("/events/{id}/players", deps.EventPlayer.GetEventPlayers)
 	r.Post("/events/{id}/players", deps.EventPlayer.AddEventPlayers)
+	r.Put("/events/{id}/players", deps.EventPlayer.ReplaceEventPlayers)
+	r.Delete("/events/{id}/players", deps.EventPlayer.RemoveEventPlayers)
 	r.Delete("/events/{id}/players/{playerID}", deps.EventPlayer.RemoveEventPlayer)
 
+	// Draft results routes
+	r.Get("/events/{id}/results", deps.DraftResult.GetResults)
+	r.Get("/events/{id}/teams", deps.DraftResult.GetTeams)
+	r.Get("/events/{id}/teams/{userID}/roster", deps.DraftResult.GetTeamRoster)
+	r.Get("/events/{id}/export", deps.DraftResult.ExportResults)
+
 	// Draft room routes (HTTP)
 	r.Post("/events/{id}/draft-room", deps.DraftRoom.CreateDraftRoom)
 	r.Get("/events/{id}/draft-room", deps.DraftRoom.GetDraftRoom)
+	r.Get("/events/{id}/draft-room/clients", deps.DraftRoom.GetDraftRoomClients)
+	r.Get("/events/{id}/draft-feed", deps.Draft.HandleDraftFeed)
+	r.Get("/events/{id}/roster", deps.DraftRoom.GetEventRoster)
 	r.Post("/events/join", deps.DraftRoom.JoinEvent)
+	r.Post("/events/{id}/admin-session", deps.DraftRoom.CreateAdminSession)
+	r.Post("/events/spectate", deps.DraftRoom.SpectateEvent)
 }
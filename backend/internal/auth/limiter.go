@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter locks a key (an IP address, an event) out after too many failed attempts
+// Failures are counted in a fixed window that starts with the first failure; reaching
+// the maximum locks the key for the lockout duration. Safe for concurrent use.
+type Limiter struct {
+	mu          sync.Mutex
+	maxFailures int
+	window      time.Duration
+	lockout     time.Duration
+	entries     map[string]*limiterEntry
+	lastSweep   time.Time
+	now         func() time.Time
+}
+
+type limiterEntry struct {
+	failures    int
+	windowStart time.Time
+	lockedUntil time.Time
+}
+
+// NewLimiter creates a Limiter allowing maxFailures failures per window before a lockout
+func NewLimiter(maxFailures int, window, lockout time.Duration) *Limiter {
+	return &Limiter{
+		maxFailures: maxFailures,
+		window:      window,
+		lockout:     lockout,
+		entries:     make(map[string]*limiterEntry),
+		now:         time.Now,
+	}
+}
+
+// Locked reports whether a key is locked out, and for how much longer
+func (l *Limiter) Locked(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	entry, ok := l.entries[key]
+	if !ok || !now.Before(entry.lockedUntil) {
+		return false, 0
+	}
+	return true, entry.lockedUntil.Sub(now)
+}
+
+// Fail records a failed attempt for a key
+func (l *Limiter) Fail(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	entry, ok := l.entries[key]
+	if !ok || now.Sub(entry.windowStart) >= l.window {
+		entry = &limiterEntry{windowStart: now, lockedUntil: entry.lockedUntilOrZero()}
+		l.entries[key] = entry
+	}
+
+	entry.failures++
+	if entry.failures >= l.maxFailures {
+		entry.lockedUntil = now.Add(l.lockout)
+		entry.failures = 0
+		entry.windowStart = now
+	}
+}
+
+// sweep drops entries whose window and lockout have both passed, at most once per window
+// Must be called while holding the mutex
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+	for key, entry := range l.entries {
+		if now.Sub(entry.windowStart) >= l.window && !now.Before(entry.lockedUntil) {
+			delete(l.entries, key)
+		}
+	}
+}
+
+func (e *limiterEntry) lockedUntilOrZero() time.Time {
+	if e == nil {
+		return time.Time{}
+	}
+	return e.lockedUntil
+}
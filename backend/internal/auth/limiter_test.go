@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	const (
+		maxFailures = 3
+		window      = time.Minute
+		lockout     = 5 * time.Minute
+	)
+
+	// Each step moves the clock forward by after, then records failures for key before checking it
+	type step struct {
+		after    time.Duration
+		key      string
+		failures int
+		locked   bool
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name:  "locks at the maximum",
+			steps: []step{{0, "a", maxFailures - 1, false}, {time.Second, "a", 1, true}},
+		},
+		{
+			name:  "keys are counted separately",
+			steps: []step{{0, "a", maxFailures - 1, false}, {0, "b", maxFailures - 1, false}, {0, "a", 0, false}},
+		},
+		{
+			name:  "failures outside the window are forgotten",
+			steps: []step{{0, "a", maxFailures - 1, false}, {window, "a", 1, false}, {time.Second, "a", maxFailures - 1, true}},
+		},
+		{
+			name:  "lockout ends",
+			steps: []step{{0, "a", maxFailures, true}, {lockout - time.Second, "a", 0, true}, {time.Second, "a", 0, false}},
+		},
+		{
+			name:  "a new window does not end a lockout",
+			steps: []step{{0, "a", maxFailures, true}, {window, "a", 1, true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewLimiter(maxFailures, window, lockout)
+			now := time.Now()
+			limiter.now = func() time.Time { return now }
+
+			for i, s := range tt.steps {
+				now = now.Add(s.after)
+				for range s.failures {
+					limiter.Fail(s.key)
+				}
+				locked, retryAfter := limiter.Locked(s.key)
+				if locked != s.locked {
+					t.Fatalf("step %d: locked = %v, want %v", i, locked, s.locked)
+				}
+				if locked && (retryAfter <= 0 || retryAfter > lockout) {
+					t.Fatalf("step %d: retry after %v, want within the lockout", i, retryAfter)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Package auth hashes and checks the shared secrets used to enter a draft, and limits
+// how fast they can be guessed.
+package auth
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Hash parameters for new secrets (PBKDF2-HMAC-SHA256, per the OWASP recommendation)
+const (
+	hashIterations = 600_000
+	saltSize       = 16
+	keySize        = 32
+	tokenSize      = 32
+)
+
+// Team PIN length limits
+const (
+	minPinLength = 4
+	maxPinLength = 8
+)
+
+// Stored hash formats
+const (
+	schemePBKDF2 = "pbkdf2-sha256" // pbkdf2-sha256$<iterations>$<base64 salt>$<base64 key>
+	schemeSHA256 = "sha256"        // sha256$<hex salt>$<hex sha256(salt || secret)>, from migrating plaintext passkeys
+)
+
+// HashSecret returns a salted hash of a secret for storage
+func HashSecret(secret string) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, secret, salt, hashIterations, keySize)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s$%d$%s$%s", schemePBKDF2, hashIterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// dummyHash is what VerifyNoSecret checks against, hashed once on first use
+var dummyHash = sync.OnceValue(func() string {
+	hash, _ := HashSecret("")
+	return hash
+})
+
+// VerifyNoSecret takes as long as VerifySecret against a current hash, and always fails
+// Call it when there is no stored hash to check, e.g. for an unknown ID, so response times
+// do not reveal which IDs exist.
+func VerifyNoSecret(secret string) bool {
+	VerifySecret(secret, dummyHash())
+	return false
+}
+
+// NewToken returns a random URL-safe token, e.g. for a one-time link
+func NewToken() (string, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ValidPin reports whether a team PIN is 4 to 8 digits
+func ValidPin(pin string) bool {
+	if len(pin) < minPinLength || len(pin) > maxPinLength {
+		return false
+	}
+	for _, c := range pin {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// HashToken returns the hex SHA-256 of a token for storage and lookup
+// Tokens from NewToken are random, so unlike secrets they need no salt or stretching.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifySecret reports whether a secret matches a stored hash
+// rehash is true when the hash uses an outdated format or cost and should be replaced
+// with HashSecret(secret) now that the secret is known.
+func VerifySecret(secret, hash string) (ok, rehash bool) {
+	parts := strings.Split(hash, "$")
+	switch {
+	case len(parts) == 4 && parts[0] == schemePBKDF2:
+		iterations, err := strconv.Atoi(parts[1])
+		if err != nil || iterations < 1 {
+			return false, false
+		}
+		salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+		if err != nil {
+			return false, false
+		}
+		want, err := base64.RawStdEncoding.DecodeString(parts[3])
+		if err != nil {
+			return false, false
+		}
+		got, err := pbkdf2.Key(sha256.New, secret, salt, iterations, len(want))
+		if err != nil {
+			return false, false
+		}
+		ok = subtle.ConstantTimeCompare(got, want) == 1
+		return ok, ok && iterations < hashIterations
+
+	case len(parts) == 3 && parts[0] == schemeSHA256:
+		salt, err := hex.DecodeString(parts[1])
+		if err != nil {
+			return false, false
+		}
+		want, err := hex.DecodeString(parts[2])
+		if err != nil {
+			return false, false
+		}
+		sum := sha256.Sum256(append(salt, secret...))
+		ok = subtle.ConstantTimeCompare(sum[:], want) == 1
+		return ok, ok
+
+	default:
+		return false, false
+	}
+}
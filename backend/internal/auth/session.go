@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sessionKeySize is the size of a generated session signing key
+const sessionKeySize = 32
+
+// ErrInvalidSession is returned for a session token that is malformed, forged or expired
+var ErrInvalidSession = errors.New("invalid or expired session token")
+
+// Session roles, as encoded in a token
+const (
+	roleTeam      = 0
+	roleAdmin     = 1
+	roleSpectator = 2
+)
+
+// Session is the identity a session token carries: a team of an event, an admin of it,
+// or a spectator who entered its passkey
+type Session struct {
+	EventID   int
+	UserID    int  // 0 for an admin or spectator session
+	Admin     bool // Issued for the admin token, not a team
+	Spectator bool // Issued for the event's passkey, to watch only
+	ExpiresAt time.Time
+}
+
+// SessionSigner issues and verifies signed session tokens (HMAC-SHA256)
+// Tokens are stateless: anyone holding the key can verify them, and they cannot be revoked
+// before they expire except by changing the key. Safe for concurrent use.
+type SessionSigner struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewSessionSigner creates a signer whose tokens are valid for ttl
+func NewSessionSigner(key []byte, ttl time.Duration) *SessionSigner {
+	return &SessionSigner{key: key, ttl: ttl, now: time.Now}
+}
+
+// NewSessionKey returns a random signing key, for when none is configured
+// Tokens signed with it stop verifying once the process restarts.
+func NewSessionKey() ([]byte, error) {
+	key := make([]byte, sessionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Issue returns a session token for a team, and when it expires
+func (s *SessionSigner) Issue(eventID, userID int) (string, time.Time) {
+	return s.issue(Session{EventID: eventID, UserID: userID})
+}
+
+// IssueAdmin returns an admin session token for an event, and when it expires
+func (s *SessionSigner) IssueAdmin(eventID int) (string, time.Time) {
+	return s.issue(Session{EventID: eventID, Admin: true})
+}
+
+// IssueSpectator returns a spectator session token for an event, and when it expires
+func (s *SessionSigner) IssueSpectator(eventID int) (string, time.Time) {
+	return s.issue(Session{EventID: eventID, Spectator: true})
+}
+
+func (s *SessionSigner) issue(session Session) (string, time.Time) {
+	expiresAt := s.now().Add(s.ttl).Truncate(time.Second)
+	role := roleTeam
+	switch {
+	case session.Admin:
+		role = roleAdmin
+	case session.Spectator:
+		role = roleSpectator
+	}
+	payload := base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%d:%d:%d", session.EventID, session.UserID, role, expiresAt.Unix()))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload)), expiresAt
+}
+
+// Verify checks a session token's signature and expiry and returns the session it carries
+func (s *SessionSigner) Verify(token string) (Session, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return Session{}, ErrInvalidSession
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.sign(payload)) {
+		return Session{}, ErrInvalidSession
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Session{}, ErrInvalidSession
+	}
+	var session Session
+	var role int
+	var expires int64
+	if _, err := fmt.Sscanf(string(raw), "%d:%d:%d:%d", &session.EventID, &session.UserID, &role, &expires); err != nil {
+		return Session{}, ErrInvalidSession
+	}
+	switch role {
+	case roleTeam:
+	case roleAdmin:
+		session.Admin = true
+	case roleSpectator:
+		session.Spectator = true
+	default:
+		return Session{}, ErrInvalidSession
+	}
+	session.ExpiresAt = time.Unix(expires, 0)
+	if !s.now().Before(session.ExpiresAt) {
+		return Session{}, ErrInvalidSession
+	}
+	return session, nil
+}
+
+// VerifySession checks a session token and returns who it identifies (implements draft.SessionVerifier)
+func (s *SessionSigner) VerifySession(token string) (eventID, userID int, admin, spectator bool, err error) {
+	session, err := s.Verify(token)
+	if err != nil {
+		return 0, 0, false, false, err
+	}
+	return session.EventID, session.UserID, session.Admin, session.Spectator, nil
+}
+
+func (s *SessionSigner) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
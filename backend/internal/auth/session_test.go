@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	signer := NewSessionSigner([]byte("test-key"), time.Hour)
+
+	token, expiresAt := signer.Issue(3, 42)
+	session, err := signer.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if session.EventID != 3 || session.UserID != 42 || session.Admin || !session.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("unexpected session %+v", session)
+	}
+
+	token, _ = signer.IssueAdmin(3)
+	if session, err = signer.Verify(token); err != nil || !session.Admin || session.Spectator || session.UserID != 0 {
+		t.Fatalf("unexpected admin session %+v, err %v", session, err)
+	}
+
+	token, _ = signer.IssueSpectator(3)
+	if session, err = signer.Verify(token); err != nil || session.Admin || !session.Spectator || session.UserID != 0 {
+		t.Fatalf("unexpected spectator session %+v, err %v", session, err)
+	}
+}
+
+func TestSessionRejectsForgedTokens(t *testing.T) {
+	signer := NewSessionSigner([]byte("test-key"), time.Hour)
+	token, _ := signer.Issue(3, 42)
+	payload, sig, _ := strings.Cut(token, ".")
+
+	// Another team's payload with this team's signature
+	other, _ := signer.Issue(3, 7)
+	otherPayload, _, _ := strings.Cut(other, ".")
+
+	for name, forged := range map[string]string{
+		"swapped payload": otherPayload + "." + sig,
+		"other key":       must(NewSessionSigner([]byte("other-key"), time.Hour).Issue(3, 42)),
+		"no signature":    payload,
+		"empty":           "",
+	} {
+		if _, err := signer.Verify(forged); !errors.Is(err, ErrInvalidSession) {
+			t.Errorf("%s: expected ErrInvalidSession, got %v", name, err)
+		}
+	}
+}
+
+func TestSessionExpires(t *testing.T) {
+	signer := NewSessionSigner([]byte("test-key"), time.Hour)
+	now := time.Now()
+	signer.now = func() time.Time { return now }
+	token, _ := signer.Issue(3, 42)
+
+	signer.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if _, err := signer.Verify(token); !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("expected an expired token to be rejected, got %v", err)
+	}
+}
+
+func must(token string, _ time.Time) string {
+	return token
+}
@@ -0,0 +1,346 @@
+package draft
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+const (
+	chatHistorySize = 50               // Recent messages kept for the reconnect snapshot
+	chatRateLimit   = 5                // Messages a user may send per chatRateWindow
+	chatRateWindow  = 10 * time.Second // Sliding window for the chat rate limit
+)
+
+// ChatStore defines the interface for persisting chat messages
+type ChatStore interface {
+	SaveChatMessage(ctx context.Context, eventID, userID int, body string) (*models.ChatMessage, error)
+	DeleteChatMessage(ctx context.Context, eventID, messageID int) error
+	GetRecentChatMessages(ctx context.Context, eventID, limit int) ([]models.ChatMessage, error)
+	SetChatMuted(ctx context.Context, eventID, userID int, muted bool) error
+	GetChatMutes(ctx context.Context, eventID int) ([]int, error)
+}
+
+// CommissionerChecker defines the interface for checking moderation rights
+type CommissionerChecker interface {
+	IsCommissioner(ctx context.Context, eventID, userID int) (bool, error)
+}
+
+// ChatEntry is a chat message as sent to clients
+type ChatEntry struct {
+	ID        int    `json:"id"`
+	UserID    int    `json:"userID"`
+	Text      string `json:"text"`
+	CreatedAt int64  `json:"createdAt"` // Unix timestamp
+}
+
+// chatRoom holds the in-memory chat state for a draft room
+type chatRoom struct {
+	mu     sync.Mutex
+	recent []ChatEntry         // Last chatHistorySize messages, oldest first
+	muted  map[int]bool        // Users muted by the commissioner
+	sent   map[int][]time.Time // Send times per user within the rate window
+}
+
+func newChatRoom(history []models.ChatMessage, muted []int) *chatRoom {
+	c := &chatRoom{
+		muted: make(map[int]bool),
+		sent:  make(map[int][]time.Time),
+	}
+	for _, msg := range history {
+		c.recent = append(c.recent, chatEntryFromModel(msg))
+	}
+	for _, userID := range muted {
+		c.muted[userID] = true
+	}
+	return c
+}
+
+// allow records a send attempt and reports whether the user is within the rate limit
+func (c *chatRoom) allow(userID int, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cutoff := now.Add(-chatRateWindow)
+	times := c.sent[userID][:0]
+	for _, t := range c.sent[userID] {
+		if t.After(cutoff) {
+			times = append(times, t)
+		}
+	}
+
+	if len(times) >= chatRateLimit {
+		c.sent[userID] = times
+		return false
+	}
+	c.sent[userID] = append(times, now)
+	return true
+}
+
+// isMuted reports whether a user is muted
+func (c *chatRoom) isMuted(userID int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.muted[userID]
+}
+
+// setMuted mutes or unmutes a user
+func (c *chatRoom) setMuted(userID int, muted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if muted {
+		c.muted[userID] = true
+	} else {
+		delete(c.muted, userID)
+	}
+}
+
+// add appends a message to the recent history
+func (c *chatRoom) add(entry ChatEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recent = append(c.recent, entry)
+	if len(c.recent) > chatHistorySize {
+		c.recent = c.recent[len(c.recent)-chatHistorySize:]
+	}
+}
+
+// remove drops a message from the recent history
+func (c *chatRoom) remove(messageID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, entry := range c.recent {
+		if entry.ID == messageID {
+			c.recent = append(c.recent[:i], c.recent[i+1:]...)
+			return
+		}
+	}
+}
+
+// history returns a copy of the recent messages
+func (c *chatRoom) history() []ChatEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	history := make([]ChatEntry, len(c.recent))
+	copy(history, c.recent)
+	return history
+}
+
+func chatEntryFromModel(msg models.ChatMessage) ChatEntry {
+	return ChatEntry{
+		ID:        msg.ID,
+		UserID:    msg.UserID,
+		Text:      msg.Body,
+		CreatedAt: msg.CreatedAt.Unix(),
+	}
+}
+
+// SendChatMessage represents the payload for posting a chat message
+type SendChatMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text" schema:"minLength=1,maxLength=500"`
+}
+
+// MuteUserMessage represents the payload for muting or unmuting a user (commissioner only)
+type MuteUserMessage struct {
+	Type   string `json:"type"`
+	UserID int    `json:"userID" schema:"minimum=1"`
+	Muted  bool   `json:"muted"`
+}
+
+// DeleteChatMessageMessage represents the payload for deleting a chat message (commissioner only)
+type DeleteChatMessageMessage struct {
+	Type      string `json:"type"`
+	MessageID int    `json:"messageID" schema:"minimum=1"`
+}
+
+// ChatMessage is broadcast when a chat message is posted
+type ChatMessage struct {
+	Type string `json:"type"`
+	ChatEntry
+}
+
+// ChatMessageDeletedMessage is broadcast when the commissioner deletes a chat message
+type ChatMessageDeletedMessage struct {
+	Type      string `json:"type"`
+	MessageID int    `json:"messageID"`
+}
+
+// UserMutedMessage is broadcast when the commissioner mutes or unmutes a user
+type UserMutedMessage struct {
+	Type   string `json:"type"`
+	UserID int    `json:"userID"`
+	Muted  bool   `json:"muted"`
+}
+
+// currentChat returns the room's event ID and chat state, or false if there is no room
+func (s *DraftService) currentChat() (int, *chatRoom, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.state == nil || s.chat == nil {
+		return 0, nil, false
+	}
+	return s.state.GetEventID(), s.chat, true
+}
+
+// handleChatMessage persists a chat message and broadcasts it to the room
+func (s *DraftService) handleChatMessage(c *Client, data []byte) {
+	eventID, chat, ok := s.currentChat()
+	if !ok {
+		c.SendError("no draft room created")
+		return
+	}
+
+	if c.UserID == 0 {
+		c.SendError("chat requires a team connection")
+		return
+	}
+
+	var msg SendChatMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.SendError("invalid chat_message message format")
+		return
+	}
+
+	text := strings.TrimSpace(msg.Text)
+	if text == "" {
+		c.SendError("chat message cannot be empty")
+		return
+	}
+
+	if chat.isMuted(c.UserID) {
+		c.SendError("you have been muted by the commissioner")
+		return
+	}
+
+	if !chat.allow(c.UserID, time.Now()) {
+		c.SendError("slow down - too many chat messages")
+		return
+	}
+
+	saved, err := s.chatStore.SaveChatMessage(context.Background(), eventID, c.UserID, text)
+	if err != nil {
+		slog.ErrorContext(c.ctx, "Failed to persist chat message", "err", err)
+		c.SendError("failed to send chat message")
+		return
+	}
+
+	entry := chatEntryFromModel(*saved)
+	chat.add(entry)
+	s.manager.Broadcast(encodeMessage(ChatMessage{
+		Type:      MsgTypeChatMessage,
+		ChatEntry: entry,
+	}))
+}
+
+// handleMuteUser mutes or unmutes a user's chat (commissioner only)
+func (s *DraftService) handleMuteUser(c *Client, data []byte) {
+	eventID, chat, ok := s.currentChat()
+	if !ok {
+		c.SendError("no draft room created")
+		return
+	}
+
+	if !s.requireCommissioner(c, eventID) {
+		return
+	}
+
+	var msg MuteUserMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.SendError("invalid mute_user message format")
+		return
+	}
+
+	users, err := s.teams.GetByEvent(context.Background(), eventID)
+	if err != nil {
+		slog.ErrorContext(c.ctx, "Failed to load teams for mute", "err", err)
+		c.SendError("failed to update mute")
+		return
+	}
+	if !slices.ContainsFunc(users, func(u models.User) bool { return u.ID == msg.UserID }) {
+		c.SendError("user is not a team in this draft")
+		return
+	}
+
+	// Saved first so the mute outlives the room
+	if err := s.chatStore.SetChatMuted(context.Background(), eventID, msg.UserID, msg.Muted); err != nil {
+		slog.ErrorContext(c.ctx, "Failed to persist chat mute", "err", err, "target_user_id", msg.UserID)
+		c.SendError("failed to update mute")
+		return
+	}
+
+	chat.setMuted(msg.UserID, msg.Muted)
+	s.manager.Broadcast(encodeMessage(UserMutedMessage{
+		Type:   MsgTypeUserMuted,
+		UserID: msg.UserID,
+		Muted:  msg.Muted,
+	}))
+
+	slog.InfoContext(c.ctx, "Commissioner set mute", "target_user_id", msg.UserID, "muted", msg.Muted)
+}
+
+// handleDeleteChatMessage deletes a chat message (commissioner only)
+func (s *DraftService) handleDeleteChatMessage(c *Client, data []byte) {
+	eventID, chat, ok := s.currentChat()
+	if !ok {
+		c.SendError("no draft room created")
+		return
+	}
+
+	if !s.requireCommissioner(c, eventID) {
+		return
+	}
+
+	var msg DeleteChatMessageMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.SendError("invalid delete_chat_message message format")
+		return
+	}
+
+	if err := s.chatStore.DeleteChatMessage(context.Background(), eventID, msg.MessageID); err != nil {
+		c.SendError("chat message not found")
+		return
+	}
+
+	chat.remove(msg.MessageID)
+	s.manager.Broadcast(encodeMessage(ChatMessageDeletedMessage{
+		Type:      MsgTypeChatMessageDeleted,
+		MessageID: msg.MessageID,
+	}))
+
+	slog.InfoContext(c.ctx, "Commissioner deleted chat message", "message_id", msg.MessageID)
+}
+
+// requireCommissioner sends an error and returns false unless the client is the event's commissioner or an admin
+func (s *DraftService) requireCommissioner(c *Client, eventID int) bool {
+	return s.requireCommissionerOr(c, eventID, "only the commissioner can do that")
+}
+
+// requireCommissionerOr is requireCommissioner with the error to send when the client is neither
+func (s *DraftService) requireCommissionerOr(c *Client, eventID int, denied string) bool {
+	if c.Admin {
+		return true
+	}
+	if c.UserID == 0 {
+		c.SendError(denied)
+		return false
+	}
+
+	ok, err := s.commissioners.IsCommissioner(context.Background(), eventID, c.UserID)
+	if err != nil {
+		slog.ErrorContext(c.ctx, "Failed to check commissioner", "err", err)
+		c.SendError("failed to check permissions")
+		return false
+	}
+	if !ok {
+		c.SendError(denied)
+		return false
+	}
+	return true
+}
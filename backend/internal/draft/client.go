@@ -0,0 +1,159 @@
+package draft
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// sendBufferSize is the number of outgoing messages a client may have queued
+const sendBufferSize = 256
+
+// Client represents a WebSocket client connection
+type Client struct {
+	Conn            *websocket.Conn
+	Send            chan []byte // Buffered channel for outgoing messages
+	ProtocolVersion int         // Protocol version negotiated during the handshake
+	UserID          int         // Team this connection belongs to, from its session token (0 for admins and spectators)
+	Admin           bool        // Connected with an admin session; has every commissioner power
+	Spectator       bool        // Spectators receive broadcasts but cannot mutate the draft
+
+	// ctx is the connection's context; its log records carry the connection ID, event ID and user ID
+	ctx context.Context
+
+	mu            sync.Mutex // protects the fields below
+	id            uint64     // Assigned by the manager on registration
+	connectedAt   time.Time
+	closed        bool                 // Send has been closed
+	closeCode     websocket.StatusCode // Close frame sent once Send drains
+	closeReason   string
+	maxQueueDepth int         // High-water mark of queued messages
+	coalesced     int         // Times the queue was replaced by a snapshot
+	coalescedAt   []time.Time // Recent coalesce times, for the eviction policy
+}
+
+func newClient(ctx context.Context, conn *websocket.Conn, version, userID int, spectator bool) *Client {
+	return &Client{
+		ctx:             ctx,
+		Conn:            conn,
+		Send:            make(chan []byte, sendBufferSize),
+		ProtocolVersion: version,
+		UserID:          userID,
+		Spectator:       spectator,
+		connectedAt:     time.Now(),
+		closeCode:       websocket.StatusNormalClosure,
+		closeReason:     "connection closed",
+	}
+}
+
+// SendError sends an error message to this client
+func (c *Client) SendError(message string) {
+	c.enqueue(encodeMessage(ErrorMessage{
+		Type:  MsgTypeError,
+		Error: message,
+	}))
+}
+
+// enqueue queues a message without blocking
+// Returns false if the client is closed or its queue is full
+func (c *Client) enqueue(msg []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+
+	select {
+	case c.Send <- msg:
+		c.maxQueueDepth = max(c.maxQueueDepth, len(c.Send))
+		return true
+	default:
+		return false
+	}
+}
+
+// coalesce drops every queued message and queues the snapshot in their place
+// Returns the number of recent coalesces within the window, including this one
+func (c *Client) coalesce(snapshot []byte, now time.Time, window time.Duration) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0
+	}
+
+	for drained := false; !drained; {
+		select {
+		case <-c.Send:
+		default:
+			drained = true
+		}
+	}
+	if snapshot != nil {
+		c.Send <- snapshot
+	}
+
+	c.coalesced++
+	cutoff := now.Add(-window)
+	recent := c.coalescedAt[:0]
+	for _, t := range c.coalescedAt {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	c.coalescedAt = append(recent, now)
+	return len(c.coalescedAt)
+}
+
+// close closes the Send channel; writePump then sends a close frame with the given status
+// Safe to call more than once - only the first call takes effect
+func (c *Client) close(code websocket.StatusCode, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.closeCode = code
+	c.closeReason = reason
+	close(c.Send)
+}
+
+// closeStatus returns the status for the close frame
+func (c *Client) closeStatus() (websocket.StatusCode, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeCode, c.closeReason
+}
+
+// ClientStats reports the outgoing queue state of one connection
+type ClientStats struct {
+	ID            uint64    `json:"id"`
+	UserID        int       `json:"userID,omitempty"`
+	Spectator     bool      `json:"spectator"`
+	ConnectedAt   time.Time `json:"connectedAt"`
+	QueueDepth    int       `json:"queueDepth"`
+	QueueCapacity int       `json:"queueCapacity"`
+	MaxQueueDepth int       `json:"maxQueueDepth"`
+	Coalesced     int       `json:"coalesced"`
+}
+
+// stats returns a snapshot of the client's queue statistics
+func (c *Client) stats() ClientStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ClientStats{
+		ID:            c.id,
+		UserID:        c.UserID,
+		Spectator:     c.Spectator,
+		ConnectedAt:   c.connectedAt,
+		QueueDepth:    len(c.Send),
+		QueueCapacity: cap(c.Send),
+		MaxQueueDepth: c.maxQueueDepth,
+		Coalesced:     c.coalesced,
+	}
+}
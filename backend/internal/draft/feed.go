@@ -0,0 +1,116 @@
+package draft
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/logging"
+)
+
+// feedKeepAlive is how often an idle SSE stream gets a comment line to keep proxies from closing it
+const feedKeepAlive = 15 * time.Second
+
+// HandleDraftFeed streams the room's broadcasts as Server-Sent Events
+// Handles GET /events/{id}/draft-feed - a read-only alternative to the WebSocket for watchers
+// Any session token for the event (team, admin or spectator) is accepted, as ?token=
+func (s *DraftService) HandleDraftFeed(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "Invalid event ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := s.verifySession(w, r, eventID); !ok {
+		return
+	}
+
+	room := s.GetRoom()
+	if room == nil || room.GetEventID() != eventID {
+		http.Error(w, `{"error": "No draft room for this event"}`, http.StatusNotFound)
+		return
+	}
+
+	lastID, resume := parseLastEventID(r)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	if err := rc.Flush(); err != nil {
+		slog.ErrorContext(r.Context(), "Draft feed does not support streaming", "err", err)
+		return
+	}
+
+	watcher := &Watcher{Send: make(chan FeedEvent, historySize)}
+	s.manager.Watch(watcher, lastID, resume, s.snapshotMessage)
+	defer s.manager.Unwatch(watcher)
+
+	ctx := logging.With(r.Context(), "event_id", eventID)
+	slog.InfoContext(ctx, "Draft feed opened", "resume", resume, "last_id", lastID)
+
+	ticker := time.NewTicker(feedKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			slog.InfoContext(ctx, "Draft feed closed")
+			return
+		case event, ok := <-watcher.Send:
+			if !ok {
+				return // Dropped by the manager
+			}
+			if err := writeFeedEvent(w, event); err != nil {
+				slog.WarnContext(ctx, "Draft feed write error", "err", err)
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// parseLastEventID reads the resume point from the Last-Event-ID header
+// A lastEventID query parameter is also accepted for clients that cannot set headers
+func parseLastEventID(r *http.Request) (uint64, bool) {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("lastEventID")
+	}
+	if raw == "" {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// writeFeedEvent writes one broadcast in SSE format, using the message type as the event name
+func writeFeedEvent(w http.ResponseWriter, event FeedEvent) error {
+	var msg struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(event.Data, &msg); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, msg.Type, event.Data)
+	return err
+}
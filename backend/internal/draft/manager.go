@@ -1,23 +1,77 @@
 package draft
 
 import (
-	"log"
+	"cmp"
+	"log/slog"
+	"slices"
 	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// historySize is how many recent broadcasts are kept for Last-Event-ID resume
+const historySize = 256
+
+// Slow-consumer policy: once a client's queue reaches coalesceHighWater, its pending
+// messages are replaced by a single draft_state snapshot. A client that needs more than
+// maxCoalesces within coalesceWindow is evicted with a close frame and must reconnect.
+const (
+	coalesceHighWater = sendBufferSize * 3 / 4
+	coalesceWindow    = time.Minute
+	maxCoalesces      = 3
 )
 
+// FeedEvent is a broadcast message tagged with its sequence number
+type FeedEvent struct {
+	ID   uint64
+	Data []byte
+}
+
+// Watcher is a read-only subscriber to the broadcast feed (e.g. an SSE connection)
+type Watcher struct {
+	Send chan FeedEvent // Buffered channel for outgoing events
+}
+
+// watchRequest asks the manager to add a watcher, replaying missed events or sending a snapshot
+type watchRequest struct {
+	watcher  *Watcher
+	lastID   uint64        // Last event ID the watcher saw
+	resume   bool          // Whether lastID was supplied
+	snapshot func() []byte // Returns the current draft_state message, or nil if there is none
+}
+
+// registration asks the manager to add a client and reports whether it was accepted
+type registration struct {
+	client         *Client
+	spectatorLimit int // Spectators allowed, checked when client is a spectator
+	accepted       chan bool
+}
+
 type Manager struct {
-	clients    map[*Client]bool
-	mu         sync.Mutex
-	register   chan *Client
-	unregister chan *Client
-	broadcast  chan []byte // Channel for broadcasting messages to clients
+	clients      map[*Client]bool
+	watchers     map[*Watcher]bool
+	mu           sync.Mutex    // protects clients, watchers and evicted
+	evicted      int           // Clients evicted for being too slow
+	nextClientID uint64        // Last ID assigned to a registered client
+	snapshot     func() []byte // Returns the current draft_state message, or nil if there is none
+	register     chan registration
+	unregister   chan *Client
+	watch        chan watchRequest
+	unwatch      chan *Watcher
+	broadcast    chan []byte // Channel for broadcasting messages to clients
+	seq          uint64      // Sequence number of the last broadcast
+	history      []FeedEvent // Recent broadcasts, oldest first
 }
 
 func NewManager() *Manager {
 	return &Manager{
 		clients:    make(map[*Client]bool),
-		register:   make(chan *Client),
+		watchers:   make(map[*Watcher]bool),
+		register:   make(chan registration),
 		unregister: make(chan *Client),
+		watch:      make(chan watchRequest),
+		unwatch:    make(chan *Watcher),
 		broadcast:  make(chan []byte),
 	}
 }
@@ -25,45 +79,272 @@ func NewManager() *Manager {
 func (m *Manager) Run() {
 	for {
 		select {
-		case client := <-m.register:
-			m.clients[client] = true
-			log.Println("Connected new client")
+		case reg := <-m.register:
+			m.mu.Lock()
+			if reg.client.Spectator && m.countSpectators() >= reg.spectatorLimit {
+				m.mu.Unlock()
+				reg.accepted <- false
+				slog.InfoContext(reg.client.ctx, "Refused spectator (limit reached)")
+				continue
+			}
+			m.nextClientID++
+			reg.client.mu.Lock()
+			reg.client.id = m.nextClientID
+			reg.client.mu.Unlock()
+			m.clients[reg.client] = true
+			m.mu.Unlock()
+			reg.accepted <- true
+			slog.InfoContext(reg.client.ctx, "Registered client", "client_id", reg.client.id)
+			m.deliver(m.record(m.presenceMessage()))
 		case client := <-m.unregister:
-			if m.clients[client] {
+			m.mu.Lock()
+			removed := m.clients[client]
+			if removed {
 				delete(m.clients, client)
-				close(client.Send)
-				log.Println("Disconnected client")
+				client.close(websocket.StatusNormalClosure, "connection closed")
+				slog.InfoContext(client.ctx, "Unregistered client")
+			}
+			m.mu.Unlock()
+			if removed {
+				m.deliver(m.record(m.presenceMessage()))
+			}
+		case req := <-m.watch:
+			m.addWatcher(req)
+		case watcher := <-m.unwatch:
+			m.mu.Lock()
+			removed := m.watchers[watcher]
+			if removed {
+				delete(m.watchers, watcher)
+				close(watcher.Send)
+				slog.Info("Disconnected watcher")
+			}
+			m.mu.Unlock()
+			if removed {
+				m.deliver(m.record(m.presenceMessage()))
 			}
 		case message := <-m.broadcast:
-			for client := range m.clients {
-				select {
-				case client.Send <- message:
-					// Message sent successfully
-				default:
-					// Channel full or closed - remove dead client
-					close(client.Send)
-					delete(m.clients, client)
-					log.Println("Removed dead client (send failed)")
-				}
+			m.deliver(m.record(message))
+		}
+	}
+}
+
+// deliver sends a recorded broadcast to every client and watcher
+func (m *Manager) deliver(event FeedEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var snapshot []byte
+	snapshotTaken := false
+	for client := range m.clients {
+		if len(client.Send) >= coalesceHighWater {
+			// Client is falling behind - the snapshot supersedes everything queued, including this message
+			if !snapshotTaken {
+				snapshot = m.snapshot()
+				snapshotTaken = true
 			}
+			m.coalesceOrEvict(client, snapshot)
+			continue
 		}
+
+		if !client.enqueue(event.Data) {
+			// Channel full or closed - remove dead client
+			m.evict(client, "send failed")
+		}
+	}
+	for watcher := range m.watchers {
+		select {
+		case watcher.Send <- event:
+		default:
+			close(watcher.Send)
+			delete(m.watchers, watcher)
+			slog.Warn("Removed dead watcher (send failed)")
+		}
+	}
+}
+
+// coalesceOrEvict replaces a lagging client's queue with a snapshot, or evicts it if it keeps lagging
+// Must be called while holding the mutex
+func (m *Manager) coalesceOrEvict(client *Client, snapshot []byte) {
+	if snapshot == nil {
+		// Nothing to resync from (draft not started) - the client must reconnect
+		m.evict(client, "queue full")
+		return
+	}
+
+	if recent := client.coalesce(snapshot, time.Now(), coalesceWindow); recent > maxCoalesces {
+		m.evict(client, "repeatedly fell behind")
+		return
 	}
+	slog.WarnContext(client.ctx, "Coalesced queue of slow client into a snapshot")
+}
+
+// evict removes a client and closes it with a reason so it knows to reconnect
+// Must be called while holding the mutex
+func (m *Manager) evict(client *Client, cause string) {
+	delete(m.clients, client)
+	client.close(websocket.StatusTryAgainLater, "client too slow - reconnect to resync")
+	m.evicted++
+	slog.WarnContext(client.ctx, "Evicted slow client", "cause", cause)
 }
 
-func (m *Manager) Register(client *Client) {
-	m.register <- client
+// presenceMessage builds a presence message from the current connections
+func (m *Manager) presenceMessage() []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	connected := make(map[int]bool)
+	for client := range m.clients {
+		if client.UserID != 0 {
+			connected[client.UserID] = true
+		}
+	}
+	userIDs := make([]int, 0, len(connected))
+	for id := range connected {
+		userIDs = append(userIDs, id)
+	}
+	slices.Sort(userIDs)
+
+	return encodeMessage(PresenceMessage{
+		Type:           MsgTypePresence,
+		ConnectedUsers: userIDs,
+		Spectators:     m.countSpectators(),
+		Watchers:       len(m.watchers),
+	})
+}
+
+// countSpectators returns the number of spectator clients
+// Must be called while holding the mutex
+func (m *Manager) countSpectators() int {
+	count := 0
+	for client := range m.clients {
+		if client.Spectator {
+			count++
+		}
+	}
+	return count
+}
+
+// record assigns the next sequence number to a broadcast and keeps it for resume
+func (m *Manager) record(message []byte) FeedEvent {
+	m.seq++
+	event := FeedEvent{ID: m.seq, Data: message}
+	m.history = append(m.history, event)
+	if len(m.history) > historySize {
+		m.history = m.history[len(m.history)-historySize:]
+	}
+	return event
+}
+
+// canResume reports whether every broadcast after lastID is still in history
+func (m *Manager) canResume(lastID uint64) bool {
+	if lastID > m.seq {
+		return false
+	}
+	if len(m.history) == 0 {
+		return lastID == m.seq
+	}
+	return lastID+1 >= m.history[0].ID
+}
+
+// addWatcher catches a new watcher up and starts delivering broadcasts to it
+// Runs on the manager goroutine so no broadcast can slip in between catch-up and registration
+func (m *Manager) addWatcher(req watchRequest) {
+	if req.resume && m.canResume(req.lastID) {
+		for _, event := range m.history {
+			if event.ID > req.lastID {
+				req.watcher.Send <- event
+			}
+		}
+	} else if snapshot := req.snapshot(); snapshot != nil {
+		req.watcher.Send <- FeedEvent{ID: m.seq, Data: snapshot}
+	}
+
+	m.mu.Lock()
+	m.watchers[req.watcher] = true
+	m.mu.Unlock()
+	slog.Info("Connected new watcher")
+	m.deliver(m.record(m.presenceMessage()))
+}
+
+// Register adds a client to the room
+// Returns false if the client is a spectator and spectatorLimit spectators are already connected
+func (m *Manager) Register(client *Client, spectatorLimit int) bool {
+	reg := registration{client: client, spectatorLimit: spectatorLimit, accepted: make(chan bool, 1)}
+	m.register <- reg
+	return <-reg.accepted
 }
 
 func (m *Manager) Unregister(client *Client) {
 	m.unregister <- client
 }
 
+// Watch subscribes a watcher to the broadcast feed
+// If resume is set and lastID is still in history, missed events are replayed;
+// otherwise the watcher receives the current snapshot first
+func (m *Manager) Watch(watcher *Watcher, lastID uint64, resume bool, snapshot func() []byte) {
+	m.watch <- watchRequest{watcher: watcher, lastID: lastID, resume: resume, snapshot: snapshot}
+}
+
+// Unwatch removes a watcher from the broadcast feed
+func (m *Manager) Unwatch(watcher *Watcher) {
+	m.unwatch <- watcher
+}
+
 func (m *Manager) Broadcast(message []byte) {
 	m.broadcast <- message
 }
 
+// SendToUser queues a message for a team's own connections only
+// It is not part of the broadcast feed, so spectators and feed watchers never see it
+func (m *Manager) SendToUser(userID int, message []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for client := range m.clients {
+		if client.UserID == userID && !client.Spectator {
+			client.enqueue(message)
+		}
+	}
+}
+
 func (m *Manager) GetClientCount() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	return len(m.clients)
 }
+
+// SetSnapshotSource sets the function used to build the snapshot that replaces a slow client's queue
+func (m *Manager) SetSnapshotSource(snapshot func() []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshot = snapshot
+}
+
+// GetClientStats returns queue statistics for every connected client, and the number of evictions so far
+func (m *Manager) GetClientStats() ([]ClientStats, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]ClientStats, 0, len(m.clients))
+	for client := range m.clients {
+		stats = append(stats, client.stats())
+	}
+	slices.SortFunc(stats, func(a, b ClientStats) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return stats, m.evicted
+}
+
+// GetSpectatorCount returns the number of connected spectators
+func (m *Manager) GetSpectatorCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.countSpectators()
+}
+
+// GetWatcherCount returns the number of read-only feed subscribers
+func (m *Manager) GetWatcherCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.watchers)
+}
@@ -3,22 +3,33 @@ package draft
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 	"time"
 
+	"github.com/sblackwood23/fantasy-draft-app/internal/logging"
 	"github.com/sblackwood23/fantasy-draft-app/internal/models"
 )
 
 // Incoming message types (from client)
 const (
-	MsgTypeStartDraft = "start_draft"
-	MsgTypeMakePick   = "make_pick"
-	MsgTypePauseDraft = "pause_draft"
+	MsgTypeStartDraft  = "start_draft"
+	MsgTypeMakePick    = "make_pick"
+	MsgTypePauseDraft  = "pause_draft"
 	MsgTypeResumeDraft = "resume_draft"
+
+	MsgTypeMuteUser          = "mute_user"           // Commissioner only
+	MsgTypeDeleteChatMessage = "delete_chat_message" // Commissioner only
+	MsgTypeResetTeamSecret   = "reset_team_secret"   // Commissioner only
+)
+
+// Bidirectional message types
+const (
+	MsgTypeChatMessage = "chat_message" // Client posts text; server broadcasts the saved message
 )
 
 // Outgoing message types (to client)
 const (
+	MsgTypeWelcome        = "welcome" // Sent once after the handshake with the negotiated protocol version
 	MsgTypeDraftStarted   = "draft_started"
 	MsgTypeDraftPaused    = "draft_paused"
 	MsgTypeDraftResumed   = "draft_resumed"
@@ -26,23 +37,75 @@ const (
 	MsgTypeDraftState     = "draft_state" // Sent to reconnecting clients
 	MsgTypePickMade       = "pick_made"
 	MsgTypeTurnChanged    = "turn_changed"
-	MsgTypeError          = "error"
+	MsgTypePresence       = "presence"
+
+	MsgTypeChatMessageDeleted = "chat_message_deleted"
+	MsgTypeUserMuted          = "user_muted"
+	MsgTypeTeamSecretReset    = "team_secret_reset" // Sent to the commissioner who reset it
+	MsgTypeOnDeck             = "on_deck"           // Sent to the team with the next pick
+	MsgTypeOnTheClock         = "on_the_clock"      // Sent to the team whose turn it is
+	MsgTypeError              = "error"
 )
 
+// PublishedMessageTypes are the draft messages published outside the room (e.g. to webhooks)
+var PublishedMessageTypes = []string{
+	MsgTypeDraftStarted,
+	MsgTypePickMade,
+	MsgTypeTurnChanged,
+	MsgTypeDraftPaused,
+	MsgTypeDraftResumed,
+	MsgTypeDraftCompleted,
+}
+
+// NotificationMessageTypes are the turn notifications a team can opt in to receiving outside the room
+var NotificationMessageTypes = []string{
+	MsgTypeOnDeck,
+	MsgTypeOnTheClock,
+}
+
+// Publishing runs behind the broadcast bridge, so a slow database never holds up the room
+const (
+	publishTimeout   = 5 * time.Second // Bounds queueing one published message
+	publishQueueSize = 1024            // Broadcast messages that may wait to be published
+)
+
+// isMutating reports whether a message type changes draft state
+// Spectators are refused every mutating message
+func isMutating(msgType string) bool {
+	switch msgType {
+	case MsgTypeStartDraft, MsgTypeMakePick, MsgTypePauseDraft, MsgTypeResumeDraft,
+		MsgTypeChatMessage, MsgTypeMuteUser, MsgTypeDeleteChatMessage, MsgTypeResetTeamSecret:
+		return true
+	default:
+		return false
+	}
+}
+
 // StartDraftMessage represents the payload for starting a draft
 // Note: availablePlayers comes from CreateRoom (HTTP), not this message
 type StartDraftMessage struct {
 	Type          string `json:"type"`
-	PickOrder     []int  `json:"pickOrder"`
-	TotalRounds   int    `json:"totalRounds"`
-	TimerDuration int    `json:"timerDuration"` // in seconds
+	PickOrder     []int  `json:"pickOrder" schema:"minItems=1"`
+	TotalRounds   int    `json:"totalRounds" schema:"minimum=1"`
+	TimerDuration int    `json:"timerDuration" schema:"minimum=1"` // in seconds
 }
 
 // MakePickMessage represents the payload for making a pick
 type MakePickMessage struct {
 	Type     string `json:"type"`
-	UserID   int    `json:"userID"`
-	PlayerID int    `json:"playerID"`
+	UserID   int    `json:"userID" schema:"minimum=1"`
+	PlayerID int    `json:"playerID" schema:"minimum=1"`
+	Keeper   bool   `json:"keeper,omitempty"` // The team keeps the player with this pick (commissioner only)
+}
+
+// PauseDraftMessage represents the payload for pausing a draft
+type PauseDraftMessage struct {
+	Type string `json:"type"`
+}
+
+// ResumeDraftMessage represents the payload for resuming a paused draft
+type ResumeDraftMessage struct {
+	Type string `json:"type"`
 }
 
 // handleStartDraft initializes and starts the draft
@@ -72,22 +135,24 @@ func (s *DraftService) handleStartDraft(c *Client, data []byte) {
 	}
 	s.mu.Unlock()
 
+	s.loadTeams(state)
+
 	// Update event status to in_progress
 	eventID := state.GetEventID()
 	if err := s.eventUpdater.UpdateStatus(context.Background(), eventID, models.EventStatusInProgress); err != nil {
-		log.Printf("Failed to update event status to in_progress: %v", err)
+		slog.ErrorContext(c.ctx, "Failed to update event status to in_progress", "err", err)
 	}
 
 	// Start the bridge goroutine to broadcast outgoing messages
 	go s.startOutgoingBridge(state)
 
-	// Start the persistence goroutine to save picks to database
-	go s.startPickPersistence(state)
-
 	// Start the completion handler to update event status when draft ends
 	go s.startCompletionHandler(state)
 
-	log.Printf("Draft started for event %d", eventID)
+	// Start the turn notifier to tell teams when they are on deck or on the clock
+	go s.startTurnNotifier(state)
+
+	slog.InfoContext(c.ctx, "Draft started")
 }
 
 // handleMakePick processes a pick from a user
@@ -107,7 +172,30 @@ func (s *DraftService) handleMakePick(c *Client, data []byte) {
 		return
 	}
 
-	if err := state.MakePick(msg.UserID, msg.PlayerID); err != nil {
+	// Picking for another team, while the draft is paused, or assigning a keeper takes the commissioner or an admin
+	provenance := models.PickProvenanceManual
+	if msg.UserID != c.UserID || state.GetStatus() == StatusPaused || msg.Keeper {
+		denied := "you can only pick for your own team"
+		switch {
+		case msg.Keeper:
+			denied = "only the commissioner can assign a keeper"
+		case msg.UserID == c.UserID:
+			denied = "draft is paused"
+		}
+		if !s.requireCommissionerOr(c, state.GetEventID(), denied) {
+			return
+		}
+		provenance = models.PickProvenanceAdmin
+		if msg.Keeper {
+			provenance = models.PickProvenanceKeeper
+		}
+	}
+
+	// The pick ID correlates the pick's log records from here through saving it
+	ctx := logging.With(c.ctx, "pick_id", logging.NewID())
+	slog.DebugContext(ctx, "Pick received", "pick_user_id", msg.UserID, "player_id", msg.PlayerID, "provenance", provenance)
+
+	if err := state.MakePick(ctx, msg.UserID, msg.PlayerID, provenance); err != nil {
 		c.SendError(err.Error())
 		return
 	}
@@ -129,7 +217,7 @@ func (s *DraftService) handlePauseDraft(c *Client) {
 		return
 	}
 
-	log.Printf("Draft paused for event %d", state.GetEventID())
+	slog.InfoContext(c.ctx, "Draft paused")
 }
 
 // handleResumeDraft resumes a paused draft
@@ -148,36 +236,66 @@ func (s *DraftService) handleResumeDraft(c *Client) {
 		return
 	}
 
-	log.Printf("Draft resumed for event %d", state.GetEventID())
+	slog.InfoContext(c.ctx, "Draft resumed")
 }
 
 // startOutgoingBridge reads from the draft state's outgoing channel and broadcasts to all clients
+// Each message is then handed to the publisher goroutine once the room has it. The bridge never
+// waits on the publisher: if its queue is full the message is dropped from publishing (not from
+// the room), so the outgoing channel, and the draft state writing to it, never back up.
 func (s *DraftService) startOutgoingBridge(state *DraftState) {
+	eventID := state.GetEventID()
+	queue := make(chan []byte, publishQueueSize)
+	defer close(queue)
+	go s.startPublisher(eventID, queue)
+
 	for msg := range state.Outgoing() {
 		s.manager.Broadcast(msg)
+		select {
+		case queue <- msg:
+		default:
+			publishDropped.Inc()
+			slog.Error("Publish queue is full; message not published", "event_id", eventID)
+		}
 	}
 }
 
-// startPickPersistence reads from the draft state's pick results channel and saves to database
-func (s *DraftService) startPickPersistence(state *DraftState) {
-	for pick := range state.PickResults() {
-		ctx := context.Background()
-		if err := s.pickSaver.SavePick(ctx, pick.EventID, pick.UserID, pick.PlayerID, pick.PickNumber, pick.Round); err != nil {
-			log.Printf("Failed to persist pick: %v", err)
-		} else {
-			log.Printf("Persisted pick: event=%d user=%d player=%d pick#=%d round=%d auto=%v",
-				pick.EventID, pick.UserID, pick.PlayerID, pick.PickNumber, pick.Round, pick.AutoDraft)
-		}
+// startPublisher publishes queued broadcast messages in order until the queue is closed
+func (s *DraftService) startPublisher(eventID int, queue <-chan []byte) {
+	for msg := range queue {
+		s.publish(eventID, msg)
 	}
 }
 
-// startCompletionHandler waits for the draft to complete and updates event status
+// publish hands a broadcast draft message to the publisher
+func (s *DraftService) publish(eventID int, msg []byte) {
+	var envelope struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(msg, &envelope); err != nil {
+		slog.Error("Failed to read outgoing message type", "event_id", eventID, "err", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if err := s.publisher.Publish(ctx, eventID, envelope.Type, msg); err != nil {
+		slog.Error("Failed to publish draft message", "event_id", eventID, "type", envelope.Type, "err", err)
+	}
+}
+
+// startCompletionHandler waits for the draft to complete, updates event status and recomputes ADP
 func (s *DraftService) startCompletionHandler(state *DraftState) {
 	<-state.Completed()
 	eventID := state.GetEventID()
 	if err := s.eventUpdater.UpdateStatus(context.Background(), eventID, models.EventStatusCompleted); err != nil {
-		log.Printf("Failed to update event status to completed: %v", err)
-	} else {
-		log.Printf("Event %d marked as completed", eventID)
+		slog.Error("Failed to update event status to completed", "event_id", eventID, "err", err)
+		return
+	}
+	slog.Info("Event marked as completed", "event_id", eventID)
+
+	// Fold this draft into the ADP ranking (only completed events count)
+	if err := s.rankings.RecomputeADP(context.Background()); err != nil {
+		slog.Error("Failed to recompute ADP", "event_id", eventID, "err", err)
 	}
 }
@@ -0,0 +1,111 @@
+package draft
+
+import (
+	"strconv"
+
+	"github.com/coder/websocket"
+	"github.com/sblackwood23/fantasy-draft-app/internal/metrics"
+)
+
+// Draft metrics, served at /metrics
+var (
+	picksTotal = metrics.NewCounterVec("draft_picks_total",
+		"Picks made, by provenance (manual, auto, admin or keeper).", "provenance")
+	pickDuration = metrics.NewHistogramVec("draft_pick_duration_seconds",
+		"Time to process a pick, from validation through saving it to broadcasting it.", metrics.DefaultBuckets, "provenance")
+	pickPersistFailures = metrics.NewCounterVec("draft_pick_persist_failures_total",
+		"Picks that could not be saved to the database and were not applied.")
+	publishDropped = metrics.NewCounterVec("draft_publish_dropped_total",
+		"Broadcast draft messages not published (e.g. to webhooks) because the publish queue was full.")
+	websocketErrors = metrics.NewCounterVec("draft_websocket_errors_total",
+		"WebSocket connections that ended in a read or write error, by close code (none if no close frame was received).", "op", "close_code")
+)
+
+func init() {
+	metrics.Default.MustRegister(picksTotal, pickDuration, pickPersistFailures, publishDropped, websocketErrors)
+}
+
+// RegisterMetrics adds the room's gauges, read when scraped, to a registry
+func (s *DraftService) RegisterMetrics(r *metrics.Registry) {
+	r.MustRegister(
+		metrics.NewGaugeFunc("draft_rooms_active", "Draft rooms with a draft in progress or paused.", nil, s.activeRoomSamples),
+		metrics.NewGaugeFunc("draft_connected_clients", "Open connections to the draft room, by event and role (team, spectator or watcher).",
+			[]string{"event_id", "role"}, s.connectedClientSamples),
+		metrics.NewGaugeFunc("draft_broadcast_queue_depth", "Draft messages waiting to be broadcast to the room.",
+			[]string{"event_id"}, s.broadcastQueueSamples),
+		metrics.NewGaugeFunc("draft_client_queue_depth", "Messages queued across the room's connections, waiting to be written.",
+			[]string{"event_id"}, s.clientQueueSamples),
+	)
+}
+
+// room returns the draft room and its event_id label, or false if there is no room
+func (s *DraftService) room() (string, *DraftState, bool) {
+	state := s.GetRoom()
+	if state == nil {
+		return "", nil, false
+	}
+	return strconv.Itoa(state.GetEventID()), state, true
+}
+
+func (s *DraftService) activeRoomSamples() []metrics.Sample {
+	active := 0.0
+	if state := s.GetRoom(); state != nil {
+		if status := state.GetStatus(); status == StatusInProgress || status == StatusPaused {
+			active = 1
+		}
+	}
+	return []metrics.Sample{{Value: active}}
+}
+
+func (s *DraftService) connectedClientSamples() []metrics.Sample {
+	eventID, _, ok := s.room()
+	if !ok {
+		return nil
+	}
+
+	stats, _ := s.manager.GetClientStats()
+	var teams, spectators float64
+	for _, client := range stats {
+		if client.Spectator {
+			spectators++
+		} else {
+			teams++
+		}
+	}
+	return []metrics.Sample{
+		{LabelValues: []string{eventID, "team"}, Value: teams},
+		{LabelValues: []string{eventID, "spectator"}, Value: spectators},
+		{LabelValues: []string{eventID, "watcher"}, Value: float64(s.manager.GetWatcherCount())},
+	}
+}
+
+func (s *DraftService) broadcastQueueSamples() []metrics.Sample {
+	eventID, state, ok := s.room()
+	if !ok {
+		return nil
+	}
+	return []metrics.Sample{{LabelValues: []string{eventID}, Value: float64(len(state.outgoing))}}
+}
+
+func (s *DraftService) clientQueueSamples() []metrics.Sample {
+	eventID, _, ok := s.room()
+	if !ok {
+		return nil
+	}
+
+	stats, _ := s.manager.GetClientStats()
+	depth := 0
+	for _, client := range stats {
+		depth += client.QueueDepth
+	}
+	return []metrics.Sample{{LabelValues: []string{eventID}, Value: float64(depth)}}
+}
+
+// countWebSocketError records a connection that ended in an error
+func countWebSocketError(op string, err error) {
+	code := "none"
+	if status := websocket.CloseStatus(err); status != -1 {
+		code = strconv.Itoa(int(status))
+	}
+	websocketErrors.Inc(op, code)
+}
@@ -0,0 +1,217 @@
+package draft
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// notifyTimeout bounds a single out-of-band notification, e.g. one email
+const notifyTimeout = 30 * time.Second
+
+// NotificationPrefsStore loads the turn notification preferences of an event's opted-in teams
+type NotificationPrefsStore interface {
+	GetNotificationPrefs(ctx context.Context, eventID int) ([]models.NotificationPrefs, error)
+}
+
+// Notifier sends turn notifications outside the draft room, e.g. by email
+type Notifier interface {
+	Channel() string // The preference channel that opts a team in, e.g. models.NotifyChannelEmail
+	Notify(ctx context.Context, n TurnNotification) error
+}
+
+// TurnNotification tells a team its turn is coming up (on_deck) or running out (on_the_clock)
+type TurnNotification struct {
+	Type             string // MsgTypeOnDeck or MsgTypeOnTheClock
+	EventID          int
+	EventName        string
+	UserID           int
+	TeamName         string
+	Email            string // The team's contact email; empty if it has none
+	PicksAway        int    // Picks before the team's turn; 0 when on the clock
+	PickNumber       int    // The team's pick
+	Round            int
+	TurnDeadline     time.Time // Only set when on the clock
+	RemainingSeconds int       // Only set when on the clock
+}
+
+// OnDeckMessage is sent to a team's connections when it has the next pick
+type OnDeckMessage struct {
+	Type       string `json:"type"`
+	UserID     int    `json:"userID"`
+	PicksAway  int    `json:"picksAway"`
+	PickNumber int    `json:"pickNumber"`
+	Round      int    `json:"round"`
+}
+
+// OnTheClockMessage is sent to a team's connections when its turn starts or resumes
+type OnTheClockMessage struct {
+	Type         string `json:"type"`
+	UserID       int    `json:"userID"`
+	PickNumber   int    `json:"pickNumber"`
+	Round        int    `json:"round"`
+	TurnDeadline int64  `json:"turnDeadline"`
+}
+
+// turnNotifier tracks which out-of-band notifications were sent during one draft,
+// so a team hears about each of its picks once
+type turnNotifier struct {
+	mu         sync.Mutex
+	onDeckSent map[int]int  // User ID -> pick number the team was last told is coming
+	warned     map[int]bool // Pick numbers whose clock warning was sent
+	clock      *time.Timer  // Pending clock warning for the current turn
+}
+
+// startTurnNotifier sends turn notifications for each turn until the draft completes
+func (s *DraftService) startTurnNotifier(state *DraftState) {
+	n := &turnNotifier{onDeckSent: make(map[int]int), warned: make(map[int]bool)}
+	for {
+		select {
+		case <-state.Completed():
+			n.mu.Lock()
+			if n.clock != nil {
+				n.clock.Stop()
+			}
+			n.mu.Unlock()
+			return
+		case turn := <-state.Turns():
+			s.notifyTurn(state, n, turn)
+		}
+	}
+}
+
+// notifyTurn tells the teams on the clock and on deck over their WebSocket connections,
+// then sends the out-of-band notifications they opted in to
+func (s *DraftService) notifyTurn(state *DraftState, n *turnNotifier, turn TurnInfo) {
+	s.manager.SendToUser(turn.UserID, encodeMessage(OnTheClockMessage{
+		Type:         MsgTypeOnTheClock,
+		UserID:       turn.UserID,
+		PickNumber:   turn.PickNumber,
+		Round:        turn.Round,
+		TurnDeadline: turn.Deadline.Unix(),
+	}))
+	if len(turn.Upcoming) > 0 && turn.Upcoming[0] != turn.UserID {
+		s.manager.SendToUser(turn.Upcoming[0], encodeMessage(OnDeckMessage{
+			Type:       MsgTypeOnDeck,
+			UserID:     turn.Upcoming[0],
+			PicksAway:  1,
+			PickNumber: turn.PickNumber + 1,
+			Round:      turn.roundOf(turn.PickNumber + 1),
+		}))
+	}
+
+	if len(s.notifiers) == 0 {
+		return
+	}
+
+	eventID := state.GetEventID()
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+	prefs, err := s.notificationPrefs.GetNotificationPrefs(ctx, eventID)
+	if err != nil {
+		slog.Error("Failed to load notification preferences", "event_id", eventID, "err", err)
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// A new turn replaces the previous turn's clock warning
+	if n.clock != nil {
+		n.clock.Stop()
+		n.clock = nil
+	}
+
+	for _, p := range prefs {
+		base := TurnNotification{
+			EventID:   eventID,
+			EventName: p.EventName,
+			UserID:    p.UserID,
+			TeamName:  p.TeamName,
+		}
+		if p.TeamEmail != nil {
+			base.Email = *p.TeamEmail
+		}
+
+		if p.UserID == turn.UserID {
+			if p.ClockWarningSeconds > 0 && !n.warned[turn.PickNumber] {
+				n.clock = s.scheduleClockWarning(state, n, turn, p, base)
+			}
+			continue
+		}
+
+		// Tell the team once per pick, as soon as its next pick is within range
+		for i, userID := range turn.Upcoming {
+			if userID != p.UserID {
+				continue
+			}
+			pickNumber := turn.PickNumber + 1 + i
+			if i+1 <= p.OnDeckPicks && n.onDeckSent[p.UserID] != pickNumber {
+				n.onDeckSent[p.UserID] = pickNumber
+				notification := base
+				notification.Type = MsgTypeOnDeck
+				notification.PicksAway = i + 1
+				notification.PickNumber = pickNumber
+				notification.Round = turn.roundOf(pickNumber)
+				s.sendNotification(p, notification)
+			}
+			break
+		}
+	}
+}
+
+// scheduleClockWarning warns a team when its turn has ClockWarningSeconds left
+// The warning is skipped if the turn has ended or is paused by then; resuming reschedules it
+func (s *DraftService) scheduleClockWarning(state *DraftState, n *turnNotifier, turn TurnInfo, p models.NotificationPrefs, base TurnNotification) *time.Timer {
+	warning := time.Duration(p.ClockWarningSeconds) * time.Second
+	return time.AfterFunc(max(time.Until(turn.Deadline)-warning, 0), func() {
+		if !state.OnTheClock(turn.PickNumber) {
+			return
+		}
+
+		n.mu.Lock()
+		if n.warned[turn.PickNumber] {
+			n.mu.Unlock()
+			return
+		}
+		n.warned[turn.PickNumber] = true
+		n.mu.Unlock()
+
+		notification := base
+		notification.Type = MsgTypeOnTheClock
+		notification.PickNumber = turn.PickNumber
+		notification.Round = turn.Round
+		notification.TurnDeadline = turn.Deadline
+		notification.RemainingSeconds = int(max(time.Until(turn.Deadline), 0).Round(time.Second).Seconds())
+		s.sendNotification(p, notification)
+	})
+}
+
+// sendNotification sends a notification through every channel the team opted in to
+// Each send runs on its own goroutine, so a slow mail server never delays the draft
+func (s *DraftService) sendNotification(p models.NotificationPrefs, notification TurnNotification) {
+	for _, notifier := range s.notifiers {
+		if !p.Enabled(notifier.Channel()) {
+			continue
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			defer cancel()
+			if err := notifier.Notify(ctx, notification); err != nil {
+				slog.Warn("Failed to send turn notification", "event_id", notification.EventID, "user_id", notification.UserID,
+					"channel", notifier.Channel(), "type", notification.Type, "err", err)
+			}
+		}()
+	}
+}
+
+// roundOf returns the round of a 1-indexed pick number
+func (t TurnInfo) roundOf(pickNumber int) int {
+	if t.Teams == 0 {
+		return 0
+	}
+	return (pickNumber-1)/t.Teams + 1
+}
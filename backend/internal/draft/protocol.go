@@ -0,0 +1,145 @@
+package draft
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ProtocolVersion is the current version of the draft WebSocket protocol
+// Bump this whenever a message shape changes in a way clients must know about
+const ProtocolVersion = 1
+
+// supportedProtocolVersions lists every protocol version the server can speak
+var supportedProtocolVersions = []int{1}
+
+// Subprotocol returns the WebSocket subprotocol name for a protocol version (e.g. "draft.v1")
+func Subprotocol(version int) string {
+	return fmt.Sprintf("draft.v%d", version)
+}
+
+// supportedSubprotocols returns the subprotocol names offered during the WebSocket handshake
+func supportedSubprotocols() []string {
+	names := make([]string, len(supportedProtocolVersions))
+	for i, v := range supportedProtocolVersions {
+		names[i] = Subprotocol(v)
+	}
+	return names
+}
+
+// negotiateVersion picks the protocol version for a connection from the
+// Sec-WebSocket-Protocol header. Clients that offer no subprotocol get the
+// current version; clients that only offer unsupported versions are refused.
+func negotiateVersion(r *http.Request) (int, bool) {
+	offered := r.Header.Values("Sec-WebSocket-Protocol")
+	if len(offered) == 0 {
+		return ProtocolVersion, true
+	}
+
+	for _, header := range offered {
+		for _, name := range strings.Split(header, ",") {
+			name = strings.TrimSpace(name)
+			for _, v := range supportedProtocolVersions {
+				if strings.EqualFold(name, Subprotocol(v)) {
+					return v, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// Outgoing message payloads (server to client)
+// Every message the server sends is one of these structs, so the JSON shape
+// lives in exactly one place and is described by ProtocolSchema.
+
+// WelcomeMessage is sent once to every client right after the handshake
+type WelcomeMessage struct {
+	Type            string `json:"type"`
+	ProtocolVersion int    `json:"protocolVersion"`
+}
+
+// DraftStartedMessage is broadcast when a draft begins
+type DraftStartedMessage struct {
+	Type         string `json:"type"`
+	EventID      int    `json:"eventID"`
+	CurrentTurn  int    `json:"currentTurn"`
+	RoundNumber  int    `json:"roundNumber"`
+	TurnDeadline int64  `json:"turnDeadline"`
+}
+
+// PickMadeMessage is broadcast when a pick is made (manually or via auto-draft)
+type PickMadeMessage struct {
+	Type       string `json:"type"`
+	UserID     int    `json:"userID"`
+	PlayerID   int    `json:"playerID"`
+	PickNumber int    `json:"pickNumber"`
+	Round      int    `json:"round"`
+	AutoDraft  bool   `json:"autoDraft"`
+	Provenance string `json:"provenance" schema:"enum=manual|auto|admin"`
+}
+
+// TurnChangedMessage is broadcast when the turn advances to the next user
+type TurnChangedMessage struct {
+	Type         string `json:"type"`
+	CurrentTurn  int    `json:"currentTurn"`
+	RoundNumber  int    `json:"roundNumber"`
+	TurnDeadline int64  `json:"turnDeadline"`
+}
+
+// DraftCompletedMessage is broadcast when the final pick is made
+type DraftCompletedMessage struct {
+	Type        string `json:"type"`
+	EventID     int    `json:"eventID"`
+	TotalPicks  int    `json:"totalPicks"`
+	TotalRounds int    `json:"totalRounds"`
+}
+
+// DraftPausedMessage is broadcast when a draft is paused
+type DraftPausedMessage struct {
+	Type          string  `json:"type"`
+	EventID       int     `json:"eventID"`
+	RemainingTime float64 `json:"remainingTime"`
+	Error         string  `json:"error,omitempty"` // Why the server paused the draft itself; empty when a user paused it
+}
+
+// DraftResumedMessage is broadcast when a paused draft resumes
+type DraftResumedMessage struct {
+	Type         string `json:"type"`
+	EventID      int    `json:"eventID"`
+	CurrentTurn  int    `json:"currentTurn"`
+	RoundNumber  int    `json:"roundNumber"`
+	TurnDeadline int64  `json:"turnDeadline"`
+}
+
+// DraftStateMessage is sent to connecting clients so they can rebuild the draft board
+type DraftStateMessage struct {
+	Type string `json:"type"`
+	DraftSnapshot
+	ChatHistory []ChatEntry `json:"chatHistory"` // Most recent chat messages, oldest first
+}
+
+// PresenceMessage is broadcast whenever a client, spectator or feed watcher connects or disconnects
+type PresenceMessage struct {
+	Type           string `json:"type"`
+	ConnectedUsers []int  `json:"connectedUsers"` // User IDs with at least one team connection
+	Spectators     int    `json:"spectators"`     // Spectator WebSocket connections
+	Watchers       int    `json:"watchers"`       // Server-Sent Events feed subscribers
+}
+
+// ErrorMessage is sent to a single client when one of its messages is rejected
+type ErrorMessage struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// encodeMessage marshals an outgoing message struct
+// All outgoing structs contain only JSON-safe fields, so marshalling cannot fail
+func encodeMessage(msg any) []byte {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		panic(fmt.Sprintf("draft: failed to encode %T: %v", msg, err))
+	}
+	return data
+}
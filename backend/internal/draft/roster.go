@@ -0,0 +1,82 @@
+package draft
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// rosterFits reports whether every player can be placed in a distinct roster slot
+// players holds each player's positions. Slots can accept several positions (e.g. FLEX),
+// so this is a bipartite matching rather than a per-slot count.
+func rosterFits(slots models.RosterSlots, players [][]string) bool {
+	var spots [][]string
+	for _, slot := range slots {
+		for i := 0; i < slot.Count; i++ {
+			spots = append(spots, slot.Eligible())
+		}
+	}
+	if len(players) > len(spots) {
+		return false
+	}
+
+	// assigned[spot] is the index of the player in that spot, or -1
+	assigned := make([]int, len(spots))
+	for i := range assigned {
+		assigned[i] = -1
+	}
+
+	var place func(player int, visited []bool) bool
+	place = func(player int, visited []bool) bool {
+		for spot, eligible := range spots {
+			if visited[spot] || !canFill(eligible, players[player]) {
+				continue
+			}
+			visited[spot] = true
+			if assigned[spot] == -1 || place(assigned[spot], visited) {
+				assigned[spot] = player
+				return true
+			}
+		}
+		return false
+	}
+
+	for player := range players {
+		if !place(player, make([]bool, len(spots))) {
+			return false
+		}
+	}
+	return true
+}
+
+// canFill reports whether a player with the given positions is eligible for a slot
+func canFill(eligible, positions []string) bool {
+	if slices.Contains(eligible, models.AnyPosition) {
+		return true
+	}
+	for _, position := range positions {
+		if slices.ContainsFunc(eligible, func(e string) bool { return strings.EqualFold(e, position) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// fitsRoster reports whether drafting playerID keeps userID's roster fillable
+// Always true when the event defines no roster slots. Must be called while holding the mutex
+func (d *DraftState) fitsRoster(userID, playerID int) bool {
+	if len(d.rosterSlots) == 0 {
+		return true
+	}
+
+	var roster [][]string
+	for _, pick := range d.pickHistory {
+		if pick.UserID == userID {
+			roster = append(roster, d.playerPositions[pick.PlayerID])
+		}
+	}
+	roster = append(roster, d.playerPositions[playerID])
+
+	return rosterFits(d.rosterSlots, roster)
+}
@@ -0,0 +1,343 @@
+package draft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:generate go run ../../cmd/protocolgen -o ../../../frontend/src/types/protocol.schema.json
+
+// Schema is the subset of JSON Schema used to describe the draft protocol
+// It is generated from the Go message structs, so the structs stay the single source of truth
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Const                string             `json:"const,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// incomingMessages maps each client-to-server message type to its payload struct
+var incomingMessages = map[string]any{
+	MsgTypeStartDraft:  StartDraftMessage{},
+	MsgTypeMakePick:    MakePickMessage{},
+	MsgTypePauseDraft:  PauseDraftMessage{},
+	MsgTypeResumeDraft: ResumeDraftMessage{},
+
+	MsgTypeChatMessage:       SendChatMessage{},
+	MsgTypeMuteUser:          MuteUserMessage{},
+	MsgTypeDeleteChatMessage: DeleteChatMessageMessage{},
+	MsgTypeResetTeamSecret:   ResetTeamSecretMessage{},
+}
+
+// outgoingMessages maps each server-to-client message type to its payload struct
+var outgoingMessages = map[string]any{
+	MsgTypeWelcome:        WelcomeMessage{},
+	MsgTypeDraftStarted:   DraftStartedMessage{},
+	MsgTypeDraftPaused:    DraftPausedMessage{},
+	MsgTypeDraftResumed:   DraftResumedMessage{},
+	MsgTypeDraftCompleted: DraftCompletedMessage{},
+	MsgTypeDraftState:     DraftStateMessage{},
+	MsgTypePickMade:       PickMadeMessage{},
+	MsgTypeTurnChanged:    TurnChangedMessage{},
+	MsgTypePresence:       PresenceMessage{},
+
+	MsgTypeChatMessage:        ChatMessage{},
+	MsgTypeChatMessageDeleted: ChatMessageDeletedMessage{},
+	MsgTypeUserMuted:          UserMutedMessage{},
+	MsgTypeTeamSecretReset:    TeamSecretResetMessage{},
+	MsgTypeOnDeck:             OnDeckMessage{},
+	MsgTypeOnTheClock:         OnTheClockMessage{},
+	MsgTypeError:              ErrorMessage{},
+}
+
+// incomingSchemas holds the compiled schema for each incoming message type
+var incomingSchemas = func() map[string]*Schema {
+	schemas := make(map[string]*Schema, len(incomingMessages))
+	for msgType, msg := range incomingMessages {
+		schemas[msgType] = messageSchema(msgType, msg, true)
+	}
+	return schemas
+}()
+
+// ProtocolSchema returns a JSON Schema document describing every message in the protocol
+func ProtocolSchema() *Schema {
+	doc := &Schema{
+		Schema:      "https://json-schema.org/draft/2020-12/schema",
+		Title:       fmt.Sprintf("Fantasy draft WebSocket protocol v%d", ProtocolVersion),
+		Description: fmt.Sprintf("Generated from internal/draft; negotiate with subprotocol %q", Subprotocol(ProtocolVersion)),
+		Defs:        make(map[string]*Schema),
+	}
+
+	add := func(messages map[string]any, strict bool, direction string) {
+		// Keyed by Go struct name, since a type may be used in both directions (e.g. chat_message)
+		for msgType, msg := range messages {
+			s := messageSchema(msgType, msg, strict)
+			s.Description = direction
+			doc.Defs[reflect.TypeOf(msg).Name()] = s
+		}
+	}
+	add(incomingMessages, true, "client to server")
+	add(outgoingMessages, false, "server to client")
+
+	names := make([]string, 0, len(doc.Defs))
+	for name := range doc.Defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		doc.OneOf = append(doc.OneOf, &Schema{Ref: "#/$defs/" + name})
+	}
+
+	return doc
+}
+
+// messageSchema builds the schema for a single message struct
+// strict schemas reject properties that are not part of the struct
+func messageSchema(msgType string, msg any, strict bool) *Schema {
+	s := typeSchema(reflect.TypeOf(msg), strict)
+	s.Title = msgType
+	s.Properties["type"] = &Schema{Type: "string", Const: msgType}
+	return s
+}
+
+// typeSchema converts a Go type into a schema
+func typeSchema(t reflect.Type, strict bool) *Schema {
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: typeSchema(t.Elem(), strict)}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		if strict {
+			s.AdditionalProperties = new(bool)
+		}
+		addStructFields(s, t, strict)
+		sort.Strings(s.Required)
+		return s
+	default:
+		panic(fmt.Sprintf("draft: no schema mapping for %s", t))
+	}
+}
+
+// addStructFields adds the JSON fields of a struct (including embedded structs) to an object schema
+func addStructFields(s *Schema, t reflect.Type, strict bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			addStructFields(s, field.Type, strict)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fs := typeSchema(field.Type, strict)
+		applySchemaTag(fs, field.Tag.Get("schema"))
+		s.Properties[name] = fs
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// applySchemaTag applies constraints from a `schema:"minimum=1,minItems=1,maxLength=10,enum=a|b"` struct tag
+func applySchemaTag(s *Schema, tag string) {
+	if tag == "" {
+		return
+	}
+	for _, part := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "minimum":
+			minimum, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				panic(fmt.Sprintf("draft: invalid schema minimum %q", value))
+			}
+			s.Minimum = &minimum
+		case "minItems", "minLength", "maxLength":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				panic(fmt.Sprintf("draft: invalid schema %s %q", key, value))
+			}
+			switch key {
+			case "minItems":
+				s.MinItems = &n
+			case "minLength":
+				s.MinLength = &n
+			case "maxLength":
+				s.MaxLength = &n
+			}
+		case "enum":
+			s.Enum = strings.Split(value, "|")
+		default:
+			panic(fmt.Sprintf("draft: unknown schema tag key %q", key))
+		}
+	}
+}
+
+// ValidateIncoming checks a raw client message against the protocol schema
+// It returns the message type if the message is valid
+func ValidateIncoming(data []byte) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return "", fmt.Errorf("invalid JSON format")
+	}
+
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("message must be a JSON object")
+	}
+
+	msgType, _ := obj["type"].(string)
+	if msgType == "" {
+		return "", fmt.Errorf("message type is required")
+	}
+
+	schema, ok := incomingSchemas[msgType]
+	if !ok {
+		return msgType, fmt.Errorf("unknown message type: %s", msgType)
+	}
+
+	if err := validateValue(schema, value, ""); err != nil {
+		return msgType, fmt.Errorf("invalid %s message: %w", msgType, err)
+	}
+	return msgType, nil
+}
+
+// validateValue checks a decoded JSON value against a schema
+func validateValue(s *Schema, value any, path string) error {
+	label := path
+	if label == "" {
+		label = "message"
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s must be an object", label)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s is required", joinPath(path, name))
+			}
+		}
+		for name, v := range obj {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("unexpected field %s", joinPath(path, name))
+				}
+				continue
+			}
+			if err := validateValue(prop, v, joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s must be an array", label)
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			return fmt.Errorf("%s must have at least %d items", label, *s.MinItems)
+		}
+		for i, v := range arr {
+			if err := validateValue(s.Items, v, fmt.Sprintf("%s[%d]", label, i)); err != nil {
+				return err
+			}
+		}
+
+	case "integer", "number":
+		num, ok := value.(json.Number)
+		if s.Type == "integer" {
+			if !ok {
+				return fmt.Errorf("%s must be an integer", label)
+			}
+			if _, err := num.Int64(); err != nil {
+				return fmt.Errorf("%s must be an integer", label)
+			}
+		} else if !ok {
+			return fmt.Errorf("%s must be a number", label)
+		}
+		f, err := num.Float64()
+		if err != nil {
+			return fmt.Errorf("%s must be a number", label)
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return fmt.Errorf("%s must be at least %v", label, *s.Minimum)
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", label)
+		}
+		if s.Const != "" && str != s.Const {
+			return fmt.Errorf("%s must be %q", label, s.Const)
+		}
+		if s.MinLength != nil && utf8.RuneCountInString(str) < *s.MinLength {
+			return fmt.Errorf("%s must be at least %d characters", label, *s.MinLength)
+		}
+		if s.MaxLength != nil && utf8.RuneCountInString(str) > *s.MaxLength {
+			return fmt.Errorf("%s must be at most %d characters", label, *s.MaxLength)
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			return fmt.Errorf("%s must be one of %s", label, strings.Join(s.Enum, ", "))
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", label)
+		}
+	}
+
+	return nil
+}
+
+// joinPath appends a field name to a dotted path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,134 @@
+package draft
+
+import "testing"
+
+func TestValidateIncoming(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantType string
+		wantErr  string // Empty when the message is valid
+	}{
+		{
+			name:     "valid pick",
+			data:     `{"type": "make_pick", "userID": 1, "playerID": 5}`,
+			wantType: MsgTypeMakePick,
+		},
+		{
+			name:     "valid keeper pick",
+			data:     `{"type": "make_pick", "userID": 1, "playerID": 5, "keeper": true}`,
+			wantType: MsgTypeMakePick,
+		},
+		{
+			name:     "valid start",
+			data:     `{"type": "start_draft", "pickOrder": [1, 2], "totalRounds": 3, "timerDuration": 60}`,
+			wantType: MsgTypeStartDraft,
+		},
+		{
+			name:     "valid pause with no payload",
+			data:     `{"type": "pause_draft"}`,
+			wantType: MsgTypePauseDraft,
+		},
+		{
+			name:    "not JSON",
+			data:    `{"type": `,
+			wantErr: "invalid JSON format",
+		},
+		{
+			name:    "not an object",
+			data:    `["make_pick"]`,
+			wantErr: "message must be a JSON object",
+		},
+		{
+			name:    "missing type",
+			data:    `{"userID": 1}`,
+			wantErr: "message type is required",
+		},
+		{
+			name:    "type is not a string",
+			data:    `{"type": 7}`,
+			wantErr: "message type is required",
+		},
+		{
+			name:     "unknown type",
+			data:     `{"type": "steal_pick"}`,
+			wantType: "steal_pick",
+			wantErr:  "unknown message type: steal_pick",
+		},
+		{
+			name:     "missing field",
+			data:     `{"type": "make_pick", "userID": 1}`,
+			wantType: MsgTypeMakePick,
+			wantErr:  "invalid make_pick message: playerID is required",
+		},
+		{
+			name:     "unknown field",
+			data:     `{"type": "make_pick", "userID": 1, "playerID": 5, "force": true}`,
+			wantType: MsgTypeMakePick,
+			wantErr:  "invalid make_pick message: unexpected field force",
+		},
+		{
+			name:     "string for an integer",
+			data:     `{"type": "make_pick", "userID": "1", "playerID": 5}`,
+			wantType: MsgTypeMakePick,
+			wantErr:  "invalid make_pick message: userID must be an integer",
+		},
+		{
+			name:     "fraction for an integer",
+			data:     `{"type": "make_pick", "userID": 1.5, "playerID": 5}`,
+			wantType: MsgTypeMakePick,
+			wantErr:  "invalid make_pick message: userID must be an integer",
+		},
+		{
+			name:     "below minimum",
+			data:     `{"type": "make_pick", "userID": 0, "playerID": 5}`,
+			wantType: MsgTypeMakePick,
+			wantErr:  "invalid make_pick message: userID must be at least 1",
+		},
+		{
+			name:     "string for a boolean",
+			data:     `{"type": "mute_user", "userID": 3, "muted": "yes"}`,
+			wantType: MsgTypeMuteUser,
+			wantErr:  "invalid mute_user message: muted must be a boolean",
+		},
+		{
+			name:     "object for an array",
+			data:     `{"type": "start_draft", "pickOrder": {"0": 1}, "totalRounds": 3, "timerDuration": 60}`,
+			wantType: MsgTypeStartDraft,
+			wantErr:  "invalid start_draft message: pickOrder must be an array",
+		},
+		{
+			name:     "empty array",
+			data:     `{"type": "start_draft", "pickOrder": [], "totalRounds": 3, "timerDuration": 60}`,
+			wantType: MsgTypeStartDraft,
+			wantErr:  "invalid start_draft message: pickOrder must have at least 1 items",
+		},
+		{
+			name:     "wrong array item type",
+			data:     `{"type": "start_draft", "pickOrder": [1, "2"], "totalRounds": 3, "timerDuration": 60}`,
+			wantType: MsgTypeStartDraft,
+			wantErr:  "invalid start_draft message: pickOrder[1] must be an integer",
+		},
+		{
+			name:     "empty chat text",
+			data:     `{"type": "chat_message", "text": ""}`,
+			wantType: MsgTypeChatMessage,
+			wantErr:  "invalid chat_message message: text must be at least 1 characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgType, err := ValidateIncoming([]byte(tt.data))
+			if msgType != tt.wantType {
+				t.Errorf("type = %q, want %q", msgType, tt.wantType)
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,17 +2,18 @@ package draft
 
 import (
 	"context"
-	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 	"sync"
 
 	"github.com/coder/websocket"
+	"github.com/sblackwood23/fantasy-draft-app/internal/logging"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
 )
 
 // PickSaver defines the interface for persisting draft picks
 type PickSaver interface {
-	SavePick(ctx context.Context, eventID, userID, playerID, pickNumber, round int) error
+	SavePick(ctx context.Context, eventID, userID, playerID, pickNumber, round int, provenance string) error
 }
 
 // EventUpdater defines the interface for updating event status
@@ -20,35 +21,110 @@ type EventUpdater interface {
 	UpdateStatus(ctx context.Context, eventID int, status string) error
 }
 
+// SpectatorLimits looks up how many spectators an event allows
+type SpectatorLimits interface {
+	GetMaxSpectators(ctx context.Context, eventID int) (int, error)
+}
+
+// RankingUpdater defines the interface for recomputing rankings derived from draft results
+type RankingUpdater interface {
+	RecomputeADP(ctx context.Context) error
+}
+
+// Publisher records draft messages for delivery outside the room, e.g. to webhooks
+// It should only queue the message, since it runs on the path that broadcasts it
+type Publisher interface {
+	Publish(ctx context.Context, eventID int, msgType string, data []byte) error
+}
+
+// SessionVerifier checks the signed session token a team or admin connects with
+// The token comes from POST /events/join (a team) or the admin session endpoint (userID 0, admin)
+type SessionVerifier interface {
+	VerifySession(token string) (eventID, userID int, admin, spectator bool, err error)
+}
+
 // DraftService manages WebSocket connections and draft state
 type DraftService struct {
-	manager      *Manager
-	state        *DraftState
-	mu           sync.RWMutex // protects state
-	pickSaver    PickSaver
-	eventUpdater EventUpdater
+	manager       *Manager
+	state         *DraftState
+	chat          *chatRoom
+	mu            sync.RWMutex // protects state and chat
+	pickSaver     PickSaver
+	eventUpdater  EventUpdater
+	spectators    SpectatorLimits
+	chatStore     ChatStore
+	commissioners CommissionerChecker
+	rankings      RankingUpdater
+	teams         TeamStore
+	publisher     Publisher
+	sessions      SessionVerifier
+
+	notificationPrefs NotificationPrefsStore
+	notifiers         []Notifier // Out-of-band turn notifiers; turn messages still go to the room without any
 }
 
 // NewDraftService creates a new DraftService and starts the manager
-func NewDraftService(pickSaver PickSaver, eventUpdater EventUpdater) *DraftService {
+func NewDraftService(pickSaver PickSaver, eventUpdater EventUpdater, spectators SpectatorLimits, chatStore ChatStore, commissioners CommissionerChecker, rankings RankingUpdater, teams TeamStore, publisher Publisher, sessions SessionVerifier, notificationPrefs NotificationPrefsStore, notifiers ...Notifier) *DraftService {
 	s := &DraftService{
-		manager:      NewManager(),
-		pickSaver:    pickSaver,
-		eventUpdater: eventUpdater,
+		manager:       NewManager(),
+		pickSaver:     pickSaver,
+		eventUpdater:  eventUpdater,
+		spectators:    spectators,
+		chatStore:     chatStore,
+		commissioners: commissioners,
+		rankings:      rankings,
+		teams:         teams,
+		publisher:     publisher,
+		sessions:      sessions,
+
+		notificationPrefs: notificationPrefs,
+		notifiers:         notifiers,
 	}
+	s.manager.SetSnapshotSource(s.snapshotMessage)
 	go s.manager.Run()
 	return s
 }
 
+// RoomConfig holds the event settings a draft room is created with
+type RoomConfig struct {
+	PlayerIDs       []int
+	RosterSlots     models.RosterSlots
+	PlayerPositions map[int][]string // Positions of each player in the pool, for roster slot checks
+	PlayerRanks     map[int]int      // Rank of each ranked player in the event's ranking source, for auto-draft
+}
+
 // CreateRoom creates a new draft room for the given event with available players
-func (s *DraftService) CreateRoom(eventID int, playerIDs []int) error {
+func (s *DraftService) CreateRoom(eventID int, config RoomConfig) error {
+	// Load recent chat so reconnecting clients see the conversation, and who is muted
+	history, err := s.chatStore.GetRecentChatMessages(context.Background(), eventID, chatHistorySize)
+	if err != nil {
+		return err
+	}
+	muted, err := s.chatStore.GetChatMutes(context.Background(), eventID)
+	if err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.state = NewDraftState(eventID)
-	s.state.SetAvailablePlayers(playerIDs)
+	s.chat = newChatRoom(history, muted)
+	s.state = NewDraftState(eventID, s.pickSaver)
+	s.state.SetAvailablePlayers(config.PlayerIDs)
+	s.state.SetRosterRules(config.RosterSlots, config.PlayerPositions)
+	s.state.SetPlayerRanks(config.PlayerRanks)
 	return nil
 }
 
+// GetClientStats returns per-connection queue statistics for the room
+func (s *DraftService) GetClientStats() ([]ClientStats, int) {
+	return s.manager.GetClientStats()
+}
+
+// GetSpectatorCount returns the number of spectators connected to the room
+func (s *DraftService) GetSpectatorCount() int {
+	return s.manager.GetSpectatorCount()
+}
+
 // GetRoom returns the current draft room state
 func (s *DraftService) GetRoom() *DraftState {
 	s.mu.RLock()
@@ -56,53 +132,119 @@ func (s *DraftService) GetRoom() *DraftState {
 	return s.state
 }
 
-// Client represents a WebSocket client connection
-type Client struct {
-	Conn *websocket.Conn
-	Send chan []byte // Buffered channel for outgoing messages
-}
-
-// SendError sends an error message to this client
-func (c *Client) SendError(message string) {
-	errMsg, _ := json.Marshal(map[string]string{
-		"type":  "error",
-		"error": message,
-	})
-	c.Send <- errMsg
+// DraftRunning reports whether the room is for the given event and its draft has started but not completed
+func (s *DraftService) DraftRunning(eventID int) bool {
+	state := s.GetRoom()
+	if state == nil || state.GetEventID() != eventID {
+		return false
+	}
+	status := state.GetStatus()
+	return status == StatusInProgress || status == StatusPaused
 }
 
 // HandleWebSocket upgrades HTTP connection to WebSocket and handles messages
 func (s *DraftService) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	// Negotiate the protocol version before upgrading so unsupported clients get a plain HTTP error
+	version, ok := negotiateVersion(r)
+	if !ok {
+		http.Error(w, `{"error": "unsupported protocol version"}`, http.StatusBadRequest)
+		return
+	}
+
+	// Identify the connection: ?token=<session token> for a team, an admin or a spectator
+	// The team is only ever taken from the signed token, never from the client's say-so
+	var roomEventID int
+	if room := s.GetRoom(); room != nil {
+		roomEventID = room.GetEventID()
+	}
+	session, ok := s.verifySession(w, r, roomEventID)
+	if !ok {
+		return
+	}
+	userID, admin, spectator := session.userID, session.admin, session.spectator
+
 	// Upgrade HTTP connection to WebSocket
 	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols: supportedSubprotocols(),
 		// Allow all origins for development (file:// and localhost)
 		// In production, restrict this to your frontend domain
 		InsecureSkipVerify: true,
 	})
 	if err != nil {
-		log.Printf("Failed to upgrade connection: %v", err)
+		slog.WarnContext(r.Context(), "Failed to upgrade connection", "err", err)
 		http.Error(w, "Failed to upgrade to WebSocket", http.StatusInternalServerError)
 		return
 	}
 
-	log.Println("WebSocket connection established")
+	// Bind the connection's log records to it, its team and the room's event
+	ctx := logging.With(r.Context(), "conn_id", logging.NewID(), "user_id", userID, "admin", admin, "spectator", spectator,
+		"event_id", session.eventID)
+	slog.InfoContext(ctx, "WebSocket connection established", "protocol_version", version)
 
 	// Create client
-	client := &Client{
-		Conn: conn,
-		Send: make(chan []byte, 256), // Buffered channel
+	client := newClient(ctx, conn, version, userID, spectator)
+	client.Admin = admin
+
+	// Tell the client which protocol version the server will speak (queued before any broadcast)
+	client.enqueue(encodeMessage(WelcomeMessage{
+		Type:            MsgTypeWelcome,
+		ProtocolVersion: version,
+	}))
+
+	// Register client with the draft manager; spectators are capped by their event, read from
+	// the event so changes to it apply at once
+	spectatorLimit := 0
+	if spectator {
+		if spectatorLimit, err = s.spectators.GetMaxSpectators(ctx, session.eventID); err != nil {
+			slog.ErrorContext(ctx, "Failed to look up the spectator limit", "err", err)
+			conn.Close(websocket.StatusInternalError, "failed to check the spectator limit")
+			return
+		}
+	}
+	if !s.manager.Register(client, spectatorLimit) {
+		conn.Close(websocket.StatusTryAgainLater, "spectator limit reached")
+		return
 	}
-	// Register client with the draft manager
-	s.manager.Register(client)
 
 	// Start write pump in separate goroutine
-	go s.writePump(r.Context(), client)
+	go s.writePump(ctx, client)
 
 	// Send current draft state if there's an active draft (for reconnection)
 	s.sendStateToClient(client)
 
 	// Start read pump (blocks here until connection closes)
-	s.readPump(r.Context(), client)
+	s.readPump(ctx, client)
+}
+
+// connSession is who a connection's session token identifies
+type connSession struct {
+	eventID   int
+	userID    int // 0 for an admin or spectator
+	admin     bool
+	spectator bool
+}
+
+// verifySession checks the request's ?token= session token, and that it is for eventID unless
+// that is 0. Writes the error response and returns false if the token is missing or not valid
+func (s *DraftService) verifySession(w http.ResponseWriter, r *http.Request, eventID int) (connSession, bool) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, `{"error": "a session token is required - join or spectate the event first"}`, http.StatusUnauthorized)
+		return connSession{}, false
+	}
+
+	var session connSession
+	var err error
+	session.eventID, session.userID, session.admin, session.spectator, err = s.sessions.VerifySession(token)
+	if err != nil {
+		http.Error(w, `{"error": "invalid or expired session token"}`, http.StatusUnauthorized)
+		return connSession{}, false
+	}
+	if eventID != 0 && session.eventID != eventID {
+		http.Error(w, `{"error": "session is for a different event"}`, http.StatusForbidden)
+		return connSession{}, false
+	}
+	return session, true
 }
 
 // readPump handles incoming messages from the client
@@ -110,7 +252,7 @@ func (s *DraftService) readPump(ctx context.Context, c *Client) {
 	defer func() {
 		s.manager.Unregister(c) // Unregister client
 		c.Conn.Close(websocket.StatusNormalClosure, "connection closed")
-		log.Println("Client disconnected")
+		slog.InfoContext(ctx, "Client disconnected")
 	}()
 
 	// Set read limit to 32KB
@@ -123,14 +265,18 @@ func (s *DraftService) readPump(ctx context.Context, c *Client) {
 			// Check if connection was closed normally
 			if websocket.CloseStatus(err) == websocket.StatusNormalClosure ||
 				websocket.CloseStatus(err) == websocket.StatusGoingAway {
-				log.Println("Client disconnected normally")
+				slog.DebugContext(ctx, "Client closed the connection", "close_code", int(websocket.CloseStatus(err)))
 				return
 			}
-			log.Printf("Read error: %v", err)
+			slog.WarnContext(ctx, "Read error", "err", err)
+			countWebSocketError("read", err)
 			return
 		}
 
-		log.Printf("Received message: %s", string(data))
+		// Payloads can hold chat text, so they are only logged at debug level
+		if slog.Default().Enabled(ctx, slog.LevelDebug) {
+			slog.DebugContext(ctx, "Received message", "payload", string(data))
+		}
 
 		// Handle the message
 		s.handleMessage(c, data)
@@ -143,26 +289,36 @@ func (s *DraftService) writePump(ctx context.Context, c *Client) {
 	for msg := range c.Send {
 		// Write message to client
 		if err := c.Conn.Write(ctx, websocket.MessageText, msg); err != nil {
-			log.Printf("Write error: %v", err)
+			slog.WarnContext(ctx, "Write error", "err", err)
+			countWebSocketError("write", err)
 			return
 		}
-		log.Printf("Sent message: %s", string(msg))
+		if slog.Default().Enabled(ctx, slog.LevelDebug) {
+			slog.DebugContext(ctx, "Sent message", "payload", string(msg))
+		}
 	}
+
+	// Send channel was closed by the manager - send the close frame with its reason
+	code, reason := c.closeStatus()
+	c.Conn.Close(code, reason)
 }
 
 // handleMessage routes incoming messages to appropriate handlers
 func (s *DraftService) handleMessage(c *Client, data []byte) {
-	// Parse message to extract type
-	var msg struct {
-		Type string `json:"type"`
+	// Validate against the protocol schema and extract the type
+	msgType, err := ValidateIncoming(data)
+	if err != nil {
+		c.SendError(err.Error())
+		return
 	}
-	if err := json.Unmarshal(data, &msg); err != nil {
-		c.SendError("invalid JSON format")
+
+	if c.Spectator && isMutating(msgType) {
+		c.SendError("spectators cannot send " + msgType)
 		return
 	}
 
 	// Route to appropriate handler based on message type
-	switch msg.Type {
+	switch msgType {
 	case MsgTypeStartDraft:
 		s.handleStartDraft(c, data)
 	case MsgTypeMakePick:
@@ -171,41 +327,51 @@ func (s *DraftService) handleMessage(c *Client, data []byte) {
 		s.handlePauseDraft(c)
 	case MsgTypeResumeDraft:
 		s.handleResumeDraft(c)
+	case MsgTypeChatMessage:
+		s.handleChatMessage(c, data)
+	case MsgTypeMuteUser:
+		s.handleMuteUser(c, data)
+	case MsgTypeDeleteChatMessage:
+		s.handleDeleteChatMessage(c, data)
+	case MsgTypeResetTeamSecret:
+		s.handleResetTeamSecret(c, data)
 	default:
-		c.SendError("unknown message type: " + msg.Type)
+		c.SendError("unknown message type: " + msgType)
 	}
 }
 
 // sendStateToClient sends the current draft state to a newly connected client
 // This enables reconnection - clients joining mid-draft receive the full state
 func (s *DraftService) sendStateToClient(c *Client) {
+	msg := s.snapshotMessage()
+	if msg == nil {
+		return // No draft room exists, or it hasn't been configured/started yet
+	}
+
+	c.enqueue(msg)
+	slog.DebugContext(c.ctx, "Sent draft state to reconnecting client")
+}
+
+// snapshotMessage builds a draft_state message for the current room
+// Returns nil if there is no room or the draft hasn't started
+func (s *DraftService) snapshotMessage() []byte {
 	s.mu.RLock()
 	state := s.state
+	chat := s.chat
 	s.mu.RUnlock()
 
 	if state == nil {
-		return // No draft room exists
+		return nil
 	}
 
 	snapshot := state.GetSnapshot()
 	if snapshot.Status == StatusNotStarted {
-		return // Draft exists but hasn't been configured/started yet
-	}
-
-	msg, _ := json.Marshal(map[string]interface{}{
-		"type":             MsgTypeDraftState,
-		"eventID":          snapshot.EventID,
-		"status":           snapshot.Status,
-		"currentTurn":      snapshot.CurrentTurn,
-		"roundNumber":      snapshot.RoundNumber,
-		"currentPickIndex": snapshot.CurrentPickIndex,
-		"totalRounds":      snapshot.TotalRounds,
-		"pickOrder":        snapshot.PickOrder,
-		"availablePlayers": snapshot.AvailablePlayers,
-		"turnDeadline":     snapshot.TurnDeadline,
-		"remainingTime":    snapshot.RemainingTime,
-		"pickHistory":      snapshot.PickHistory,
+		return nil
+	}
+
+	return encodeMessage(DraftStateMessage{
+		Type:          MsgTypeDraftState,
+		DraftSnapshot: snapshot,
+		ChatHistory:   chat.history(),
 	})
-	c.Send <- msg
-	log.Printf("Sent draft state to reconnecting client (status: %s)", snapshot.Status)
 }
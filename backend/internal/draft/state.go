@@ -1,12 +1,17 @@
 package draft
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"slices"
 	"sync"
 	"time"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/logging"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
 )
 
 type DraftStatus string
@@ -18,57 +23,87 @@ const (
 	StatusCompleted  DraftStatus = "completed"
 )
 
+const (
+	persistTimeout       = 5 * time.Second // How long a pick may take to commit to the database
+	maxAutoDraftFailures = 3               // Failed auto-draft saves in a row before the draft is paused
+)
+
+// autoDraftRetryDelay is the wait before retrying an auto-draft whose save failed (tests shorten it)
+var autoDraftRetryDelay = 5 * time.Second
+
 // PickResult contains the details of a completed pick for persistence
 type PickResult struct {
-	EventID    int  `json:"eventID,omitempty"`
-	UserID     int  `json:"userID"`
-	PlayerID   int  `json:"playerID"`
-	PickNumber int  `json:"pickNumber"`
-	Round      int  `json:"round"`
-	AutoDraft  bool `json:"autoDraft"`
+	EventID    int    `json:"eventID,omitempty"`
+	UserID     int    `json:"userID"`
+	PlayerID   int    `json:"playerID"`
+	PickNumber int    `json:"pickNumber"`
+	Round      int    `json:"round"`
+	AutoDraft  bool   `json:"autoDraft"`
+	Provenance string `json:"provenance" schema:"enum=manual|auto|admin|keeper"`
+}
+
+// TurnInfo describes a turn that has just started or resumed, for turn notifications
+type TurnInfo struct {
+	UserID     int // Team on the clock
+	PickNumber int // 1-indexed
+	Round      int
+	Deadline   time.Time // When the turn expires
+	Upcoming   []int     // Teams with the following picks, in order, up to models.MaxOnDeckPicks
+	Teams      int       // Teams in the pick order
 }
 
 // DraftSnapshot captures the current state for client synchronization
 type DraftSnapshot struct {
-	EventID          int          `json:"eventID"`
-	Status           DraftStatus  `json:"status"`
-	CurrentTurn      int          `json:"currentTurn"`
-	RoundNumber      int          `json:"roundNumber"`
-	CurrentPickIndex int          `json:"currentPickIndex"`
-	TotalRounds      int          `json:"totalRounds"`
-	PickOrder        []int        `json:"pickOrder"`
-	AvailablePlayers []int        `json:"availablePlayers"`
-	TurnDeadline     int64        `json:"turnDeadline"`
-	RemainingTime    float64      `json:"remainingTime"`
-	PickHistory      []PickResult `json:"pickHistory"`
+	EventID          int                 `json:"eventID"`
+	Status           DraftStatus         `json:"status" schema:"enum=not_started|in_progress|paused|completed"`
+	CurrentTurn      int                 `json:"currentTurn"`
+	RoundNumber      int                 `json:"roundNumber"`
+	CurrentPickIndex int                 `json:"currentPickIndex"`
+	TotalRounds      int                 `json:"totalRounds"`
+	PickOrder        []int               `json:"pickOrder"`
+	AvailablePlayers []int               `json:"availablePlayers"`
+	TurnDeadline     int64               `json:"turnDeadline"`
+	RemainingTime    float64             `json:"remainingTime"`
+	PickHistory      []PickResult        `json:"pickHistory"`
+	RosterSlots      []models.RosterSlot `json:"rosterSlots"`
+	Teams            []TeamProfile       `json:"teams"` // Draft board profiles of the event's teams, in join order
 }
 
 type DraftState struct {
-	mu               sync.Mutex      // Protects concurrent access to state
-	eventID          int             // ID of the event for which the draft is occurring
-	currentTurnID    int             // ID of the user whose turn it currently is
-	pickTimer        *time.Timer     // Stores the timer for a pick
-	roundNumber      int             // The number of what round it is
-	draftStatus      DraftStatus     // Status of the draft
-	outgoing         chan []byte     // Outgoing messages from the draft state
-	pickResults      chan PickResult // Channel for completed picks (for persistence)
-	completed        chan struct{}   // Closed when draft completes (signals DraftService)
-	pickOrder        []int           // Order of user IDs for drafting
-	currentPickIndex int             // Current position in pickOrder
-	timerDuration    time.Duration   // How long each user has to pick
-	turnDeadline     time.Time       // When the current turn expires (for client countdown)
-	remainingTime    time.Duration   // Time remaining when paused (for resume)
-	totalRounds      int             // Total rounds in the draft (picks per team)
-	availablePlayers []int           // Player IDs available to draft
-	pickHistory      []PickResult    // All picks made in order (for reconnection sync)
-}
-
-func NewDraftState(eventID int) *DraftState {
+	mu               sync.Mutex         // Protects concurrent access to state
+	eventID          int                // ID of the event for which the draft is occurring
+	currentTurnID    int                // ID of the user whose turn it currently is
+	pickTimer        *time.Timer        // Stores the timer for a pick
+	timerGeneration  uint64             // Bumped whenever the timer starts or stops, so a stale expiry is ignored
+	pickInFlight     bool               // A pick is being saved; the turn is reserved until it is applied or fails
+	autoDraftFails   int                // Failed auto-draft saves in a row for the current turn
+	roundNumber      int                // The number of what round it is
+	draftStatus      DraftStatus        // Status of the draft
+	outgoing         chan []byte        // Outgoing messages from the draft state
+	turns            chan TurnInfo      // Turns as they start, for notifications
+	pickSaver        PickSaver          // Commits picks to the database before they are applied
+	completed        chan struct{}      // Closed when draft completes (signals DraftService)
+	pickOrder        []int              // Order of user IDs for drafting
+	currentPickIndex int                // Current position in pickOrder
+	timerDuration    time.Duration      // How long each user has to pick
+	turnDeadline     time.Time          // When the current turn expires (for client countdown)
+	remainingTime    time.Duration      // Time remaining when paused (for resume)
+	totalRounds      int                // Total rounds in the draft (picks per team)
+	availablePlayers []int              // Player IDs available to draft
+	pickHistory      []PickResult       // All picks made in order (for reconnection sync)
+	rosterSlots      models.RosterSlots // Roster slots each team must fill (empty means unrestricted)
+	playerPositions  map[int][]string   // Positions of each player in the pool, for roster slot checks
+	playerRanks      map[int]int        // Rank of each ranked player, for auto-draft (empty means random)
+	teams            []TeamProfile      // Draft board profiles, loaded when the draft starts
+}
+
+func NewDraftState(eventID int, pickSaver PickSaver) *DraftState {
 	return &DraftState{
 		eventID:     eventID,
 		draftStatus: StatusNotStarted,
 		outgoing:    make(chan []byte, 256),
-		pickResults: make(chan PickResult, 256),
+		turns:       make(chan TurnInfo, 256),
+		pickSaver:   pickSaver,
 		completed:   make(chan struct{}),
 	}
 }
@@ -98,90 +133,208 @@ func (d *DraftState) StartDraft(pickOrder []int, totalRounds int, timerDuration
 	d.startTimer(d.timerDuration)
 
 	// Emit draft started message
-	msg, _ := json.Marshal(map[string]interface{}{
-		"type":         MsgTypeDraftStarted,
-		"eventID":      d.eventID,
-		"currentTurn":  d.currentTurnID,
-		"roundNumber":  d.roundNumber,
-		"turnDeadline": d.turnDeadline.Unix(),
+	d.outgoing <- encodeMessage(DraftStartedMessage{
+		Type:         MsgTypeDraftStarted,
+		EventID:      d.eventID,
+		CurrentTurn:  d.currentTurnID,
+		RoundNumber:  d.roundNumber,
+		TurnDeadline: d.turnDeadline.Unix(),
 	})
-	d.outgoing <- msg
+	d.emitTurn()
 
 	return nil
 }
 
 // startTimer starts the countdown for the current pick with the given duration
+// Must be called while holding the mutex
 func (d *DraftState) startTimer(duration time.Duration) {
 	// Stop existing timer if any
-	if d.pickTimer != nil {
-		d.pickTimer.Stop()
-	}
+	d.stopTimer()
 
 	d.turnDeadline = time.Now().Add(duration)
-	d.pickTimer = time.NewTimer(duration)
+	generation := d.timerGeneration
+	d.pickTimer = time.AfterFunc(duration, func() {
+		d.handleTimerExpired(generation)
+	})
+}
 
-	// Wait for timer in a goroutine
-	go func() {
-		<-d.pickTimer.C
-		d.handleTimerExpired()
-	}()
+// stopTimer stops the pick timer, leaving turnDeadline as it was
+// An expiry that already fired and is waiting for the mutex sees the new generation and does nothing.
+// Must be called while holding the mutex
+func (d *DraftState) stopTimer() {
+	if d.pickTimer != nil {
+		d.pickTimer.Stop()
+	}
+	d.timerGeneration++
 }
 
 // handleTimerExpired is called when the pick timer runs out - triggers auto-draft
-func (d *DraftState) handleTimerExpired() {
+// generation is the timer generation when the timer was started
+func (d *DraftState) handleTimerExpired(generation uint64) {
 	d.mu.Lock()
-	defer d.mu.Unlock()
 
-	if d.draftStatus != StatusInProgress {
+	// The timer was stopped or replaced after it fired, or a pick for this turn is being saved
+	if generation != d.timerGeneration || d.draftStatus != StatusInProgress || d.pickInFlight {
+		d.mu.Unlock()
 		return
 	}
 
-	// Pick a random available player
+	// Pick the best ranked available player, or a random one if none are ranked
 	if len(d.availablePlayers) == 0 {
+		d.mu.Unlock()
 		return // No players left to draft
 	}
 
-	randomIndex := rand.Intn(len(d.availablePlayers))
-	playerID := d.availablePlayers[randomIndex]
+	ctx := logging.With(context.Background(), "event_id", d.eventID, "pick_id", logging.NewID())
+
+	// Only consider players that fit the team's open roster slots, if any do
+	candidates := slices.DeleteFunc(slices.Clone(d.availablePlayers), func(id int) bool {
+		return !d.fitsRoster(d.currentTurnID, id)
+	})
+	if len(candidates) == 0 {
+		slog.WarnContext(ctx, "No available player fits the team's roster slots; auto-drafting from the whole pool", "pick_user_id", d.currentTurnID)
+		candidates = d.availablePlayers
+	}
 
-	d.recordPick(d.currentTurnID, playerID, true)
+	playerID, ok := d.bestRanked(candidates)
+	if !ok {
+		playerID = candidates[rand.Intn(len(candidates))]
+	}
+
+	pickResult := d.reservePick(d.currentTurnID, playerID, models.PickProvenanceAuto)
+	d.mu.Unlock()
+
+	start := time.Now()
+	err := d.savePick(ctx, pickResult)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err == nil {
+		err = d.applyPick(ctx, pickResult, start)
+	}
+	if err != nil {
+		d.autoDraftFailed(ctx, pickResult, err)
+	}
 }
 
-// recordPick handles the common logic for recording a pick (manual or auto-draft)
+// autoDraftFailed handles an auto-draft pick that could not be saved; nothing was applied
+// A player the database rejects outright is dropped from the pool so the retry picks someone else.
+// After maxAutoDraftFailures in a row the draft is paused rather than retried forever.
 // Must be called while holding the mutex
-func (d *DraftState) recordPick(userID, playerID int, autoDraft bool) {
-	// Remove player from available list
-	d.removePlayer(playerID)
+func (d *DraftState) autoDraftFailed(ctx context.Context, pick PickResult, err error) {
+	d.releasePick()
+	d.autoDraftFails++
+
+	retryDelay := autoDraftRetryDelay
+	if errors.Is(err, models.ErrPlayerNotDraftable) {
+		d.removePlayer(pick.PlayerID)
+		retryDelay = 0
+	}
+
+	if d.autoDraftFails >= maxAutoDraftFailures {
+		slog.ErrorContext(ctx, "Auto-draft failed repeatedly; pausing the draft",
+			"pick_user_id", pick.UserID, "player_id", pick.PlayerID, "failures", d.autoDraftFails, "err", err)
+		d.autoDraftFails = 0
+		if d.draftStatus == StatusInProgress {
+			// Resuming gives the team a full turn to pick for itself
+			d.pause(d.timerDuration, "auto-draft failed; a pick must be made for this team before the draft continues")
+		}
+		return
+	}
+
+	// Try again rather than skipping the turn, unless the draft was paused meanwhile
+	slog.ErrorContext(ctx, "Failed to save auto-draft pick; retrying",
+		"pick_user_id", pick.UserID, "player_id", pick.PlayerID, "failures", d.autoDraftFails, "err", err)
+	if d.draftStatus == StatusInProgress {
+		d.startTimer(retryDelay)
+	}
+}
+
+// reservePick claims the current pick for a player while it is saved
+// The timer is stopped so the turn cannot expire mid-save; other picks are refused until
+// the pick is applied or released. Must be called while holding the mutex
+func (d *DraftState) reservePick(userID, playerID int, provenance string) PickResult {
+	d.pickInFlight = true
+	d.stopTimer()
 
 	// Create pick result (pick_number is 1-indexed)
-	pickResult := PickResult{
+	return PickResult{
 		EventID:    d.eventID,
 		UserID:     userID,
 		PlayerID:   playerID,
 		PickNumber: d.currentPickIndex + 1,
 		Round:      d.roundNumber,
-		AutoDraft:  autoDraft,
+		AutoDraft:  provenance == models.PickProvenanceAuto,
+		Provenance: provenance,
+	}
+}
+
+// releasePick gives up a reserved pick that failed to save, leaving the draft as it was
+// The turn's timer restarts with the time it had left. Must be called while holding the mutex
+func (d *DraftState) releasePick() {
+	d.pickInFlight = false
+	pickPersistFailures.Inc()
+	if d.draftStatus == StatusInProgress {
+		d.startTimer(max(time.Until(d.turnDeadline), 0))
 	}
+}
+
+// savePick commits a reserved pick to the database
+// Must be called WITHOUT holding the mutex, so a slow save never stalls snapshots or other messages
+func (d *DraftState) savePick(ctx context.Context, pick PickResult) error {
+	// The save carries the pick's log attributes but not its cancellation: a pick
+	// is not abandoned half-saved because the connection that made it closed
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), persistTimeout)
+	defer cancel()
+	return d.pickSaver.SavePick(saveCtx, pick.EventID, pick.UserID, pick.PlayerID, pick.PickNumber, pick.Round, pick.Provenance)
+}
+
+// applyPick changes and broadcasts the draft state for a saved pick
+// State only changes once the pick is durable, so a failed save leaves the draft exactly as it was.
+// Must be called while holding the mutex
+func (d *DraftState) applyPick(ctx context.Context, pickResult PickResult, start time.Time) error {
+	// The reservation keeps the pick number fixed during the save; anything else is a bug
+	if !d.pickInFlight || d.currentPickIndex+1 != pickResult.PickNumber {
+		slog.ErrorContext(ctx, "Draft moved on while a pick was being saved", "pick_number", pickResult.PickNumber, "current_pick_number", d.currentPickIndex+1)
+		return fmt.Errorf("draft moved on while pick %d was being saved", pickResult.PickNumber)
+	}
+	d.pickInFlight = false
+	d.autoDraftFails = 0
+	userID, playerID, provenance := pickResult.UserID, pickResult.PlayerID, pickResult.Provenance
+
+	// A pick made while paused (admin picking for a user) resumes the draft
+	d.draftStatus = StatusInProgress
+
+	// Remove player from available list
+	d.removePlayer(playerID)
 
 	// Add to pick history for reconnection sync
 	d.pickHistory = append(d.pickHistory, pickResult)
 
 	// Emit pick made message
-	msg, _ := json.Marshal(map[string]interface{}{
-		"type":       MsgTypePickMade,
-		"userID":     userID,
-		"playerID":   playerID,
-		"pickNumber": pickResult.PickNumber,
-		"round":      d.roundNumber,
-		"autoDraft":  autoDraft,
+	d.outgoing <- encodeMessage(PickMadeMessage{
+		Type:       MsgTypePickMade,
+		UserID:     userID,
+		PlayerID:   playerID,
+		PickNumber: pickResult.PickNumber,
+		Round:      d.roundNumber,
+		AutoDraft:  pickResult.AutoDraft,
+		Provenance: provenance,
 	})
-	d.outgoing <- msg
-
-	// Send pick result for persistence
-	d.pickResults <- pickResult
 
 	// Move to next turn
 	d.advanceTurn()
+
+	slog.InfoContext(ctx, "Pick recorded",
+		"pick_user_id", userID,
+		"player_id", playerID,
+		"pick_number", pickResult.PickNumber,
+		"round", pickResult.Round,
+		"provenance", provenance,
+	)
+	picksTotal.Inc(provenance)
+	pickDuration.Observe(time.Since(start).Seconds(), provenance)
+	return nil
 }
 
 // advanceTurn moves to the next player in the pick order
@@ -218,13 +371,48 @@ func (d *DraftState) advanceTurn() {
 	d.startTimer(d.timerDuration)
 
 	// Emit turn changed message
-	msg, _ := json.Marshal(map[string]interface{}{
-		"type":         MsgTypeTurnChanged,
-		"currentTurn":  d.currentTurnID,
-		"roundNumber":  d.roundNumber,
-		"turnDeadline": d.turnDeadline.Unix(),
+	d.outgoing <- encodeMessage(TurnChangedMessage{
+		Type:         MsgTypeTurnChanged,
+		CurrentTurn:  d.currentTurnID,
+		RoundNumber:  d.roundNumber,
+		TurnDeadline: d.turnDeadline.Unix(),
 	})
-	d.outgoing <- msg
+	d.emitTurn()
+}
+
+// emitTurn reports the current turn to the turn notifier
+// Notifications are best effort: if the notifier has fallen far behind, the turn is dropped
+// rather than holding up the draft. Must be called while holding the mutex
+func (d *DraftState) emitTurn() {
+	totalPicks := len(d.pickOrder) * d.totalRounds
+	var upcoming []int
+	for index := d.currentPickIndex + 1; index < totalPicks && len(upcoming) < models.MaxOnDeckPicks; index++ {
+		upcoming = append(upcoming, d.userAt(index))
+	}
+
+	turn := TurnInfo{
+		UserID:     d.currentTurnID,
+		PickNumber: d.currentPickIndex + 1,
+		Round:      d.roundNumber,
+		Deadline:   d.turnDeadline,
+		Upcoming:   upcoming,
+		Teams:      len(d.pickOrder),
+	}
+	select {
+	case d.turns <- turn:
+	default:
+		slog.Warn("Turn notifier is behind; dropped notifications", "event_id", d.eventID, "pick_number", turn.PickNumber)
+	}
+}
+
+// userAt returns the team with the pick at a 0-indexed position in the snake order
+func (d *DraftState) userAt(index int) int {
+	numPlayers := len(d.pickOrder)
+	position := index % numPlayers
+	if (index/numPlayers)%2 == 1 {
+		position = numPlayers - 1 - position
+	}
+	return d.pickOrder[position]
 }
 
 // completeDraft finalizes the draft when all picks are made
@@ -232,54 +420,78 @@ func (d *DraftState) completeDraft() {
 	d.draftStatus = StatusCompleted
 
 	// Stop any running timer
-	if d.pickTimer != nil {
-		d.pickTimer.Stop()
-	}
+	d.stopTimer()
 
 	// Emit draft completed message
-	msg, _ := json.Marshal(map[string]interface{}{
-		"type":        MsgTypeDraftCompleted,
-		"eventID":     d.eventID,
-		"totalPicks":  d.currentPickIndex,
-		"totalRounds": d.totalRounds,
+	d.outgoing <- encodeMessage(DraftCompletedMessage{
+		Type:        MsgTypeDraftCompleted,
+		EventID:     d.eventID,
+		TotalPicks:  d.currentPickIndex,
+		TotalRounds: d.totalRounds,
 	})
-	d.outgoing <- msg
 
 	// Signal completion to DraftService
 	close(d.completed)
 }
 
-// MakePick processes a pick from a user
+// MakePick processes a pick from a user; ctx carries the pick's log attributes
+// provenance records who made it (manual or admin); picks made while paused are always admin picks
 // Returns error if invalid (not your turn, player unavailable, etc.)
-func (d *DraftState) MakePick(userID, playerID int) error {
+func (d *DraftState) MakePick(ctx context.Context, userID, playerID int, provenance string) error {
+	// The save runs without the mutex; the reservation holds the turn until it finishes
+	pickResult, err := d.claimPick(userID, playerID, provenance)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = d.savePick(ctx, pickResult)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err == nil {
+		err = d.applyPick(ctx, pickResult, start)
+	}
+	if err != nil {
+		d.releasePick()
+		slog.ErrorContext(ctx, "Failed to save pick", "pick_user_id", userID, "player_id", playerID, "err", err)
+		return fmt.Errorf("failed to save pick - please try again")
+	}
+
+	return nil
+}
+
+// claimPick validates a pick and reserves the current turn for it
+func (d *DraftState) claimPick(userID, playerID int, provenance string) (PickResult, error) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
 	if d.draftStatus != StatusInProgress && d.draftStatus != StatusPaused {
-		return fmt.Errorf("draft is not active")
+		return PickResult{}, fmt.Errorf("draft is not active")
+	}
+
+	if d.pickInFlight {
+		return PickResult{}, fmt.Errorf("a pick is already being saved")
 	}
 
 	if userID != d.currentTurnID {
-		return fmt.Errorf("not your turn")
+		return PickResult{}, fmt.Errorf("not your turn")
 	}
 
 	if !d.isPlayerAvailable(playerID) {
-		return fmt.Errorf("player not available")
+		return PickResult{}, fmt.Errorf("player not available")
 	}
 
-	// Stop the current timer (pick was made in time)
-	if d.pickTimer != nil {
-		d.pickTimer.Stop()
+	if !d.fitsRoster(userID, playerID) {
+		return PickResult{}, fmt.Errorf("player does not fit an open roster slot")
 	}
 
-	// If paused, resume the draft
-	if d.draftStatus == StatusPaused {
-		d.draftStatus = StatusInProgress
+	// Only the commissioner or an admin can pick while the draft is paused
+	if d.draftStatus == StatusPaused && provenance == models.PickProvenanceManual {
+		return PickResult{}, fmt.Errorf("draft is paused")
 	}
 
-	d.recordPick(userID, playerID, false)
-
-	return nil
+	return d.reservePick(userID, playerID, provenance), nil
 }
 
 // PauseDraft pauses the draft, stopping the timer and saving remaining time
@@ -291,25 +503,24 @@ func (d *DraftState) PauseDraft() error {
 		return fmt.Errorf("can only pause an in-progress draft")
 	}
 
-	// Calculate remaining time before stopping timer
-	d.remainingTime = max(time.Until(d.turnDeadline), 0)
-
-	// Stop the timer
-	if d.pickTimer != nil {
-		d.pickTimer.Stop()
-	}
+	d.pause(max(time.Until(d.turnDeadline), 0), "")
+	return nil
+}
 
+// pause stops the timer and broadcasts the pause, keeping remaining for the resume
+// reason is set when the server pauses the draft itself. Must be called while holding the mutex
+func (d *DraftState) pause(remaining time.Duration, reason string) {
+	d.remainingTime = remaining
+	d.stopTimer()
 	d.draftStatus = StatusPaused
 
 	// Emit draft paused message
-	msg, _ := json.Marshal(map[string]interface{}{
-		"type":          MsgTypeDraftPaused,
-		"eventID":       d.eventID,
-		"remainingTime": d.remainingTime.Seconds(),
+	d.outgoing <- encodeMessage(DraftPausedMessage{
+		Type:          MsgTypeDraftPaused,
+		EventID:       d.eventID,
+		RemainingTime: d.remainingTime.Seconds(),
+		Error:         reason,
 	})
-	d.outgoing <- msg
-
-	return nil
 }
 
 // ResumeDraft resumes a paused draft, restarting the timer with remaining time
@@ -327,14 +538,14 @@ func (d *DraftState) ResumeDraft() error {
 	d.startTimer(d.remainingTime)
 
 	// Emit draft resumed message
-	msg, _ := json.Marshal(map[string]interface{}{
-		"type":         MsgTypeDraftResumed,
-		"eventID":      d.eventID,
-		"currentTurn":  d.currentTurnID,
-		"roundNumber":  d.roundNumber,
-		"turnDeadline": d.turnDeadline.Unix(),
+	d.outgoing <- encodeMessage(DraftResumedMessage{
+		Type:         MsgTypeDraftResumed,
+		EventID:      d.eventID,
+		CurrentTurn:  d.currentTurnID,
+		RoundNumber:  d.roundNumber,
+		TurnDeadline: d.turnDeadline.Unix(),
 	})
-	d.outgoing <- msg
+	d.emitTurn()
 
 	return nil
 }
@@ -356,9 +567,16 @@ func (d *DraftState) Outgoing() <-chan []byte {
 	return d.outgoing
 }
 
-// PickResults returns the channel for reading completed picks (for persistence)
-func (d *DraftState) PickResults() <-chan PickResult {
-	return d.pickResults
+// Turns returns the channel of turns as they start or resume
+func (d *DraftState) Turns() <-chan TurnInfo {
+	return d.turns
+}
+
+// OnTheClock reports whether a pick is the current one and its turn is running (not paused)
+func (d *DraftState) OnTheClock(pickNumber int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draftStatus == StatusInProgress && d.currentPickIndex+1 == pickNumber
 }
 
 // GetCurrentTurn returns the user ID of the current turn
@@ -396,11 +614,46 @@ func (d *DraftState) SetAvailablePlayers(playerIDs []int) {
 	d.availablePlayers = playerIDs
 }
 
+// SetRosterRules sets the roster slots each team must fill and the positions of every player in the pool
+func (d *DraftState) SetRosterRules(slots models.RosterSlots, playerPositions map[int][]string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rosterSlots = slots
+	d.playerPositions = playerPositions
+}
+
+// SetPlayerRanks sets the rank of each ranked player in the pool, used to choose auto-draft picks
+func (d *DraftState) SetPlayerRanks(ranks map[int]int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.playerRanks = ranks
+}
+
+// SetTeams sets the draft board profiles of the event's teams
+func (d *DraftState) SetTeams(teams []TeamProfile) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.teams = teams
+}
+
+// bestRanked returns the candidate with the best (lowest) rank
+// Reports false if no candidate is ranked
+func (d *DraftState) bestRanked(candidates []int) (int, bool) {
+	best, bestRank := 0, 0
+	for _, id := range candidates {
+		rank, ok := d.playerRanks[id]
+		if ok && (bestRank == 0 || rank < bestRank) {
+			best, bestRank = id, rank
+		}
+	}
+	return best, bestRank != 0
+}
+
 // GetAvailablePlayers returns the available players for the draft
 func (d *DraftState) GetAvailablePlayers() []int {
 	d.mu.Lock()
 	defer d.mu.Unlock()
-	return d.availablePlayers
+	return slices.Clone(d.availablePlayers)
 }
 
 // Completed returns a channel that is closed when the draft completes
@@ -432,6 +685,12 @@ func (d *DraftState) GetSnapshot() DraftSnapshot {
 	pickHistory := make([]PickResult, len(d.pickHistory))
 	copy(pickHistory, d.pickHistory)
 
+	rosterSlots := make([]models.RosterSlot, len(d.rosterSlots))
+	copy(rosterSlots, d.rosterSlots)
+
+	teams := make([]TeamProfile, len(d.teams))
+	copy(teams, d.teams)
+
 	return DraftSnapshot{
 		EventID:          d.eventID,
 		Status:           d.draftStatus,
@@ -444,5 +703,7 @@ func (d *DraftState) GetSnapshot() DraftSnapshot {
 		TurnDeadline:     d.turnDeadline.Unix(),
 		RemainingTime:    remainingTime,
 		PickHistory:      pickHistory,
+		RosterSlots:      rosterSlots,
+		Teams:            teams,
 	}
 }
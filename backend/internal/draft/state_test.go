@@ -0,0 +1,283 @@
+package draft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// fakePickSaver records every save and can block or fail them
+type fakePickSaver struct {
+	mu      sync.Mutex
+	saves   []PickResult
+	err     error         // Returned by every save when set
+	release chan struct{} // Saves wait for this to close when set
+}
+
+func (f *fakePickSaver) SavePick(ctx context.Context, eventID, userID, playerID, pickNumber, round int, provenance string) error {
+	f.mu.Lock()
+	f.saves = append(f.saves, PickResult{EventID: eventID, UserID: userID, PlayerID: playerID, PickNumber: pickNumber, Round: round, Provenance: provenance})
+	release, err := f.release, f.err
+	f.mu.Unlock()
+
+	if release != nil {
+		<-release
+	}
+	return err
+}
+
+func (f *fakePickSaver) saved() []PickResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]PickResult(nil), f.saves...)
+}
+
+// startTestDraft starts a two-team draft and drains its outgoing messages into the returned channel
+func startTestDraft(t *testing.T, saver PickSaver, timer time.Duration, players []int) (*DraftState, <-chan []byte) {
+	t.Helper()
+	state := NewDraftState(1, saver)
+	if err := state.StartDraft([]int{1, 2}, 2, timer, players); err != nil {
+		t.Fatalf("StartDraft: %v", err)
+	}
+
+	messages := make(chan []byte, 1024)
+	go func() {
+		for msg := range state.Outgoing() {
+			messages <- msg
+		}
+	}()
+	t.Cleanup(func() {
+		state.mu.Lock()
+		state.stopTimer()
+		state.mu.Unlock()
+	})
+	return state, messages
+}
+
+// waitFor polls cond until it holds or the timeout passes
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTimerExpiringDuringSaveDoesNotAutoDraftNextTeam(t *testing.T) {
+	saver := &fakePickSaver{release: make(chan struct{})}
+	timer := 50 * time.Millisecond
+	state, _ := startTestDraft(t, saver, timer, []int{10, 11, 12, 13})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- state.MakePick(context.Background(), 1, 10, models.PickProvenanceManual)
+	}()
+
+	// Hold the save well past the turn's deadline
+	waitFor(t, time.Second, "the pick to be saved", func() bool { return len(saver.saved()) == 1 })
+	time.Sleep(3 * timer)
+
+	// Snapshots are not blocked by the save
+	snapshot := state.GetSnapshot()
+	if snapshot.CurrentPickIndex != 0 || len(snapshot.PickHistory) != 0 {
+		t.Fatalf("draft moved on before the save finished: pick index %d, history %v", snapshot.CurrentPickIndex, snapshot.PickHistory)
+	}
+
+	saver.mu.Lock()
+	release := saver.release
+	saver.release = nil // Later saves, e.g. a stray auto-draft, must not block
+	saver.mu.Unlock()
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("MakePick: %v", err)
+	}
+
+	// The expired timer must not have auto-drafted for team 2 once the pick was applied
+	time.Sleep(timer / 2)
+	saves := saver.saved()
+	if len(saves) != 1 {
+		t.Fatalf("expected only the manual pick to be saved, got %+v", saves)
+	}
+	snapshot = state.GetSnapshot()
+	if snapshot.CurrentTurn != 2 || len(snapshot.PickHistory) != 1 || snapshot.PickHistory[0].Provenance != models.PickProvenanceManual {
+		t.Fatalf("unexpected state after the pick: turn %d, history %+v", snapshot.CurrentTurn, snapshot.PickHistory)
+	}
+}
+
+func TestConcurrentPicksSaveOnlyOne(t *testing.T) {
+	saver := &fakePickSaver{release: make(chan struct{})}
+	state, _ := startTestDraft(t, saver, time.Minute, []int{10, 11, 12, 13, 14, 15})
+
+	// Every team and several players race for the first pick while the first save is held
+	type attempt struct{ userID, playerID int }
+	attempts := []attempt{{1, 10}, {1, 11}, {2, 12}, {1, 13}, {2, 14}, {1, 15}}
+	var wg sync.WaitGroup
+	errs := make([]error, len(attempts))
+	for i, a := range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = state.MakePick(context.Background(), a.userID, a.playerID, models.PickProvenanceManual)
+		}()
+	}
+
+	waitFor(t, time.Second, "a pick to be saved", func() bool { return len(saver.saved()) == 1 })
+	close(saver.release)
+	wg.Wait()
+
+	saves := saver.saved()
+	if len(saves) != 1 || saves[0].UserID != 1 || saves[0].PickNumber != 1 {
+		t.Fatalf("expected one save of pick 1 for team 1, got %+v", saves)
+	}
+	succeeded := 0
+	for i, err := range errs {
+		if err == nil {
+			succeeded++
+			if attempts[i].playerID != saves[0].PlayerID {
+				t.Fatalf("attempt %+v succeeded but player %d was saved", attempts[i], saves[0].PlayerID)
+			}
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one pick to succeed, got %d: %v", succeeded, errs)
+	}
+
+	// The saved pick is the only one applied, and the turn passes to team 2
+	snapshot := state.GetSnapshot()
+	if len(snapshot.PickHistory) != 1 || snapshot.PickHistory[0] != saves[0] || snapshot.CurrentTurn != 2 {
+		t.Fatalf("unexpected state after the race: turn %d, history %+v", snapshot.CurrentTurn, snapshot.PickHistory)
+	}
+}
+
+func TestFailedPickRestartsTimer(t *testing.T) {
+	saver := &fakePickSaver{err: errors.New("connection reset")}
+	autoDraftRetryDelay = time.Hour // Only the restarted turn timer may fire
+	t.Cleanup(func() { autoDraftRetryDelay = 5 * time.Second })
+	state, _ := startTestDraft(t, saver, 50*time.Millisecond, []int{10, 11})
+
+	if err := state.MakePick(context.Background(), 1, 10, models.PickProvenanceManual); err == nil {
+		t.Fatal("expected MakePick to fail when the save fails")
+	}
+
+	// The turn keeps its deadline, so the auto-draft still runs for team 1
+	waitFor(t, time.Second, "the auto-draft", func() bool { return len(saver.saved()) == 2 })
+	if auto := saver.saved()[1]; auto.UserID != 1 || auto.Provenance != models.PickProvenanceAuto {
+		t.Fatalf("expected an auto-draft for team 1, got %+v", auto)
+	}
+}
+
+func TestFailedAutoDraftPausesDraftAfterRetries(t *testing.T) {
+	saver := &fakePickSaver{err: errors.New("connection reset")}
+	autoDraftRetryDelay = 10 * time.Millisecond
+	t.Cleanup(func() { autoDraftRetryDelay = 5 * time.Second })
+	state, messages := startTestDraft(t, saver, 10*time.Millisecond, []int{10, 11, 12})
+
+	waitFor(t, time.Second, "the draft to pause", func() bool { return state.GetStatus() == StatusPaused })
+
+	// Give a runaway retry loop the chance to show itself
+	time.Sleep(10 * autoDraftRetryDelay)
+	if saves := saver.saved(); len(saves) != maxAutoDraftFailures {
+		t.Fatalf("expected %d auto-draft attempts, got %d", maxAutoDraftFailures, len(saves))
+	}
+
+	paused := findMessage[DraftPausedMessage](t, messages, MsgTypeDraftPaused)
+	if paused.Error == "" {
+		t.Fatal("expected the pause to say why the draft stopped")
+	}
+	if paused.RemainingTime != (10 * time.Millisecond).Seconds() {
+		t.Fatalf("expected resuming to give a full turn, got %v seconds", paused.RemainingTime)
+	}
+	if got := state.GetAvailablePlayers(); len(got) != 3 {
+		t.Fatalf("transient failures must not drop players, pool is %v", got)
+	}
+}
+
+func TestRejectedAutoDraftPlayerIsDropped(t *testing.T) {
+	saver := &fakePickSaver{err: fmt.Errorf("%w: drafted by the maximum number of teams", models.ErrPlayerNotDraftable)}
+	state, _ := startTestDraft(t, saver, 100*time.Millisecond, []int{10, 11, 12, 13})
+	state.SetPlayerRanks(map[int]int{10: 1, 11: 2, 12: 3, 13: 4})
+
+	waitFor(t, time.Second, "the draft to pause", func() bool { return state.GetStatus() == StatusPaused })
+
+	// Each attempt moves on to the next best player instead of retrying the rejected one
+	saves := saver.saved()
+	if len(saves) != maxAutoDraftFailures {
+		t.Fatalf("expected %d auto-draft attempts, got %+v", maxAutoDraftFailures, saves)
+	}
+	for i, save := range saves {
+		if save.PlayerID != 10+i {
+			t.Fatalf("attempt %d picked player %d, expected %d", i+1, save.PlayerID, 10+i)
+		}
+	}
+	if got := state.GetAvailablePlayers(); len(got) != 1 || got[0] != 13 {
+		t.Fatalf("expected only player 13 left in the pool, got %v", got)
+	}
+}
+
+func TestPausedDraftOnlyTakesAdminPicks(t *testing.T) {
+	saver := &fakePickSaver{}
+	state, _ := startTestDraft(t, saver, time.Minute, []int{10, 11})
+	if err := state.PauseDraft(); err != nil {
+		t.Fatalf("PauseDraft: %v", err)
+	}
+
+	if err := state.MakePick(context.Background(), 1, 10, models.PickProvenanceManual); err == nil {
+		t.Fatal("expected a manual pick to be refused while paused")
+	}
+	if err := state.MakePick(context.Background(), 1, 10, models.PickProvenanceAdmin); err != nil {
+		t.Fatalf("admin pick while paused: %v", err)
+	}
+	if saves := saver.saved(); len(saves) != 1 || saves[0].Provenance != models.PickProvenanceAdmin {
+		t.Fatalf("expected only the admin pick to be saved, got %+v", saves)
+	}
+}
+
+func TestKeeperPickWhilePaused(t *testing.T) {
+	saver := &fakePickSaver{}
+	state, _ := startTestDraft(t, saver, time.Minute, []int{10, 11})
+	if err := state.PauseDraft(); err != nil {
+		t.Fatalf("PauseDraft: %v", err)
+	}
+
+	if err := state.MakePick(context.Background(), 1, 10, models.PickProvenanceKeeper); err != nil {
+		t.Fatalf("keeper pick while paused: %v", err)
+	}
+	if saves := saver.saved(); len(saves) != 1 || saves[0].Provenance != models.PickProvenanceKeeper || saves[0].AutoDraft {
+		t.Fatalf("expected the keeper pick to be saved, got %+v", saves)
+	}
+}
+
+// findMessage returns the first outgoing message of a type received so far
+func findMessage[T any](t *testing.T, messages <-chan []byte, msgType string) T {
+	t.Helper()
+	for {
+		select {
+		case data := <-messages:
+			var envelope struct {
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal(data, &envelope); err != nil {
+				t.Fatalf("decode message: %v", err)
+			}
+			if envelope.Type != msgType {
+				continue
+			}
+			var msg T
+			if err := json.Unmarshal(data, &msg); err != nil {
+				t.Fatalf("decode %s: %v", msgType, err)
+			}
+			return msg
+		case <-time.After(time.Second):
+			t.Fatalf("no %s message was sent", msgType)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package draft
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/auth"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// TeamStore defines the interface for loading an event's teams and resetting a team's PIN and rejoin link
+type TeamStore interface {
+	GetByEvent(ctx context.Context, eventID int) ([]models.User, error)
+	ResetSecrets(ctx context.Context, eventID, userID int, pinHash *string) error
+}
+
+// TeamProfile is how a team is shown on the draft board
+type TeamProfile struct {
+	UserID      int    `json:"userID"`
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"` // The team's display name, or its username if it has none
+	Color       string `json:"color,omitempty"`
+	AvatarURL   string `json:"avatarURL,omitempty"`
+}
+
+// teamProfiles builds the draft board profiles of an event's teams
+func teamProfiles(users []models.User) []TeamProfile {
+	profiles := make([]TeamProfile, len(users))
+	for i, user := range users {
+		profiles[i] = TeamProfile{
+			UserID:      user.ID,
+			Username:    user.Username,
+			DisplayName: user.Name(),
+			AvatarURL:   user.AvatarURL,
+		}
+		if user.Color != nil {
+			profiles[i].Color = *user.Color
+		}
+	}
+	return profiles
+}
+
+// loadTeams sets the draft board profiles of the room's teams
+// Profiles are locked once the draft starts, so they are loaded once when it does.
+func (s *DraftService) loadTeams(state *DraftState) {
+	eventID := state.GetEventID()
+	users, err := s.teams.GetByEvent(context.Background(), eventID)
+	if err != nil {
+		slog.Error("Failed to load team profiles", "event_id", eventID, "err", err)
+		return
+	}
+	state.SetTeams(teamProfiles(users))
+}
+
+// ResetTeamSecretMessage represents the payload for resetting a team's PIN and rejoin link (commissioner only)
+// The rejoin link is cleared and the PIN replaced by Pin, or cleared if it is empty; a join
+// never sets the PIN of an existing team, so this is how a team gets a new one.
+type ResetTeamSecretMessage struct {
+	Type   string `json:"type"`
+	UserID int    `json:"userID" schema:"minimum=1"`
+	Pin    string `json:"pin,omitempty" schema:"minLength=4,maxLength=8"` // 4 to 8 digits
+}
+
+// TeamSecretResetMessage confirms to the commissioner that a team's PIN and rejoin link were cleared
+type TeamSecretResetMessage struct {
+	Type   string `json:"type"`
+	UserID int    `json:"userID"`
+}
+
+// handleResetTeamSecret resets a team's PIN and rejoin link (commissioner only)
+func (s *DraftService) handleResetTeamSecret(c *Client, data []byte) {
+	eventID, _, ok := s.currentChat()
+	if !ok {
+		c.SendError("no draft room created")
+		return
+	}
+
+	if !s.requireCommissioner(c, eventID) {
+		return
+	}
+
+	var msg ResetTeamSecretMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.SendError("invalid reset_team_secret message format")
+		return
+	}
+
+	var pinHash *string
+	if msg.Pin != "" {
+		if !auth.ValidPin(msg.Pin) {
+			c.SendError("PIN must be 4 to 8 digits")
+			return
+		}
+		hash, err := auth.HashSecret(msg.Pin)
+		if err != nil {
+			c.SendError("failed to set PIN")
+			return
+		}
+		pinHash = &hash
+	}
+
+	if err := s.teams.ResetSecrets(context.Background(), eventID, msg.UserID, pinHash); err != nil {
+		c.SendError("team not found")
+		return
+	}
+
+	c.enqueue(encodeMessage(TeamSecretResetMessage{
+		Type:   MsgTypeTeamSecretReset,
+		UserID: msg.UserID,
+	}))
+
+	slog.InfoContext(c.ctx, "Commissioner reset a team's PIN and rejoin link", "target_user_id", msg.UserID, "pin_set", pinHash != nil)
+}
@@ -0,0 +1,114 @@
+package export
+
+import (
+	"html/template"
+	"io"
+	"sort"
+)
+
+// boardCell is one square of the printable draft board
+type boardCell struct {
+	Pick  *Pick
+	Empty bool
+}
+
+// boardRow is one round of the printable draft board
+type boardRow struct {
+	Round int
+	Cells []boardCell
+}
+
+var boardTemplate = template.Must(template.New("board").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Event.Name}} - Draft Board</title>
+<style>
+  body { font-family: sans-serif; margin: 1.5em; }
+  h1 { margin-bottom: 0.2em; }
+  .meta { color: #555; margin: 0 0 1em; }
+  table { border-collapse: collapse; width: 100%; table-layout: fixed; }
+  th, td { border: 1px solid #999; padding: 0.4em; vertical-align: top; font-size: 0.9em; }
+  th { background: #eee; }
+  td.round { width: 4em; text-align: center; font-weight: bold; background: #eee; }
+  .pick { color: #777; font-size: 0.8em; }
+  .country { color: #555; }
+  td.auto { background: #fff4d6; }
+  .tag { font-size: 0.75em; font-weight: bold; text-transform: uppercase; color: #a86b00; }
+  @media print { body { margin: 0; } .legend { display: none; } }
+</style>
+</head>
+<body>
+<h1>{{.Event.Name}}</h1>
+<p class="meta">Status: {{.Event.Status}} &middot; {{.Event.MaxPicksPerTeam}} picks per team &middot; exported {{.ExportedAt.Format "2006-01-02 15:04 MST"}}</p>
+{{- if .Event.Stipulations}}
+<p class="meta">Stipulations:{{range $key, $value := .Event.Stipulations}} {{$key}}: {{$value}};{{end}}</p>
+{{- end}}
+<table>
+<thead>
+<tr><th>Round</th>{{range .Teams}}<th>{{.Username}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr><td class="round">{{.Round}}</td>
+{{- range .Cells}}
+{{- if .Empty}}<td></td>
+{{- else}}<td{{if .Pick.AutoDraft}} class="auto"{{end}}><span class="pick">#{{.Pick.PickNumber}}</span><br>{{.Pick.PlayerName}} <span class="country">({{.Pick.CountryCode}})</span>
+{{- if ne .Pick.Provenance "manual"}}<br><span class="tag">{{.Pick.Provenance}}</span>{{end}}</td>
+{{- end}}
+{{- end}}</tr>
+{{- end}}
+</tbody>
+</table>
+<p class="legend meta">Highlighted cells were auto-drafted when the pick timer expired.</p>
+</body>
+</html>
+`))
+
+// WriteHTML writes a printable draft board: one row per round, one column per team
+func (d *Document) WriteHTML(w io.Writer) error {
+	column := make(map[int]int, len(d.Teams))
+	for i, team := range d.Teams {
+		column[team.UserID] = i
+	}
+
+	rounds := make(map[int][]boardCell)
+	for i := range d.Picks {
+		pick := &d.Picks[i]
+		col, ok := column[pick.UserID]
+		if !ok {
+			continue
+		}
+		if _, ok := rounds[pick.Round]; !ok {
+			rounds[pick.Round] = emptyCells(len(d.Teams))
+		}
+		rounds[pick.Round][col] = boardCell{Pick: pick}
+	}
+
+	// Show every round up to the configured number, even ones nobody has picked in yet
+	for round := 1; round <= d.Event.MaxPicksPerTeam; round++ {
+		if _, ok := rounds[round]; !ok {
+			rounds[round] = emptyCells(len(d.Teams))
+		}
+	}
+
+	rows := make([]boardRow, 0, len(rounds))
+	for round, cells := range rounds {
+		rows = append(rows, boardRow{Round: round, Cells: cells})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Round < rows[j].Round })
+
+	return boardTemplate.Execute(w, struct {
+		*Document
+		Rows []boardRow
+	}{d, rows})
+}
+
+// emptyCells returns a board row with no picks in it
+func emptyCells(n int) []boardCell {
+	cells := make([]boardCell, n)
+	for i := range cells {
+		cells[i].Empty = true
+	}
+	return cells
+}
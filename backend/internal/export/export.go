@@ -0,0 +1,210 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// FormatVersion is bumped whenever the shape of the JSON export changes
+const FormatVersion = 1
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatHTML = "html"
+)
+
+// Document is the canonical export of an event's draft
+type Document struct {
+	FormatVersion int       `json:"formatVersion"`
+	ExportedAt    time.Time `json:"exportedAt"`
+	Event         Event     `json:"event"`
+	Teams         []Team    `json:"teams"`
+	Picks         []Pick    `json:"picks"`
+}
+
+// Event is the event metadata included in an export (never includes the passkey)
+type Event struct {
+	ID                int                 `json:"id"`
+	Name              string              `json:"name"`
+	Status            string              `json:"status"`
+	MaxPicksPerTeam   int                 `json:"maxPicksPerTeam"`
+	MaxTeamsPerPlayer int                 `json:"maxTeamsPerPlayer"`
+	Stipulations      models.Stipulations `json:"stipulations"`
+	CreatedAt         time.Time           `json:"createdAt"`
+	StartedAt         *time.Time          `json:"startedAt,omitempty"`
+	CompletedAt       *time.Time          `json:"completedAt,omitempty"`
+}
+
+// Team is a team in the export, listed in draft order
+type Team struct {
+	UserID   int    `json:"userID"`
+	Username string `json:"username"`
+}
+
+// Pick is a single pick in the export
+type Pick struct {
+	PickNumber  int    `json:"pickNumber"`
+	Round       int    `json:"round"`
+	UserID      int    `json:"userID"`
+	Username    string `json:"username"`
+	PlayerID    int    `json:"playerID"`
+	FirstName   string `json:"firstName"`
+	LastName    string `json:"lastName"`
+	CountryCode string `json:"countryCode"`
+	Status      string `json:"status"`
+	Provenance  string `json:"provenance"`
+	AutoDraft   bool   `json:"autoDraft"`
+}
+
+// PlayerName returns the player's full name
+func (p Pick) PlayerName() string {
+	return p.FirstName + " " + p.LastName
+}
+
+// NewDocument builds an export from an event, its teams and its picks (in pick order)
+func NewDocument(event *models.Event, users []models.User, picks []models.DraftPick, exportedAt time.Time) *Document {
+	stipulations := event.Stipulations
+	if stipulations == nil {
+		stipulations = models.Stipulations{}
+	}
+
+	doc := &Document{
+		FormatVersion: FormatVersion,
+		ExportedAt:    exportedAt.UTC(),
+		Event: Event{
+			ID:                event.ID,
+			Name:              event.Name,
+			Status:            event.Status,
+			MaxPicksPerTeam:   event.MaxPicksPerTeam,
+			MaxTeamsPerPlayer: event.MaxTeamsPerPlayer,
+			Stipulations:      stipulations,
+			CreatedAt:         event.CreatedAt,
+			StartedAt:         event.StartedAt,
+			CompletedAt:       event.CompletedAt,
+		},
+		Teams: draftOrder(users, picks),
+		Picks: make([]Pick, 0, len(picks)),
+	}
+
+	for _, p := range picks {
+		doc.Picks = append(doc.Picks, Pick{
+			PickNumber:  p.PickNumber,
+			Round:       p.Round,
+			UserID:      p.UserID,
+			Username:    p.Username,
+			PlayerID:    p.PlayerID,
+			FirstName:   p.Player.FirstName,
+			LastName:    p.Player.LastName,
+			CountryCode: p.Player.CountryCode,
+			Status:      p.Player.Status,
+			Provenance:  p.Provenance,
+			AutoDraft:   p.Provenance == models.PickProvenanceAuto,
+		})
+	}
+
+	return doc
+}
+
+// draftOrder lists teams in the order they picked in the first round,
+// followed by any teams that have not picked yet in join order
+func draftOrder(users []models.User, picks []models.DraftPick) []Team {
+	teams := make([]Team, 0, len(users))
+	seen := make(map[int]bool)
+	for _, p := range picks {
+		if p.Round != 1 || seen[p.UserID] {
+			continue
+		}
+		seen[p.UserID] = true
+		teams = append(teams, Team{UserID: p.UserID, Username: p.Username})
+	}
+	for _, u := range users {
+		if !seen[u.ID] {
+			seen[u.ID] = true
+			teams = append(teams, Team{UserID: u.ID, Username: u.Username})
+		}
+	}
+	return teams
+}
+
+// Write renders the document in the given format
+func (d *Document) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return d.WriteCSV(w)
+	case FormatJSON:
+		return d.WriteJSON(w)
+	case FormatHTML:
+		return d.WriteHTML(w)
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// ContentType returns the MIME type for an export format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// WriteJSON writes the canonical JSON document
+func (d *Document) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(d)
+}
+
+// WriteCSV writes the event metadata as key/value rows, a blank row, then one row per pick
+func (d *Document) WriteCSV(w io.Writer) error {
+	stipulations, err := json.Marshal(d.Event.Stipulations)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"event_id", strconv.Itoa(d.Event.ID)},
+		{"event_name", d.Event.Name},
+		{"status", d.Event.Status},
+		{"max_picks_per_team", strconv.Itoa(d.Event.MaxPicksPerTeam)},
+		{"max_teams_per_player", strconv.Itoa(d.Event.MaxTeamsPerPlayer)},
+		{"stipulations", string(stipulations)},
+		{"exported_at", d.ExportedAt.Format(time.RFC3339)},
+		{},
+		{"pick_number", "round", "user_id", "team", "player_id", "first_name", "last_name", "country_code", "player_status", "provenance", "auto_draft"},
+	}
+	for _, p := range d.Picks {
+		rows = append(rows, []string{
+			strconv.Itoa(p.PickNumber),
+			strconv.Itoa(p.Round),
+			strconv.Itoa(p.UserID),
+			p.Username,
+			strconv.Itoa(p.PlayerID),
+			p.FirstName,
+			p.LastName,
+			p.CountryCode,
+			p.Status,
+			p.Provenance,
+			strconv.FormatBool(p.AutoDraft),
+		})
+	}
+
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
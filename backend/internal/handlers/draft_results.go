@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/export"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+type DraftResultHandler struct {
+	draftResultRepo *repository.DraftResultRepository
+	eventRepo       *repository.EventRepository
+	userRepo        *repository.UserRepository
+}
+
+func NewDraftResultHandler(draftResultRepo *repository.DraftResultRepository, eventRepo *repository.EventRepository, userRepo *repository.UserRepository) *DraftResultHandler {
+	return &DraftResultHandler{
+		draftResultRepo: draftResultRepo,
+		eventRepo:       eventRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// GetResults handles GET /events/{id}/results
+// Returns the pick board so far, in pick order, for in-progress and completed drafts
+func (h *DraftResultHandler) GetResults(w http.ResponseWriter, r *http.Request) {
+	event, ok := h.getEvent(w, r)
+	if !ok {
+		return
+	}
+
+	picks, err := h.draftResultRepo.GetPicksByEvent(r.Context(), event.ID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to get draft results"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"eventID": event.ID,
+		"status":  event.Status,
+		"picks":   picks,
+	})
+}
+
+// GetTeams handles GET /events/{id}/teams
+// Returns every team in the event with its picks and how many picks it has left
+func (h *DraftResultHandler) GetTeams(w http.ResponseWriter, r *http.Request) {
+	event, ok := h.getEvent(w, r)
+	if !ok {
+		return
+	}
+
+	users, err := h.userRepo.GetByEvent(r.Context(), event.ID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to get teams"}`, http.StatusInternalServerError)
+		return
+	}
+
+	picks, err := h.draftResultRepo.GetPicksByEvent(r.Context(), event.ID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to get draft results"}`, http.StatusInternalServerError)
+		return
+	}
+
+	picksByUser := make(map[int][]models.DraftPick)
+	for _, pick := range picks {
+		picksByUser[pick.UserID] = append(picksByUser[pick.UserID], pick)
+	}
+
+	teams := make([]models.TeamRoster, 0, len(users))
+	for _, user := range users {
+		teams = append(teams, newTeamRoster(user, picksByUser[user.ID], event.MaxPicksPerTeam))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"eventID":         event.ID,
+		"status":          event.Status,
+		"maxPicksPerTeam": event.MaxPicksPerTeam,
+		"teams":           teams,
+	})
+}
+
+// GetTeamRoster handles GET /events/{id}/teams/{userID}/roster
+func (h *DraftResultHandler) GetTeamRoster(w http.ResponseWriter, r *http.Request) {
+	event, ok := h.getEvent(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid user ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "team not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+	if user.EventID != event.ID {
+		http.Error(w, `{"error": "team not found"}`, http.StatusNotFound)
+		return
+	}
+
+	picks, err := h.draftResultRepo.GetPicksByEventAndUser(r.Context(), event.ID, user.ID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to get roster"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newTeamRoster(*user, picks, event.MaxPicksPerTeam))
+}
+
+// ExportResults handles GET /events/{id}/export?format=csv|json|html
+// Renders the draft as a spreadsheet-friendly CSV, the canonical JSON document, or a printable HTML board
+func (h *DraftResultHandler) ExportResults(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatJSON
+	}
+	if format != export.FormatCSV && format != export.FormatJSON && format != export.FormatHTML {
+		http.Error(w, `{"error": "format must be csv, json or html"}`, http.StatusBadRequest)
+		return
+	}
+
+	event, ok := h.getEvent(w, r)
+	if !ok {
+		return
+	}
+
+	users, err := h.userRepo.GetByEvent(r.Context(), event.ID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to get teams"}`, http.StatusInternalServerError)
+		return
+	}
+
+	picks, err := h.draftResultRepo.GetPicksByEvent(r.Context(), event.ID)
+	if err != nil {
+		http.Error(w, `{"error": "failed to get draft results"}`, http.StatusInternalServerError)
+		return
+	}
+
+	doc := export.NewDocument(event, users, picks, time.Now())
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	if format != export.FormatHTML {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-draft.%s"`, event.ID, format))
+	}
+	w.WriteHeader(http.StatusOK)
+	if err := doc.Write(w, format); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to write export", "event_id", event.ID, "format", format, "err", err)
+	}
+}
+
+// getEvent loads the event named in the URL, writing an error response if it can't
+func (h *DraftResultHandler) getEvent(w http.ResponseWriter, r *http.Request) (*models.Event, bool) {
+	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	event, err := h.eventRepo.GetByID(r.Context(), eventID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "event not found"}`, http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return event, true
+}
+
+// newTeamRoster builds a team's roster from its picks
+func newTeamRoster(user models.User, picks []models.DraftPick, maxPicksPerTeam int) models.TeamRoster {
+	if picks == nil {
+		picks = []models.DraftPick{}
+	}
+	return models.TeamRoster{
+		UserID:         user.ID,
+		Username:       user.Username,
+		Picks:          picks,
+		RemainingPicks: max(maxPicksPerTeam-len(picks), 0),
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+	"github.com/sblackwood23/fantasy-draft-app/internal/testdb"
+)
+
+func TestDraftResultEndpoints(t *testing.T) {
+	pool := testdb.Open(t)
+	ctx := context.Background()
+	resultRepo := repository.NewDraftResultRepository(pool)
+	h := NewDraftResultHandler(resultRepo, repository.NewEventRepository(pool), repository.NewUserRepository(pool))
+
+	router := chi.NewRouter()
+	router.Get("/events/{id}/results", h.GetResults)
+	router.Get("/events/{id}/teams", h.GetTeams)
+	router.Get("/events/{id}/teams/{userID}/roster", h.GetTeamRoster)
+
+	eventID := testdb.Event(t, pool, 4)
+	team1 := testdb.Team(t, pool, eventID, "Team 1")
+	team2 := testdb.Team(t, pool, eventID, "Team 2")
+	otherTeam := testdb.Team(t, pool, testdb.Event(t, pool, 4), "Elsewhere")
+	players := testdb.Players(t, pool, eventID, 3)
+	if _, err := pool.Exec(ctx, `UPDATE players SET positions = '{QB}' WHERE id = $1`, players[0]); err != nil {
+		t.Fatalf("set positions: %v", err)
+	}
+
+	// Picks 1-3 of a snake draft: team 1, team 2, team 2
+	for i, pick := range []struct{ userID, playerID, round int }{
+		{team1, players[0], 1}, {team2, players[1], 1}, {team2, players[2], 2},
+	} {
+		if err := resultRepo.SavePick(ctx, eventID, pick.userID, pick.playerID, i+1, pick.round, models.PickProvenanceManual); err != nil {
+			t.Fatalf("SavePick: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		check  func(t *testing.T, body []byte)
+	}{
+		{
+			name:   "results in pick order",
+			path:   fmt.Sprintf("/events/%d/results", eventID),
+			status: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var resp struct {
+					Picks []models.DraftPick `json:"picks"`
+				}
+				decode(t, body, &resp)
+				if len(resp.Picks) != 3 {
+					t.Fatalf("expected 3 picks, got %d", len(resp.Picks))
+				}
+				for i, pick := range resp.Picks {
+					if pick.PickNumber != i+1 || pick.Player.Positions == nil || pick.Player.Attributes == nil {
+						t.Fatalf("unexpected pick %d: %+v", i+1, pick)
+					}
+				}
+				if first := resp.Picks[0]; first.Username != "Team 1" || len(first.Player.Positions) != 1 || first.Player.Positions[0] != "QB" {
+					t.Fatalf("first pick missing its team or player details: %+v", first)
+				}
+			},
+		},
+		{
+			name:   "teams with remaining picks",
+			path:   fmt.Sprintf("/events/%d/teams", eventID),
+			status: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var resp struct {
+					MaxPicksPerTeam int                 `json:"maxPicksPerTeam"`
+					Teams           []models.TeamRoster `json:"teams"`
+				}
+				decode(t, body, &resp)
+				if len(resp.Teams) != 2 || resp.Teams[0].UserID != team1 || resp.Teams[1].UserID != team2 {
+					t.Fatalf("expected both teams in join order, got %+v", resp.Teams)
+				}
+				if len(resp.Teams[0].Picks) != 1 || resp.Teams[0].RemainingPicks != resp.MaxPicksPerTeam-1 {
+					t.Fatalf("unexpected roster for team 1: %+v", resp.Teams[0])
+				}
+				if len(resp.Teams[1].Picks) != 2 || resp.Teams[1].RemainingPicks != resp.MaxPicksPerTeam-2 {
+					t.Fatalf("unexpected roster for team 2: %+v", resp.Teams[1])
+				}
+			},
+		},
+		{
+			name:   "one team's roster",
+			path:   fmt.Sprintf("/events/%d/teams/%d/roster", eventID, team2),
+			status: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var roster models.TeamRoster
+				decode(t, body, &roster)
+				if roster.UserID != team2 || len(roster.Picks) != 2 || roster.Picks[0].PickNumber != 2 || roster.Picks[1].PickNumber != 3 {
+					t.Fatalf("unexpected roster: %+v", roster)
+				}
+			},
+		},
+		{
+			name:   "team from another event",
+			path:   fmt.Sprintf("/events/%d/teams/%d/roster", eventID, otherTeam),
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "unknown event",
+			path:   "/events/-1/results",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "invalid event ID",
+			path:   "/events/abc/teams",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid user ID",
+			path:   fmt.Sprintf("/events/%d/teams/abc/roster", eventID),
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.check != nil {
+				tt.check(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+// decode unmarshals a JSON response body, failing the test if it can't
+func decode(t *testing.T, body []byte, v any) {
+	t.Helper()
+	if err := json.Unmarshal(body, v); err != nil {
+		t.Fatalf("decode response: %v: %s", err, body)
+	}
+}
@@ -2,11 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/auth"
 	"github.com/sblackwood23/fantasy-draft-app/internal/draft"
 	"github.com/sblackwood23/fantasy-draft-app/internal/models"
 	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
@@ -18,6 +24,11 @@ type DraftRoomHandler struct {
 	eventRepo       *repository.EventRepository
 	userRepo        *repository.UserRepository
 	draftService    *draft.DraftService
+	guard           *Guard
+	sessions        *auth.SessionSigner // Signs the session tokens draft room connections identify with
+	ipLimiter       *auth.Limiter       // Failed passkey and PIN attempts per client IP
+	eventLimiter    *auth.Limiter       // Failed passkey attempts per client IP and event
+	teamLimiter     *auth.Limiter       // Failed PIN and rejoin link attempts per team
 }
 
 // NewDraftRoomHandler creates a new DraftRoomHandler
@@ -26,12 +37,19 @@ func NewDraftRoomHandler(
 	eventRepo *repository.EventRepository,
 	userRepo *repository.UserRepository,
 	draftService *draft.DraftService,
+	guard *Guard,
+	sessions *auth.SessionSigner,
 ) *DraftRoomHandler {
 	return &DraftRoomHandler{
 		eventPlayerRepo: eventPlayerRepo,
 		eventRepo:       eventRepo,
 		userRepo:        userRepo,
 		draftService:    draftService,
+		guard:           guard,
+		sessions:        sessions,
+		ipLimiter:       auth.NewLimiter(maxPasskeyFailuresPerIP, passkeyFailureWindow, passkeyLockout),
+		eventLimiter:    auth.NewLimiter(maxPasskeyFailuresPerEvent, passkeyFailureWindow, passkeyLockout),
+		teamLimiter:     auth.NewLimiter(maxPinFailuresPerTeam, passkeyFailureWindow, passkeyLockout),
 	}
 }
 
@@ -44,20 +62,66 @@ func (h *DraftRoomHandler) CreateDraftRoom(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	event, err := h.eventRepo.GetByID(r.Context(), eventID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "Event not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Recreating the room would discard a running draft's state
+	var conflict string
+	if draftStarted(event.Status) {
+		conflict = "cannot recreate the draft room once the draft has started"
+	}
+	audit, ok := h.guard.Permit(w, r, eventID, models.AuditActionRecreateRoom, conflict)
+	if !ok {
+		return
+	}
+
 	// Get available players for this event from the database
-	playerIDs, err := h.eventPlayerRepo.GetPlayerIDsByEvent(r.Context(), eventID)
+	players, err := h.eventPlayerRepo.GetPlayersByEvent(r.Context(), eventID)
 	if err != nil {
 		http.Error(w, `{"error": "Failed to get players"}`, http.StatusInternalServerError)
 		return
 	}
 
-	if len(playerIDs) == 0 {
+	if len(players) == 0 {
 		http.Error(w, `{"error": "No players assigned to this event"}`, http.StatusBadRequest)
 		return
 	}
 
+	playerIDs := make([]int, len(players))
+	positions := make(map[int][]string, len(players))
+	ranks := make(map[int]int)
+	for i, player := range players {
+		playerIDs[i] = player.ID
+		positions[player.ID] = player.Positions
+		if player.Rank != nil {
+			ranks[player.ID] = *player.Rank
+		}
+	}
+
 	// Delegate to draft handler to create the room
-	if err := h.draftService.CreateRoom(eventID, playerIDs); err != nil {
+	config := draft.RoomConfig{
+		PlayerIDs:       playerIDs,
+		RosterSlots:     event.RosterSlots,
+		PlayerPositions: positions,
+		PlayerRanks:     ranks,
+	}
+
+	// The room is not in the database, so a forced recreate is audited before the room is replaced
+	err = h.guard.Apply(r.Context(), audit, func(tx pgx.Tx) (any, error) {
+		return map[string]any{"status": event.Status, "players": len(playerIDs)}, nil
+	})
+	if err != nil {
+		http.Error(w, `{"error": "Failed to record the audit entry"}`, http.StatusInternalServerError)
+		return
+	}
+	if err := h.draftService.CreateRoom(eventID, config); err != nil {
 		http.Error(w, `{"error": "Failed to create draft room"}`, http.StatusInternalServerError)
 		return
 	}
@@ -96,13 +160,99 @@ func (h *DraftRoomHandler) GetDraftRoom(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// GetDraftRoomClients handles GET /events/{id}/draft-room/clients
+// Returns per-connection outgoing queue depth for diagnosing slow consumers
+func (h *DraftRoomHandler) GetDraftRoomClients(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "Invalid event ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	room := h.draftService.GetRoom()
+	if room == nil || room.GetEventID() != eventID {
+		http.Error(w, `{"error": "No draft room for this event"}`, http.StatusNotFound)
+		return
+	}
+
+	clients, evicted := h.draftService.GetClientStats()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"eventID": eventID,
+		"clients": clients,
+		"evicted": evicted,
+	})
+}
+
+// joinResponse is a joined team, with its new rejoin link token when one was issued
+// and the session token its draft room connection identifies with
+type joinResponse struct {
+	*models.User
+	RejoinToken      string    `json:"rejoinToken,omitempty"` // Shown once; only its hash is stored
+	SessionToken     string    `json:"sessionToken"`
+	SessionExpiresAt time.Time `json:"sessionExpiresAt"`
+}
+
+// writeJoin writes the response for a joined team, issuing its session token
+func (h *DraftRoomHandler) writeJoin(w http.ResponseWriter, status int, user *models.User, rejoinToken string) {
+	token, expiresAt := h.sessions.Issue(user.EventID, user.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(joinResponse{
+		User:             user,
+		RejoinToken:      rejoinToken,
+		SessionToken:     token,
+		SessionExpiresAt: expiresAt,
+	})
+}
+
+// CreateAdminSession handles POST /events/{id}/admin-session
+// Issues an admin session token for the event's draft room; requires the admin token.
+// An admin connection has every commissioner power and its picks are recorded as admin picks.
+func (h *DraftRoomHandler) CreateAdminSession(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "Invalid event ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !h.guard.RequireAdmin(w, r) {
+		return
+	}
+
+	if _, err := h.eventRepo.GetByID(r.Context(), eventID); err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "Event not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	token, expiresAt := h.sessions.IssueAdmin(eventID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"eventID":          eventID,
+		"sessionToken":     token,
+		"sessionExpiresAt": expiresAt,
+	})
+}
+
 // JoinEvent handles POST /events/join
-// Validates passkey and registers/authenticates user for the draft
+// Validates the event ID and passkey and registers/authenticates user for the draft
+// A team with a PIN or rejoin link can only be reclaimed with one of them
 func (h *DraftRoomHandler) JoinEvent(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req struct {
-		TeamName string `json:"teamName"`
-		Passkey  string `json:"passkey"`
+		EventID     int    `json:"eventID"`
+		TeamName    string `json:"teamName"`
+		Passkey     string `json:"passkey"`
+		Pin         string `json:"pin"`         // Sets a new team's PIN, or reclaims a team that has one
+		RejoinToken string `json:"rejoinToken"` // Reclaims a team from its rejoin link
+		RejoinLink  bool   `json:"rejoinLink"`  // Issue a new one-time rejoin link token
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, `{"error": "Invalid JSON"}`, http.StatusBadRequest)
@@ -114,29 +264,26 @@ func (h *DraftRoomHandler) JoinEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Passkey == "" {
-		http.Error(w, `{"error": "Passkey is required"}`, http.StatusBadRequest)
+	if req.EventID == 0 || req.Passkey == "" {
+		http.Error(w, `{"error": "Event ID and Passkey are required"}`, http.StatusBadRequest)
 		return
 	}
 
-	// Look up event by passkey
-	event, err := h.eventRepo.GetByPasskey(r.Context(), req.Passkey)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			http.Error(w, `{"error": "Invalid Passkey"}`, http.StatusUnauthorized)
-			return
-		}
-		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+	if req.Pin != "" && !auth.ValidPin(req.Pin) {
+		http.Error(w, `{"error": "PIN must be 4 to 8 digits"}`, http.StatusBadRequest)
+		return
+	}
+
+	event := h.authenticateEvent(w, r, req.EventID, req.Passkey)
+	if event == nil {
 		return
 	}
 
 	// Check if user already exists for this event
 	existingUser, err := h.userRepo.GetByEventAndUsername(r.Context(), event.ID, req.TeamName)
 	if err == nil {
-		// User exists - return success (reconnection case)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(existingUser)
+		// User exists - reconnection case
+		h.reclaimTeam(w, r, existingUser, req.Pin, req.RejoinToken, req.RejoinLink)
 		return
 	}
 
@@ -145,30 +292,328 @@ func (h *DraftRoomHandler) JoinEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// User doesn't exist - check if room has capacity
-	const maxTeams = 12
-	count, err := h.userRepo.CountByEvent(r.Context(), event.ID)
+	// Early check for a friendly error; Register re-checks the window atomically with capacity
+	if reason := event.RegistrationClosedReason(time.Now()); reason != "" {
+		writeRegistrationClosed(w, reason)
+		return
+	}
+
+	// Create new user for this event, with the PIN and rejoin link it asked for
+	newUser := &models.User{
+		EventID:  event.ID,
+		Username: req.TeamName,
+	}
+	if req.Pin != "" {
+		hash, err := auth.HashSecret(req.Pin)
+		if err != nil {
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		newUser.PinHash = &hash
+	}
+	var token string
+	if req.RejoinLink {
+		if token, newUser.RejoinTokenHash, err = newRejoinToken(); err != nil {
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+	// The first team to join an event becomes its commissioner
+	if err := h.userRepo.Register(r.Context(), newUser, time.Now()); err != nil {
+		var closed *repository.RegistrationClosedError
+		switch {
+		case errors.Is(err, repository.ErrEventFull):
+			http.Error(w, `{"error": "Draft room is full"}`, http.StatusConflict)
+		case errors.Is(err, repository.ErrTeamNameTaken):
+			http.Error(w, `{"error": "Team name was just taken - try again"}`, http.StatusConflict)
+		case errors.As(err, &closed):
+			writeRegistrationClosed(w, closed.Reason)
+		default:
+			http.Error(w, `{"error": "Failed to register team"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeJoin(w, http.StatusCreated, newUser, token)
+}
+
+// writeRegistrationClosed responds that an event is not taking new teams
+func writeRegistrationClosed(w http.ResponseWriter, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]string{"error": reason})
+}
+
+// GetEventRoster handles GET /events/{id}/roster
+// Lists the teams registered for an event along with its registration settings
+func (h *DraftRoomHandler) GetEventRoster(w http.ResponseWriter, r *http.Request) {
+	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
+		http.Error(w, `{"error": "Invalid event ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.eventRepo.GetByID(r.Context(), eventID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "Event not found"}`, http.StatusNotFound)
+			return
+		}
 		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
 		return
 	}
 
-	if count >= maxTeams {
-		http.Error(w, `{"error": "Draft room is full"}`, http.StatusConflict)
+	teams, err := h.userRepo.GetByEvent(r.Context(), eventID)
+	if err != nil {
+		http.Error(w, `{"error": "Failed to get teams"}`, http.StatusInternalServerError)
 		return
 	}
 
-	// Create new user for this event
-	newUser := &models.User{
-		EventID:  event.ID,
-		Username: req.TeamName,
+	closedReason := event.RegistrationClosedReason(time.Now())
+	if closedReason == "" && len(teams) >= event.MaxTeams {
+		closedReason = "draft room is full"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"eventID":              event.ID,
+		"status":               event.Status,
+		"maxTeams":             event.MaxTeams,
+		"registrationOpensAt":  event.RegistrationOpensAt,
+		"registrationClosesAt": event.RegistrationClosesAt,
+		"lateRegistration":     event.LateRegistration,
+		"registrationOpen":     closedReason == "",
+		"closedReason":         closedReason,
+		"commissionerUserID":   event.CommissionerID,
+		"teams":                teams,
+	})
+}
+
+// reclaimTeam lets a join take over an existing team
+// A team without a PIN or rejoin link is reclaimed by name alone, but cannot be given either:
+// that would let whoever typed its name first lock its owner out. Only the commissioner's
+// reset_team_secret sets the PIN of an existing team. An unclaimed team (copied by a clone)
+// cannot be joined at all until the commissioner resets it.
+// A protected team needs its PIN or an unused rejoin link; using a link replaces it with a new one.
+func (h *DraftRoomHandler) reclaimTeam(w http.ResponseWriter, r *http.Request, user *models.User, pin, rejoinToken string, rejoinLink bool) {
+	ctx := r.Context()
+	pinHash := user.PinHash
+	var token string
+	var err error
+
+	if !user.Protected() {
+		if user.Unclaimed {
+			http.Error(w, `{"error": "This team was copied from another event - ask the commissioner to set its PIN"}`, http.StatusForbidden)
+			return
+		}
+		if pin != "" || rejoinLink {
+			http.Error(w, `{"error": "A PIN or rejoin link can only be set when the team is created - ask the commissioner to set one"}`, http.StatusForbidden)
+			return
+		}
+		h.writeJoin(w, http.StatusOK, user, "")
+		return
 	}
-	if err := h.userRepo.Create(r.Context(), newUser); err != nil {
-		http.Error(w, `{"error": "Failed to register team"}`, http.StatusInternalServerError)
+
+	teamKey := strconv.Itoa(user.ID)
+	if locked, retryAfter := h.teamLimiter.Locked(teamKey); locked {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		http.Error(w, `{"error": "Too many failed attempts - try again later"}`, http.StatusTooManyRequests)
 		return
 	}
 
+	if pin == "" && rejoinToken == "" {
+		http.Error(w, `{"error": "This team is protected - enter its PIN or use its rejoin link"}`, http.StatusUnauthorized)
+		return
+	}
+
+	// A rejoin link is single-use: swap its hash for a new link's so a second use fails
+	if rejoinToken != "" && user.RejoinTokenHash != nil {
+		var newHash *string
+		if token, newHash, err = newRejoinToken(); err != nil {
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		ok, err := h.userRepo.RotateRejoinToken(ctx, user.ID, auth.HashToken(rejoinToken), *newHash)
+		if err != nil {
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		if ok {
+			user.RejoinTokenHash = newHash
+			user.SetComputedFields()
+			h.writeJoin(w, http.StatusOK, user, token)
+			return
+		}
+	}
+
+	var ok, rehash bool
+	if pin != "" && user.PinHash != nil {
+		ok, rehash = auth.VerifySecret(pin, *user.PinHash)
+	} else if pin != "" {
+		ok = auth.VerifyNoSecret(pin)
+	}
+	if !ok {
+		h.ipLimiter.Fail(clientIP(r))
+		h.teamLimiter.Fail(teamKey)
+		http.Error(w, `{"error": "Invalid PIN or rejoin link"}`, http.StatusUnauthorized)
+		return
+	}
+
+	if rehash {
+		if hash, err := auth.HashSecret(pin); err == nil {
+			pinHash = &hash
+		}
+	}
+	if !rehash && !rejoinLink {
+		h.writeJoin(w, http.StatusOK, user, "")
+		return
+	}
+	h.saveTeamSecrets(w, r, user, pinHash, rejoinLink)
+}
+
+// saveTeamSecrets stores a reclaimed team's PIN hash, issuing a new rejoin link if asked
+// (which replaces any unused one), and writes the join response
+func (h *DraftRoomHandler) saveTeamSecrets(w http.ResponseWriter, r *http.Request, user *models.User, pinHash *string, rejoinLink bool) {
+	tokenHash := user.RejoinTokenHash
+	var token string
+	if rejoinLink {
+		var err error
+		if token, tokenHash, err = newRejoinToken(); err != nil {
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := h.userRepo.SetSecrets(r.Context(), user.ID, pinHash, tokenHash); err != nil {
+		http.Error(w, `{"error": "Failed to save team PIN"}`, http.StatusInternalServerError)
+		return
+	}
+	user.PinHash = pinHash
+	user.RejoinTokenHash = tokenHash
+	user.SetComputedFields()
+
+	h.writeJoin(w, http.StatusOK, user, token)
+}
+
+// newRejoinToken creates a rejoin link token and the hash to store for it
+func newRejoinToken() (string, *string, error) {
+	token, err := auth.NewToken()
+	if err != nil {
+		return "", nil, err
+	}
+	hash := auth.HashToken(token)
+	return token, &hash, nil
+}
+
+// SpectateEvent handles POST /events/spectate
+// Validates the event ID, passkey and spectator capacity without registering a team
+// Spectators then connect to /ws/draft?spectator=true
+func (h *DraftRoomHandler) SpectateEvent(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		EventID int    `json:"eventID"`
+		Passkey string `json:"passkey"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.EventID == 0 || req.Passkey == "" {
+		http.Error(w, `{"error": "Event ID and Passkey are required"}`, http.StatusBadRequest)
+		return
+	}
+
+	event := h.authenticateEvent(w, r, req.EventID, req.Passkey)
+	if event == nil {
+		return
+	}
+
+	// Early capacity check for a friendly error; the room enforces the limit on connect
+	room := h.draftService.GetRoom()
+	if room != nil && room.GetEventID() == event.ID && h.draftService.GetSpectatorCount() >= event.MaxSpectators {
+		http.Error(w, `{"error": "Spectator limit reached"}`, http.StatusConflict)
+		return
+	}
+
+	// The spectator session token is what lets the client into the draft room and feed
+	token, expiresAt := h.sessions.IssueSpectator(event.ID)
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(newUser)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"eventID":          event.ID,
+		"eventName":        event.Name,
+		"spectator":        true,
+		"maxSpectators":    event.MaxSpectators,
+		"sessionToken":     token,
+		"sessionExpiresAt": expiresAt,
+	})
+}
+
+// Passkey guessing limits: failed attempts per client IP across all events, and per client IP
+// for one event. Nothing is counted per event alone, so guessing can't lock everyone out of an event.
+// PIN guesses count against the client IP and the team
+const (
+	maxPasskeyFailuresPerIP    = 30
+	maxPasskeyFailuresPerEvent = 10
+	maxPinFailuresPerTeam      = 5
+	passkeyFailureWindow       = 15 * time.Minute
+	passkeyLockout             = 15 * time.Minute
+)
+
+// authenticateEvent checks an event ID and passkey, enforcing the passkey guessing limits
+// Writes the error response and returns nil if the event cannot be entered
+func (h *DraftRoomHandler) authenticateEvent(w http.ResponseWriter, r *http.Request, eventID int, passkey string) *models.Event {
+	ipKey := clientIP(r)
+	eventKey := ipKey + "|" + strconv.Itoa(eventID)
+	for _, lock := range []struct {
+		limiter *auth.Limiter
+		key     string
+	}{{h.ipLimiter, ipKey}, {h.eventLimiter, eventKey}} {
+		if locked, retryAfter := lock.limiter.Locked(lock.key); locked {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			http.Error(w, `{"error": "Too many failed attempts - try again later"}`, http.StatusTooManyRequests)
+			return nil
+		}
+	}
+
+	event, err := h.eventRepo.GetByID(r.Context(), eventID)
+	if err != nil && err != pgx.ErrNoRows {
+		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		return nil
+	}
+
+	// Unknown events and wrong passkeys get the same response so event IDs can't be probed
+	var ok, rehash bool
+	if event != nil && event.PasskeyHash != nil {
+		ok, rehash = auth.VerifySecret(passkey, *event.PasskeyHash)
+	} else {
+		ok = auth.VerifyNoSecret(passkey)
+	}
+	if !ok {
+		h.ipLimiter.Fail(ipKey)
+		h.eventLimiter.Fail(eventKey)
+		http.Error(w, `{"error": "Invalid event or passkey"}`, http.StatusUnauthorized)
+		return nil
+	}
+
+	if rehash {
+		if hash, err := auth.HashSecret(passkey); err == nil {
+			if err := h.eventRepo.SetPasskeyHash(r.Context(), event.ID, hash); err != nil {
+				slog.WarnContext(r.Context(), "Failed to upgrade passkey hash", "event_id", event.ID, "err", err)
+			}
+		}
+	}
+
+	return event
+}
+
+// clientIP returns the IP address a request came from
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
 }
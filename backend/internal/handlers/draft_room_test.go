@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/auth"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+	"github.com/sblackwood23/fantasy-draft-app/internal/testdb"
+)
+
+func TestPasskeyLimitsAreKeyedByIPAndEvent(t *testing.T) {
+	pool := testdb.Open(t)
+	h := NewDraftRoomHandler(nil, repository.NewEventRepository(pool), nil, nil, nil, nil)
+
+	hash, err := auth.HashSecret("open-sesame")
+	if err != nil {
+		t.Fatalf("HashSecret: %v", err)
+	}
+	events := make([]int, 5)
+	for i := range events {
+		events[i] = testdb.Event(t, pool, 4)
+	}
+	event1, event2 := events[0], events[1]
+	for _, id := range events {
+		if _, err := pool.Exec(context.Background(), `UPDATE events SET passkey_hash = $2 WHERE id = $1`, id, hash); err != nil {
+			t.Fatalf("set passkey: %v", err)
+		}
+	}
+
+	attempt := func(ip string, eventID int, passkey string) int {
+		r := httptest.NewRequest(http.MethodPost, "/events/join", nil)
+		r.RemoteAddr = ip + ":40000"
+		w := httptest.NewRecorder()
+		h.authenticateEvent(w, r, eventID, passkey)
+		return w.Code
+	}
+
+	// Lock 10.0.0.1 out of event 1
+	for range maxPasskeyFailuresPerEvent {
+		if code := attempt("10.0.0.1", event1, "wrong"); code != http.StatusUnauthorized {
+			t.Fatalf("wrong passkey: status %d, want 401", code)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ip      string
+		eventID int
+		passkey string
+		want    int
+	}{
+		{"locked IP and event, right passkey", "10.0.0.1", event1, "open-sesame", http.StatusTooManyRequests},
+		{"locked IP, other event", "10.0.0.1", event2, "open-sesame", http.StatusOK},
+		{"other IP, locked event", "10.0.0.2", event1, "open-sesame", http.StatusOK},
+		{"other IP, wrong passkey", "10.0.0.2", event1, "wrong", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := attempt(tt.ip, tt.eventID, tt.passkey); code != tt.want {
+				t.Fatalf("status %d, want %d", code, tt.want)
+			}
+		})
+	}
+
+	// Failures across events, none of them enough to lock an event, add up until the IP is locked everywhere
+	for i := range maxPasskeyFailuresPerIP {
+		if code := attempt("10.0.0.3", events[i%4], "wrong"); code != http.StatusUnauthorized {
+			t.Fatalf("failure %d: status %d, want 401", i+1, code)
+		}
+	}
+	if code := attempt("10.0.0.3", events[4], "open-sesame"); code != http.StatusTooManyRequests {
+		t.Fatalf("IP over its limit across events: status %d, want 429", code)
+	}
+}
@@ -1,23 +1,36 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
 	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
 )
 
 type EventPlayerHandler struct {
-	repo *repository.EventPlayerRepository
+	repo      *repository.EventPlayerRepository
+	eventRepo *repository.EventRepository
+	guard     *Guard
 }
 
-func NewEventPlayerHandler(repo *repository.EventPlayerRepository) *EventPlayerHandler {
-	return &EventPlayerHandler{repo: repo}
+func NewEventPlayerHandler(repo *repository.EventPlayerRepository, eventRepo *repository.EventRepository, guard *Guard) *EventPlayerHandler {
+	return &EventPlayerHandler{
+		repo:      repo,
+		eventRepo: eventRepo,
+		guard:     guard,
+	}
 }
 
 // GetEventPlayers handles GET /events/{id}/players
+// Accepts the same query parameters as GET /players, plus available=true to exclude drafted players
+// Players are ordered by the event's ranking source unless sort is given
 func (h *EventPlayerHandler) GetEventPlayers(w http.ResponseWriter, r *http.Request) {
 	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
@@ -25,7 +38,14 @@ func (h *EventPlayerHandler) GetEventPlayers(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	players, err := h.repo.GetPlayersByEvent(r.Context(), eventID)
+	filter, err := parsePlayerFilter(r)
+	if err != nil {
+		writePlayerFilterError(w, err)
+		return
+	}
+	filter.AvailableOnly = r.URL.Query().Get("available") == "true"
+
+	players, total, err := h.repo.SearchPlayersByEvent(r.Context(), eventID, filter)
 	if err != nil {
 		http.Error(w, `{"error": "failed to get players"}`, http.StatusInternalServerError)
 		return
@@ -33,12 +53,30 @@ func (h *EventPlayerHandler) GetEventPlayers(w http.ResponseWriter, r *http.Requ
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(players)
+	json.NewEncoder(w).Encode(playerPage(players, total, filter))
 }
 
 // AddEventPlayers handles POST /events/{id}/players
 // Accepts: {"playerIDs": [1, 2, 3]}
 func (h *EventPlayerHandler) AddEventPlayers(w http.ResponseWriter, r *http.Request) {
+	h.changePool(w, r, http.StatusCreated, h.repo.AddPlayersTx)
+}
+
+// ReplaceEventPlayers handles PUT /events/{id}/players
+// Accepts: {"playerIDs": [1, 2, 3]} - the pool becomes exactly these players
+func (h *EventPlayerHandler) ReplaceEventPlayers(w http.ResponseWriter, r *http.Request) {
+	h.changePool(w, r, http.StatusOK, h.repo.ReplacePlayersTx)
+}
+
+// RemoveEventPlayers handles DELETE /events/{id}/players
+// Accepts: {"playerIDs": [1, 2, 3]}
+func (h *EventPlayerHandler) RemoveEventPlayers(w http.ResponseWriter, r *http.Request) {
+	h.changePool(w, r, http.StatusOK, h.repo.RemovePlayersTx)
+}
+
+// changePool decodes a list of player IDs and applies a bulk pool change, writing the PoolChange
+func (h *EventPlayerHandler) changePool(w http.ResponseWriter, r *http.Request, status int,
+	apply func(ctx context.Context, tx pgx.Tx, eventID int, playerIDs []int, force bool) (*repository.PoolChange, error)) {
 	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
@@ -48,19 +86,30 @@ func (h *EventPlayerHandler) AddEventPlayers(w http.ResponseWriter, r *http.Requ
 	var body struct {
 		PlayerIDs []int `json:"playerIDs"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PlayerIDs == nil {
+		http.Error(w, `{"error": "invalid JSON - expected {\"playerIDs\": [...]}"}`, http.StatusBadRequest)
 		return
 	}
 
-	if err := h.repo.AddPlayersToEvent(r.Context(), eventID, body.PlayerIDs); err != nil {
-		http.Error(w, `{"error": "failed to add players"}`, http.StatusInternalServerError)
+	audit, ok := h.permitPoolChange(w, r, eventID)
+	if !ok {
+		return
+	}
+
+	var change *repository.PoolChange
+	err = h.guard.Apply(r.Context(), audit, func(tx pgx.Tx) (any, error) {
+		var err error
+		change, err = apply(r.Context(), tx, eventID, body.PlayerIDs, audit != nil)
+		return change, err
+	})
+	if err != nil {
+		writePoolError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"status": "players added"})
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(change)
 }
 
 // RemoveEventPlayer handles DELETE /events/{id}/players/{playerID}
@@ -77,10 +126,55 @@ func (h *EventPlayerHandler) RemoveEventPlayer(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if err := h.repo.RemovePlayerFromEvent(r.Context(), eventID, playerID); err != nil {
-		http.Error(w, `{"error": "failed to remove player"}`, http.StatusInternalServerError)
+	audit, ok := h.permitPoolChange(w, r, eventID)
+	if !ok {
+		return
+	}
+
+	err = h.guard.Apply(r.Context(), audit, func(tx pgx.Tx) (any, error) {
+		return h.repo.RemovePlayersTx(r.Context(), tx, eventID, []int{playerID}, audit != nil)
+	})
+	if err != nil {
+		writePoolError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// permitPoolChange checks an event's pool may change, allowing an admin force-edit once the draft
+// has started. The repository re-checks the status atomically unless the change is forced.
+func (h *EventPlayerHandler) permitPoolChange(w http.ResponseWriter, r *http.Request, eventID int) (*models.AuditEntry, bool) {
+	event, err := h.eventRepo.GetByID(r.Context(), eventID)
+	if err != nil {
+		writePoolError(w, r, err)
+		return nil, false
+	}
+
+	var conflict string
+	if draftStarted(event.Status) {
+		conflict = repository.ErrPoolLocked.Error()
+	}
+	return h.guard.PermitUnlessRunning(w, r, eventID, models.AuditActionChangePool, conflict)
+}
+
+// writePoolError maps an error from a pool change to a response
+func writePoolError(w http.ResponseWriter, r *http.Request, err error) {
+	var unknown *repository.UnknownPlayersError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, `{"error": "event not found"}`, http.StatusNotFound)
+	case errors.Is(err, repository.ErrPoolLocked):
+		http.Error(w, `{"error": "player pool cannot change once the draft has started"}`, http.StatusConflict)
+	case errors.As(err, &unknown):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"error":     "unknown player IDs",
+			"playerIDs": unknown.PlayerIDs,
+		})
+	default:
+		slog.ErrorContext(r.Context(), "Failed to change player pool", "err", err)
+		http.Error(w, `{"error": "failed to update player pool"}`, http.StatusInternalServerError)
+	}
+}
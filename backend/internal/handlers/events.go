@@ -2,21 +2,25 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/auth"
 	"github.com/sblackwood23/fantasy-draft-app/internal/models"
 	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
 )
 
 type EventHandler struct {
-	repo *repository.EventRepository
+	repo  *repository.EventRepository
+	guard *Guard
 }
 
-func NewEventHandler(repo *repository.EventRepository) *EventHandler {
-	return &EventHandler{repo: repo}
+func NewEventHandler(repo *repository.EventRepository, guard *Guard) *EventHandler {
+	return &EventHandler{repo: repo, guard: guard}
 }
 
 // GetEvent handles GET /events/{id}
@@ -61,12 +65,29 @@ func (h *EventHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *EventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
-	var event models.Event
+	var req eventRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
 		return
 	}
+	event := req.Event
+	event.CommissionerID = nil // Read-only: the first team to join becomes the commissioner
+
+	if !setPasskey(w, &event, req.Passkey) {
+		return
+	}
+
+	if event.MaxTeams == 0 {
+		event.MaxTeams = models.DefaultMaxTeams
+	}
+
+	if err := validateEvent(&event); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
 
 	if err := h.repo.Create(r.Context(), &event); err != nil {
 		http.Error(w, `{"error": "failed to create event"}`, http.StatusInternalServerError)
@@ -79,8 +100,9 @@ func (h *EventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 }
 
 // Handles PUT /events{id}
+// A passkey left out of the body keeps the current one; "" removes it
 func (h *EventHandler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
-	var event models.Event
+	var req eventRequest
 
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
@@ -89,14 +111,64 @@ func (h *EventHandler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
 		return
 	}
+	event := req.Event
+
+	existing, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "failed to find event to update"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to update event"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Status is driven by the draft; an update that leaves it out keeps the current one
+	if event.Status == "" {
+		event.Status = existing.Status
+	}
+	if event.MaxTeams == 0 {
+		event.MaxTeams = existing.MaxTeams
+	}
+
+	if err := validateEvent(&event); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	event.PasskeyHash = existing.PasskeyHash
+	event.CommissionerID = existing.CommissionerID // Read-only, as on create
+	if !setPasskey(w, &event, req.Passkey) {
+		return
+	}
+
+	// Once the draft has started only the name, passkey and registration settings may change
+	var conflict string
+	if draftStarted(existing.Status) {
+		if locked := lockedEventChanges(existing, &event); len(locked) > 0 {
+			conflict = "cannot change " + strings.Join(locked, ", ") + " once the draft has started"
+		}
+	}
+	audit, ok := h.guard.PermitUnlessRunning(w, r, id, models.AuditActionUpdateEvent, conflict)
+	if !ok {
+		return
+	}
 
 	// Set the id on the event
 	event.ID = id
-	if err := h.repo.Update(r.Context(), &event); err != nil {
+	err = h.guard.Apply(r.Context(), audit, func(tx pgx.Tx) (any, error) {
+		if err := h.repo.UpdateTx(r.Context(), tx, &event); err != nil {
+			return nil, err
+		}
+		return map[string]any{"before": existing, "after": event}, nil
+	})
+	if err != nil {
 		if err == pgx.ErrNoRows {
 			http.Error(w, `{"error": "failed to find event to update"}`, http.StatusNotFound)
 			return
@@ -120,7 +192,30 @@ func (h *EventHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.repo.Delete(r.Context(), id); err != nil {
+	existing, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "failed to find event to delete"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to delete event"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// A completed event can be deleted; a running draft cannot
+	var conflict string
+	if existing.Status == models.EventStatusInProgress {
+		conflict = "cannot delete an event while its draft is in progress"
+	}
+	audit, ok := h.guard.Permit(w, r, id, models.AuditActionDeleteEvent, conflict)
+	if !ok {
+		return
+	}
+
+	err = h.guard.Apply(r.Context(), audit, func(tx pgx.Tx) (any, error) {
+		return map[string]any{"event": existing}, h.repo.DeleteTx(r.Context(), tx, id)
+	})
+	if err != nil {
 		if err == pgx.ErrNoRows {
 			http.Error(w, `{"error": "failed to find event to delete"}`, http.StatusNotFound)
 			return
@@ -132,3 +227,171 @@ func (h *EventHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// CloneEvent handles POST /events/{id}/clone
+// Creates a new event from an existing one, copying only what the request asks for
+func (h *EventHandler) CloneEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	var req cloneEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, `{"error": "name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	source, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "event not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	event := models.NewEvent(req.Name)
+	if req.CopySettings {
+		event.CopySettings(source)
+	}
+	if req.CopyStipulations && source.Stipulations != nil {
+		event.Stipulations = source.Stipulations
+	}
+	if !setPasskey(w, &event, req.Passkey) {
+		return
+	}
+
+	if err := h.repo.Clone(r.Context(), id, &event, req.CopyPlayerPool, req.CopyTeams); err != nil {
+		if errors.Is(err, repository.ErrTooManyTeams) {
+			http.Error(w, `{"error": "source event has more teams than maxTeams"}`, http.StatusBadRequest)
+			return
+		}
+		http.Error(w, `{"error": "failed to clone event"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(event)
+}
+
+// GetAuditLog handles GET /events/{id}/audit
+// Lists the admin force-edits made to the event
+func (h *EventHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.guard.audit.GetByEvent(r.Context(), id)
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(entries)
+}
+
+// maxPasskeyLength caps the length of an event passkey
+const maxPasskeyLength = 100
+
+// eventRequest is the body of POST and PUT /events
+// The passkey is write-only: it is stored hashed and never returned
+type eventRequest struct {
+	models.Event
+	Passkey *string `json:"passkey"`
+}
+
+// cloneEventRequest is the body of POST /events/{id}/clone
+// Nothing is copied from the source event unless asked for; the passkey is never copied
+type cloneEventRequest struct {
+	Name             string  `json:"name"`
+	Passkey          *string `json:"passkey"`
+	CopySettings     bool    `json:"copySettings"`     // Draft settings, roster slots, ranking source, team capacity and league
+	CopyStipulations bool    `json:"copyStipulations"` // Stipulations
+	CopyPlayerPool   bool    `json:"copyPlayerPool"`   // The event's players
+	CopyTeams        bool    `json:"copyTeams"`        // Teams with their profiles, but not their PINs or rejoin links
+}
+
+// setPasskey hashes a new passkey onto the event; nil leaves it unchanged and "" removes it
+// Writes an error response and returns false if the passkey cannot be set
+func setPasskey(w http.ResponseWriter, event *models.Event, passkey *string) bool {
+	switch {
+	case passkey == nil:
+	case *passkey == "":
+		event.PasskeyHash = nil
+	case len(*passkey) > maxPasskeyLength:
+		http.Error(w, `{"error": "passkey must be at most 100 characters"}`, http.StatusBadRequest)
+		return false
+	default:
+		hash, err := auth.HashSecret(*passkey)
+		if err != nil {
+			http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+			return false
+		}
+		event.PasskeyHash = &hash
+	}
+	event.HasPasskey = event.PasskeyHash != nil
+	return true
+}
+
+// validateEvent checks an event's roster slots and registration settings
+func validateEvent(event *models.Event) error {
+	if err := event.RosterSlots.Validate(); err != nil {
+		return err
+	}
+	return event.ValidateRegistration()
+}
+
+// lockedEventChanges lists the draft settings an update would change
+// Registration settings are not locked, so a commissioner can still admit late teams
+func lockedEventChanges(existing, updated *models.Event) []string {
+	var changed []string
+	if updated.MaxPicksPerTeam != existing.MaxPicksPerTeam {
+		changed = append(changed, "maxPicksPerTeam")
+	}
+	if updated.MaxTeamsPerPlayer != existing.MaxTeamsPerPlayer {
+		changed = append(changed, "maxTeamsPerPlayer")
+	}
+	if !(len(updated.Stipulations) == 0 && len(existing.Stipulations) == 0) &&
+		!sameJSON(updated.Stipulations, existing.Stipulations) {
+		changed = append(changed, "stipulations")
+	}
+	if !(len(updated.RosterSlots) == 0 && len(existing.RosterSlots) == 0) &&
+		!sameJSON(updated.RosterSlots, existing.RosterSlots) {
+		changed = append(changed, "rosterSlots")
+	}
+	if !equalStringPtr(updated.RankingSource, existing.RankingSource) {
+		changed = append(changed, "rankingSource")
+	}
+	if updated.Status != existing.Status {
+		changed = append(changed, "status")
+	}
+	return changed
+}
+
+// sameJSON compares two values by their JSON encoding, as they are stored
+func sameJSON(a, b any) bool {
+	x, errA := json.Marshal(a)
+	y, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(x) == string(y)
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/auth"
+	"github.com/sblackwood23/fantasy-draft-app/internal/draft"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+// adminTokenHeader carries the admin token on force-edit requests
+const adminTokenHeader = "X-Admin-Token"
+
+// sessionTokenHeader carries a team's session token (from joining) on requests made as the team
+const sessionTokenHeader = "X-Session-Token"
+
+// draftStarted reports whether an event's draft has started, which locks its configuration
+func draftStarted(status string) bool {
+	return status == models.EventStatusInProgress || status == models.EventStatusCompleted
+}
+
+// Guard rejects mutations that an event's status does not allow, unless an admin forces them
+// A forced mutation is recorded in the audit log in the same transaction as the change.
+type Guard struct {
+	audit      *repository.AuditRepository
+	adminToken string // Empty disables force-edits
+	sessions   *auth.SessionSigner
+	rooms      *draft.DraftService // Refuses force-edits the running draft room would not see
+}
+
+func NewGuard(audit *repository.AuditRepository, adminToken string, sessions *auth.SessionSigner, rooms *draft.DraftService) *Guard {
+	return &Guard{
+		audit:      audit,
+		adminToken: adminToken,
+		sessions:   sessions,
+		rooms:      rooms,
+	}
+}
+
+// Permit decides whether a mutation may go ahead. conflict is why the event's status forbids it,
+// or "" if it is allowed. A forbidden mutation proceeds only as a force-edit: ?force=true with a
+// reason and the admin token. Otherwise Permit writes the error response and returns false.
+// The returned entry is non-nil for a force-edit and must be passed to Apply with the mutation.
+func (g *Guard) Permit(w http.ResponseWriter, r *http.Request, eventID int, action, conflict string) (*models.AuditEntry, bool) {
+	if conflict == "" {
+		return nil, true
+	}
+
+	q := r.URL.Query()
+	if q.Get("force") != "true" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": conflict})
+		return nil, false
+	}
+
+	if !g.IsAdmin(r) {
+		http.Error(w, `{"error": "a valid admin token is required to force-edit"}`, http.StatusForbidden)
+		return nil, false
+	}
+
+	reason := strings.TrimSpace(q.Get("reason"))
+	if reason == "" {
+		http.Error(w, `{"error": "reason is required to force-edit"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	return &models.AuditEntry{
+		EventID: eventID,
+		Action:  action,
+		Actor:   "admin",
+		Reason:  reason,
+	}, true
+}
+
+// PermitUnlessRunning is Permit for a change to the settings, pool or teams the draft room loads
+// when it is created or started. The room does not reload them, so such a change cannot be forced
+// while the event's draft is running in the room: the 409 says to wait until it completes.
+func (g *Guard) PermitUnlessRunning(w http.ResponseWriter, r *http.Request, eventID int, action, conflict string) (*models.AuditEntry, bool) {
+	if conflict != "" && g.rooms.DraftRunning(eventID) {
+		http.Error(w, `{"error": "cannot force-edit while the draft is running in the draft room"}`, http.StatusConflict)
+		return nil, false
+	}
+	return g.Permit(w, r, eventID, action, conflict)
+}
+
+// IsAdmin reports whether a request carries the admin token
+// Always false when no admin token is configured
+func (g *Guard) IsAdmin(r *http.Request) bool {
+	token := r.Header.Get(adminTokenHeader)
+	return g.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(g.adminToken)) == 1
+}
+
+// RequireAdmin writes a 403 response and returns false unless the request carries the admin token
+func (g *Guard) RequireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if !g.IsAdmin(r) {
+		http.Error(w, `{"error": "a valid admin token is required"}`, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// IsTeamOrAdmin reports whether a request is made as the team: with its session token, or with
+// the admin token or an admin session for the team's event
+func (g *Guard) IsTeamOrAdmin(r *http.Request, user *models.User) bool {
+	if g.IsAdmin(r) {
+		return true
+	}
+	session, err := g.sessions.Verify(r.Header.Get(sessionTokenHeader))
+	if err != nil || session.EventID != user.EventID {
+		return false
+	}
+	return session.Admin || session.UserID == user.ID
+}
+
+// RequireTeamOrAdmin writes a 403 response and returns false unless IsTeamOrAdmin
+func (g *Guard) RequireTeamOrAdmin(w http.ResponseWriter, r *http.Request, user *models.User) bool {
+	if !g.IsTeamOrAdmin(r, user) {
+		http.Error(w, `{"error": "only the team or an admin can do that"}`, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// Apply runs a mutation in a transaction, returning its error as is. For a force-edit (entry is
+// non-nil) the audit entry, with the details mutate returns, is written in the same transaction:
+// if it cannot be written the mutation is rolled back and an error is returned.
+func (g *Guard) Apply(ctx context.Context, entry *models.AuditEntry, mutate func(tx pgx.Tx) (details any, err error)) error {
+	var mutateErr error
+	err := g.audit.WithEntry(ctx, entry, func(tx pgx.Tx) error {
+		details, err := mutate(tx)
+		if err != nil {
+			mutateErr = err
+			return err
+		}
+		if entry != nil {
+			if entry.Details, err = json.Marshal(details); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if entry != nil && mutateErr == nil {
+			slog.ErrorContext(ctx, "Failed to record audit entry", "event_id", entry.EventID, "action", entry.Action, "err", err)
+		}
+		return err
+	}
+
+	if entry != nil {
+		slog.InfoContext(ctx, "Admin force-edit", "event_id", entry.EventID, "action", entry.Action, "reason", entry.Reason)
+	}
+	return nil
+}
@@ -0,0 +1,507 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+// maxLeagueNameLength matches the VARCHAR limits on league and member names
+const maxLeagueNameLength = 100
+
+type LeagueHandler struct {
+	repo        *repository.LeagueRepository
+	eventRepo   *repository.EventRepository
+	resultsRepo *repository.DraftResultRepository
+	guard       *Guard
+}
+
+func NewLeagueHandler(repo *repository.LeagueRepository, eventRepo *repository.EventRepository, resultsRepo *repository.DraftResultRepository, guard *Guard) *LeagueHandler {
+	return &LeagueHandler{repo: repo, eventRepo: eventRepo, resultsRepo: resultsRepo, guard: guard}
+}
+
+// leagueEventRequest is an event to create from a league's membership
+type leagueEventRequest struct {
+	eventRequest
+	CommissionerMemberID *int `json:"commissionerMemberID"` // Defaults to the league's first member
+}
+
+// leagueEventResponse is a league event with the teams created for its members
+type leagueEventResponse struct {
+	Event models.Event  `json:"event"`
+	Teams []models.User `json:"teams"`
+}
+
+// ListLeagues handles GET /leagues
+func (h *LeagueHandler) ListLeagues(w http.ResponseWriter, r *http.Request) {
+	leagues, err := h.repo.GetAll(r.Context())
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(leagues)
+}
+
+// GetLeague handles GET /leagues/{id}
+func (h *LeagueHandler) GetLeague(w http.ResponseWriter, r *http.Request) {
+	id, ok := leagueIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	league, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeLeagueLookupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(league)
+}
+
+// CreateLeague handles POST /leagues
+func (h *LeagueHandler) CreateLeague(w http.ResponseWriter, r *http.Request) {
+	var league models.League
+	if err := json.NewDecoder(r.Body).Decode(&league); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !validLeagueName(w, &league.Name) {
+		return
+	}
+
+	if err := h.repo.Create(r.Context(), &league); err != nil {
+		http.Error(w, `{"error": "failed to create league"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(league)
+}
+
+// UpdateLeague handles PUT /leagues/{id}
+func (h *LeagueHandler) UpdateLeague(w http.ResponseWriter, r *http.Request) {
+	id, ok := leagueIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var league models.League
+	if err := json.NewDecoder(r.Body).Decode(&league); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !validLeagueName(w, &league.Name) {
+		return
+	}
+
+	league.ID = id
+	if err := h.repo.Update(r.Context(), &league); err != nil {
+		writeLeagueLookupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(league)
+}
+
+// DeleteLeague handles DELETE /leagues/{id}
+// The league's events and teams are kept, unlinked from it
+func (h *LeagueHandler) DeleteLeague(w http.ResponseWriter, r *http.Request) {
+	id, ok := leagueIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		writeLeagueLookupError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListMembers handles GET /leagues/{id}/members
+func (h *LeagueHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	id, ok := leagueIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.GetByID(r.Context(), id); err != nil {
+		writeLeagueLookupError(w, err)
+		return
+	}
+
+	members, err := h.repo.GetMembers(r.Context(), id)
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(members)
+}
+
+// AddMember handles POST /leagues/{id}/members
+func (h *LeagueHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	id, ok := leagueIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var member models.LeagueMember
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !validLeagueName(w, &member.Name) {
+		return
+	}
+
+	if _, err := h.repo.GetByID(r.Context(), id); err != nil {
+		writeLeagueLookupError(w, err)
+		return
+	}
+
+	member.LeagueID = id
+	if err := h.repo.AddMember(r.Context(), &member); err != nil {
+		if errors.Is(err, repository.ErrMemberNameTaken) {
+			http.Error(w, `{"error": "league already has a member with this name"}`, http.StatusConflict)
+			return
+		}
+		http.Error(w, `{"error": "failed to add league member"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(member)
+}
+
+// RemoveMember handles DELETE /leagues/{id}/members/{memberID}
+// The member's teams are kept, unlinked from them
+func (h *LeagueHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id, memberID, ok := leagueMemberParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.RemoveMember(r.Context(), id, memberID); err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "league member not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to remove league member"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LinkMemberTeam handles PUT /leagues/{id}/members/{memberID}/teams/{userID}
+// Links a team in one of the league's events to the member, e.g. one that joined under another name
+// Admin only: a link attributes the team's picks to the member's history
+func (h *LeagueHandler) LinkMemberTeam(w http.ResponseWriter, r *http.Request) {
+	if !h.guard.RequireAdmin(w, r) {
+		return
+	}
+
+	id, memberID, ok := leagueMemberParams(w, r)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid user ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.LinkTeam(r.Context(), id, memberID, userID); err != nil {
+		switch {
+		case err == pgx.ErrNoRows:
+			http.Error(w, `{"error": "league member or team not found in this league"}`, http.StatusNotFound)
+		case errors.Is(err, repository.ErrMemberHasTeam):
+			http.Error(w, `{"error": "member already has a team in this event"}`, http.StatusConflict)
+		default:
+			http.Error(w, `{"error": "failed to link team"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnlinkMemberTeam handles DELETE /leagues/{id}/members/{memberID}/teams/{userID}
+// Admin only, like LinkMemberTeam
+func (h *LeagueHandler) UnlinkMemberTeam(w http.ResponseWriter, r *http.Request) {
+	if !h.guard.RequireAdmin(w, r) {
+		return
+	}
+
+	id, memberID, ok := leagueMemberParams(w, r)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid user ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.UnlinkTeam(r.Context(), id, memberID, userID); err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "team is not linked to this league member"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to unlink team"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListLeagueEvents handles GET /leagues/{id}/events
+func (h *LeagueHandler) ListLeagueEvents(w http.ResponseWriter, r *http.Request) {
+	id, ok := leagueIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.GetByID(r.Context(), id); err != nil {
+		writeLeagueLookupError(w, err)
+		return
+	}
+
+	events, err := h.eventRepo.GetByLeague(r.Context(), id)
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(events)
+}
+
+// CreateLeagueEvent handles POST /leagues/{id}/events
+// Creates the event with a team for every current member, named after them
+func (h *LeagueHandler) CreateLeagueEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := leagueIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req leagueEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+	event := req.Event
+
+	if !setPasskey(w, &event, req.Passkey) {
+		return
+	}
+
+	if event.MaxTeams == 0 {
+		event.MaxTeams = models.DefaultMaxTeams
+	}
+
+	if err := validateEvent(&event); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	teams, err := h.repo.CreateEvent(r.Context(), id, &event, req.CommissionerMemberID)
+	if err != nil {
+		switch {
+		case err == pgx.ErrNoRows:
+			http.Error(w, `{"error": "league not found"}`, http.StatusNotFound)
+		case errors.Is(err, repository.ErrTooManyMembers), errors.Is(err, repository.ErrMemberNotInEvent):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		default:
+			http.Error(w, `{"error": "failed to create event"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(leagueEventResponse{Event: event, Teams: teams})
+}
+
+// GetLeagueHistory handles GET /leagues/{id}/history
+// Returns every member with their rosters from the league's events, oldest event first
+func (h *LeagueHandler) GetLeagueHistory(w http.ResponseWriter, r *http.Request) {
+	id, ok := leagueIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.GetByID(r.Context(), id); err != nil {
+		writeLeagueLookupError(w, err)
+		return
+	}
+
+	members, err := h.repo.GetMembers(r.Context(), id)
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	history, err := h.memberHistories(r, id, members)
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(history)
+}
+
+// GetMemberHistory handles GET /leagues/{id}/members/{memberID}/history
+func (h *LeagueHandler) GetMemberHistory(w http.ResponseWriter, r *http.Request) {
+	id, memberID, ok := leagueMemberParams(w, r)
+	if !ok {
+		return
+	}
+
+	member, err := h.repo.GetMember(r.Context(), id, memberID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "league member not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	history, err := h.memberHistories(r, id, []models.LeagueMember{*member})
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(history[0])
+}
+
+// memberHistories gathers each member's teams in the league's events along with their picks
+func (h *LeagueHandler) memberHistories(r *http.Request, leagueID int, members []models.LeagueMember) ([]models.MemberHistory, error) {
+	teams, err := h.repo.GetMemberTeams(r.Context(), leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]models.MemberHistory, len(members))
+	index := make(map[int]int, len(members))
+	for i, member := range members {
+		history[i] = models.MemberHistory{Member: member, Teams: []models.MemberTeam{}}
+		index[member.ID] = i
+	}
+
+	var userIDs []int
+	for _, team := range teams {
+		if _, ok := index[team.MemberID]; ok {
+			userIDs = append(userIDs, team.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return history, nil
+	}
+
+	picks, err := h.resultsRepo.GetPicksByUsers(r.Context(), userIDs)
+	if err != nil {
+		return nil, err
+	}
+	picksByUser := make(map[int][]models.DraftPick)
+	for _, pick := range picks {
+		picksByUser[pick.UserID] = append(picksByUser[pick.UserID], pick)
+	}
+
+	for _, team := range teams {
+		i, ok := index[team.MemberID]
+		if !ok {
+			continue
+		}
+		team.Picks = picksByUser[team.UserID]
+		if team.Picks == nil {
+			team.Picks = []models.DraftPick{}
+		}
+		history[i].Teams = append(history[i].Teams, team)
+	}
+
+	return history, nil
+}
+
+// validLeagueName trims a league or member name and checks it is present and short enough
+// Writes an error response and returns false if it is not
+func validLeagueName(w http.ResponseWriter, name *string) bool {
+	*name = strings.TrimSpace(*name)
+	if *name == "" {
+		http.Error(w, `{"error": "name is required"}`, http.StatusBadRequest)
+		return false
+	}
+	if utf8.RuneCountInString(*name) > maxLeagueNameLength {
+		http.Error(w, `{"error": "name must be at most 100 characters"}`, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// leagueIDParam parses the {id} URL parameter, writing a 400 if it is invalid
+func leagueIDParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid league ID"}`, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// leagueMemberParams parses the {id} and {memberID} URL parameters, writing a 400 if either is invalid
+func leagueMemberParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	id, ok := leagueIDParam(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	memberID, err := strconv.Atoi(chi.URLParam(r, "memberID"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid member ID"}`, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return id, memberID, true
+}
+
+// writeLeagueLookupError maps a league lookup failure to 404 or 500
+func writeLeagueLookupError(w http.ResponseWriter, err error) {
+	if err == pgx.ErrNoRows {
+		http.Error(w, `{"error": "league not found"}`, http.StatusNotFound)
+		return
+	}
+	http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+}
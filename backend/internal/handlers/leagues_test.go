@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+	"github.com/sblackwood23/fantasy-draft-app/internal/testdb"
+)
+
+func TestLeagueHistoryEndpoints(t *testing.T) {
+	pool := testdb.Open(t)
+	ctx := context.Background()
+	repo := repository.NewLeagueRepository(pool)
+	resultRepo := repository.NewDraftResultRepository(pool)
+	h := NewLeagueHandler(repo, repository.NewEventRepository(pool), resultRepo, nil)
+
+	router := chi.NewRouter()
+	router.Get("/leagues/{id}/history", h.GetLeagueHistory)
+	router.Get("/leagues/{id}/members/{memberID}/history", h.GetMemberHistory)
+
+	newLeague := func(members ...string) (int, []int) {
+		league := &models.League{Name: t.Name()}
+		if err := repo.Create(ctx, league); err != nil {
+			t.Fatalf("create league: %v", err)
+		}
+		t.Cleanup(func() { repo.Delete(context.Background(), league.ID) })
+		memberIDs := make([]int, len(members))
+		for i, name := range members {
+			member := &models.LeagueMember{LeagueID: league.ID, Name: name}
+			if err := repo.AddMember(ctx, member); err != nil {
+				t.Fatalf("add member: %v", err)
+			}
+			memberIDs[i] = member.ID
+		}
+		return league.ID, memberIDs
+	}
+	leagueEvent := func(leagueID int) int {
+		eventID := testdb.Event(t, pool, 4)
+		if _, err := pool.Exec(ctx, `UPDATE events SET league_id = $2 WHERE id = $1`, eventID, leagueID); err != nil {
+			t.Fatalf("add event to league: %v", err)
+		}
+		return eventID
+	}
+	linkedTeam := func(leagueID, memberID, eventID int, name string) int {
+		userID := testdb.Team(t, pool, eventID, name)
+		if err := repo.LinkTeam(ctx, leagueID, memberID, userID); err != nil {
+			t.Fatalf("link team: %v", err)
+		}
+		return userID
+	}
+
+	leagueID, members := newLeague("Ann", "Bob", "Cat")
+	ann, bob := members[0], members[1]
+	_, otherMembers := newLeague("Dee")
+
+	// Ann drafts in both events, Bob only in the first; Bob's second team isn't linked to him
+	event1, event2 := leagueEvent(leagueID), leagueEvent(leagueID)
+	ann1 := linkedTeam(leagueID, ann, event1, "Ann")
+	linkedTeam(leagueID, bob, event1, "Bob")
+	linkedTeam(leagueID, ann, event2, "Ann again")
+	testdb.Team(t, pool, event2, "Bob")
+
+	// A team in an event that has since left the league is not part of its history
+	departed := leagueEvent(leagueID)
+	linkedTeam(leagueID, ann, departed, "Ann")
+	if _, err := pool.Exec(ctx, `UPDATE events SET league_id = NULL WHERE id = $1`, departed); err != nil {
+		t.Fatalf("remove event from league: %v", err)
+	}
+
+	players := testdb.Players(t, pool, event1, 2)
+	for i, playerID := range players {
+		if err := resultRepo.SavePick(ctx, event1, ann1, playerID, i+1, i+1, models.PickProvenanceManual); err != nil {
+			t.Fatalf("SavePick: %v", err)
+		}
+	}
+
+	// Event IDs and pick counts of a member's teams
+	type team struct{ eventID, picks int }
+	summarize := func(history models.MemberHistory) []team {
+		teams := []team{}
+		for _, mt := range history.Teams {
+			teams = append(teams, team{mt.EventID, len(mt.Picks)})
+		}
+		return teams
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		want   map[int][]team // Teams by member, for a 200
+	}{
+		{
+			name:   "league history",
+			path:   fmt.Sprintf("/leagues/%d/history", leagueID),
+			status: http.StatusOK,
+			want: map[int][]team{
+				ann:        {{event1, 2}, {event2, 0}},
+				bob:        {{event1, 0}},
+				members[2]: {},
+			},
+		},
+		{
+			name:   "member history",
+			path:   fmt.Sprintf("/leagues/%d/members/%d/history", leagueID, ann),
+			status: http.StatusOK,
+			want:   map[int][]team{ann: {{event1, 2}, {event2, 0}}},
+		},
+		{
+			name:   "member of another league",
+			path:   fmt.Sprintf("/leagues/%d/members/%d/history", leagueID, otherMembers[0]),
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "unknown league",
+			path:   "/leagues/-1/history",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "invalid member ID",
+			path:   fmt.Sprintf("/leagues/%d/members/abc/history", leagueID),
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var histories []models.MemberHistory
+			if len(tt.want) == 1 {
+				var history models.MemberHistory
+				decode(t, w.Body.Bytes(), &history)
+				histories = append(histories, history)
+			} else {
+				decode(t, w.Body.Bytes(), &histories)
+			}
+			if len(histories) != len(tt.want) {
+				t.Fatalf("expected %d members, got %d", len(tt.want), len(histories))
+			}
+			for _, history := range histories {
+				got, want := summarize(history), tt.want[history.Member.ID]
+				if fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Fatalf("member %s: teams %v, want %v", history.Member.Name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+type NotificationPrefsHandler struct {
+	repo     *repository.NotificationPrefsRepository
+	userRepo *repository.UserRepository
+	guard    *Guard
+}
+
+func NewNotificationPrefsHandler(repo *repository.NotificationPrefsRepository, userRepo *repository.UserRepository, guard *Guard) *NotificationPrefsHandler {
+	return &NotificationPrefsHandler{repo: repo, userRepo: userRepo, guard: guard}
+}
+
+// GetNotificationPrefs handles GET /users/{id}/notifications
+// A team that never set preferences gets the defaults, with every notification off
+func (h *NotificationPrefsHandler) GetNotificationPrefs(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authorizeTeam(w, r)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.repo.GetByUser(r.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "user not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdateNotificationPrefs handles PUT /users/{id}/notifications
+// Accepts: {"email": true, "webhook": false, "onDeckPicks": 2, "clockWarningSeconds": 30}
+// Changes apply from the next turn, including during a draft
+func (h *NotificationPrefsHandler) UpdateNotificationPrefs(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authorizeTeam(w, r)
+	if !ok {
+		return
+	}
+
+	var prefs models.NotificationPrefs
+	if err := json.NewDecoder(r.Body).Decode(&prefs); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+	prefs.UserID = id
+
+	if err := prefs.Validate(); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.Upsert(r.Context(), &prefs); err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "user not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to update notification preferences"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// authorizeTeam returns the team named by the {id} URL parameter if the request is made as that
+// team or by an admin. Writes the error response and returns false otherwise
+func (h *NotificationPrefsHandler) authorizeTeam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid user ID"}`, http.StatusBadRequest)
+		return 0, false
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "user not found"}`, http.StatusNotFound)
+			return 0, false
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return 0, false
+	}
+
+	if !h.guard.RequireTeamOrAdmin(w, r, user) {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/importer"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+// maxImportSize caps the size of an uploaded player list
+const maxImportSize = 5 << 20
+
+type PlayerImportHandler struct {
+	importer  *importer.Importer
+	eventRepo *repository.EventRepository
+}
+
+func NewPlayerImportHandler(importer *importer.Importer, eventRepo *repository.EventRepository) *PlayerImportHandler {
+	return &PlayerImportHandler{
+		importer:  importer,
+		eventRepo: eventRepo,
+	}
+}
+
+// ImportPlayers handles POST /players/import?format=csv|json&eventID=1
+// The request body is the raw CSV or JSON file. The format defaults from the Content-Type.
+func (h *PlayerImportHandler) ImportPlayers(w http.ResponseWriter, r *http.Request) {
+	format, ok := importFormat(r)
+	if !ok {
+		http.Error(w, `{"error": "format must be csv or json"}`, http.StatusBadRequest)
+		return
+	}
+
+	eventID := 0
+	if raw := r.URL.Query().Get("eventID"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+			return
+		}
+		if _, err := h.eventRepo.GetByID(r.Context(), id); err != nil {
+			if err == pgx.ErrNoRows {
+				http.Error(w, `{"error": "event not found"}`, http.StatusNotFound)
+				return
+			}
+			http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		eventID = id
+	}
+
+	rows, err := importer.Parse(http.MaxBytesReader(w, r.Body, maxImportSize), format)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	result, err := h.importer.ImportPlayers(r.Context(), rows, eventID)
+	if errors.Is(err, repository.ErrPoolLocked) {
+		http.Error(w, `{"error": "player pool cannot change once the draft has started"}`, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Player import failed", "err", err)
+		http.Error(w, `{"error": "failed to import players"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// importFormat reads the format query parameter of an import, defaulting from the Content-Type
+// Reports false if the format is not supported
+func importFormat(r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = importer.FormatJSON
+		if strings.Contains(r.Header.Get("Content-Type"), "csv") {
+			format = importer.FormatCSV
+		}
+	}
+	return format, format == importer.FormatCSV || format == importer.FormatJSON
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
@@ -43,9 +46,22 @@ func (h *PlayerHandler) GetPlayer(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(player)
 }
 
+// maxPlayerPageSize caps the limit query parameter on player listings
+const maxPlayerPageSize = 500
+
 // ListPlayers handles GET /players
+// Query: search, status, countryCode, position, attr.<key>, sort (id|name|countryCode|status), order (asc|desc), limit, offset
 func (h *PlayerHandler) ListPlayers(w http.ResponseWriter, r *http.Request) {
-	players, err := h.repo.GetAll(r.Context())
+	filter, err := parsePlayerFilter(r)
+	if err == nil && filter.Sort == repository.PlayerSortRank {
+		err = errors.New("sort=rank is only available for event players")
+	}
+	if err != nil {
+		writePlayerFilterError(w, err)
+		return
+	}
+
+	players, total, err := h.repo.Search(r.Context(), filter)
 	if err != nil {
 		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
 		return
@@ -53,7 +69,87 @@ func (h *PlayerHandler) ListPlayers(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(players)
+	json.NewEncoder(w).Encode(playerPage(players, total, filter))
+}
+
+// parsePlayerFilter reads the search, filter, sort and paging query parameters shared by player listings
+func parsePlayerFilter(r *http.Request) (repository.PlayerFilter, error) {
+	q := r.URL.Query()
+	filter := repository.PlayerFilter{
+		Search:      strings.TrimSpace(q.Get("search")),
+		Status:      q.Get("status"),
+		CountryCode: q.Get("countryCode"),
+		Position:    q.Get("position"),
+		Sort:        q.Get("sort"),
+	}
+
+	// attr.<key>=<value> filters on player attributes; values are JSON when they parse as JSON
+	// (attr.byeWeek=7 matches the number 7), otherwise plain strings (attr.team=KC)
+	for key, values := range q {
+		name, ok := strings.CutPrefix(key, "attr.")
+		if !ok || name == "" {
+			continue
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(map[string]interface{})
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(values[0]), &value); err != nil {
+			value = values[0]
+		}
+		filter.Attributes[name] = value
+	}
+
+	if filter.Status != "" && filter.Status != "professional" && filter.Status != "amateur" {
+		return filter, errors.New("status must be professional or amateur")
+	}
+
+	if filter.Sort != "" && !repository.ValidSort(filter.Sort) {
+		return filter, errors.New("sort must be id, name, countryCode, status or rank")
+	}
+
+	switch q.Get("order") {
+	case "", "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return filter, errors.New("order must be asc or desc")
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxPlayerPageSize {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxPlayerPageSize)
+		}
+		filter.Limit = limit
+	}
+
+	if raw := q.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return filter, errors.New("offset must be a non-negative number")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
+
+// writePlayerFilterError writes a 400 response for an invalid player filter
+func writePlayerFilterError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// playerPage builds the paged response body for a player listing
+func playerPage(players []models.Player, total int, filter repository.PlayerFilter) map[string]interface{} {
+	return map[string]interface{}{
+		"players": players,
+		"total":   total,
+		"limit":   filter.Limit,
+		"offset":  filter.Offset,
+	}
 }
 
 // CreatePlayer handles POST /players
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/importer"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+type RankingHandler struct {
+	repo     *repository.RankingRepository
+	importer *importer.Importer
+}
+
+func NewRankingHandler(repo *repository.RankingRepository, importer *importer.Importer) *RankingHandler {
+	return &RankingHandler{
+		repo:     repo,
+		importer: importer,
+	}
+}
+
+// ListSources handles GET /rankings
+func (h *RankingHandler) ListSources(w http.ResponseWriter, r *http.Request) {
+	sources, err := h.repo.ListSources(r.Context())
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sources)
+}
+
+// GetRankings handles GET /rankings/{source}
+func (h *RankingHandler) GetRankings(w http.ResponseWriter, r *http.Request) {
+	rankings, err := h.repo.GetBySource(r.Context(), chi.URLParam(r, "source"))
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+	if len(rankings) == 0 {
+		http.Error(w, `{"error": "ranking source not found"}`, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(rankings)
+}
+
+// ImportRankings handles PUT /rankings/{source}?format=csv|json
+// The request body is the raw CSV or JSON file and replaces the source's rankings
+func (h *RankingHandler) ImportRankings(w http.ResponseWriter, r *http.Request) {
+	source := chi.URLParam(r, "source")
+	if err := importer.ValidateRankingSource(source); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	format, ok := importFormat(r)
+	if !ok {
+		http.Error(w, `{"error": "format must be csv or json"}`, http.StatusBadRequest)
+		return
+	}
+
+	rows, err := importer.ParseRankings(http.MaxBytesReader(w, r.Body, maxImportSize), format)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	result, err := h.importer.ImportRankings(r.Context(), source, rows)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Ranking import failed", "source", source, "err", err)
+		http.Error(w, `{"error": "failed to import rankings"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// DeleteRankings handles DELETE /rankings/{source}
+func (h *RankingHandler) DeleteRankings(w http.ResponseWriter, r *http.Request) {
+	if err := h.repo.DeleteSource(r.Context(), chi.URLParam(r, "source")); err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "ranking source not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to delete rankings"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
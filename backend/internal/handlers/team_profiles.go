@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// Team profile limits
+const (
+	maxDisplayNameLength = 50
+	maxEmailLength       = 254
+	maxAvatarSize        = 256 << 10 // 256 KiB
+)
+
+// avatarTypes are the image types accepted for avatars, detected from the uploaded bytes
+// SVG is left out because it can carry scripts.
+var avatarTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// teamProfile is a team with its contact email, which other endpoints leave out
+type teamProfile struct {
+	*models.User
+	Email *string `json:"email"`
+}
+
+// newTeamProfile returns a team's profile, with the contact email only when the team or an admin asks
+func (h *UserHandler) newTeamProfile(r *http.Request, user *models.User) teamProfile {
+	profile := teamProfile{User: user}
+	if h.guard.IsTeamOrAdmin(r, user) {
+		profile.Email = user.Email
+	}
+	return profile
+}
+
+// GetTeamProfile handles GET /users/{id}/profile
+// Anyone can read a profile; the contact email is null unless the team or an admin asks
+func (h *UserHandler) GetTeamProfile(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.getProfileUser(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.newTeamProfile(r, user))
+}
+
+// UpdateTeamProfile handles PUT /users/{id}/profile
+// Accepts: {"displayName": "...", "color": "#1f77b4", "email": "..."} - a field left out or "" is cleared
+func (h *UserHandler) UpdateTeamProfile(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DisplayName string `json:"displayName"`
+		Color       string `json:"color"`
+		Email       string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+
+	displayName, color, email, err := validateProfile(req.DisplayName, req.Color, req.Email)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	user, ok := h.getProfileUser(w, r)
+	if !ok {
+		return
+	}
+	audit, ok := h.permitProfileChange(w, r, user)
+	if !ok {
+		return
+	}
+
+	before := teamProfile{User: user, Email: user.Email}
+	updated := *user
+	updated.DisplayName, updated.Color, updated.Email = displayName, color, email
+	after := teamProfile{User: &updated, Email: updated.Email}
+	err = h.guard.Apply(r.Context(), audit, func(tx pgx.Tx) (any, error) {
+		return map[string]any{"before": before, "after": after}, h.repo.UpdateProfileTx(r.Context(), tx, &updated)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "user not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to update team profile"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(after)
+}
+
+// GetTeamAvatar handles GET /users/{id}/avatar
+func (h *UserHandler) GetTeamAvatar(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid user ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	data, contentType, err := h.repo.GetAvatar(r.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "avatar not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Avatar URLs are versioned by upload time, so a response never goes stale
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// UploadTeamAvatar handles PUT /users/{id}/avatar
+// The request body is the image itself (PNG, JPEG, GIF or WebP, at most 256 KiB)
+func (h *UserHandler) UploadTeamAvatar(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAvatarSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, `{"error": "avatar must be at most 256 KiB"}`, http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, `{"error": "failed to read avatar"}`, http.StatusBadRequest)
+		return
+	}
+	if len(data) == 0 {
+		http.Error(w, `{"error": "avatar image is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	contentType := http.DetectContentType(data)
+	if !avatarTypes[contentType] {
+		http.Error(w, `{"error": "avatar must be a PNG, JPEG, GIF or WebP image"}`, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	h.setAvatar(w, r, contentType, data)
+}
+
+// DeleteTeamAvatar handles DELETE /users/{id}/avatar
+func (h *UserHandler) DeleteTeamAvatar(w http.ResponseWriter, r *http.Request) {
+	h.setAvatar(w, r, "", nil)
+}
+
+// setAvatar stores or removes a team's avatar and writes the updated profile
+func (h *UserHandler) setAvatar(w http.ResponseWriter, r *http.Request, contentType string, data []byte) {
+	user, ok := h.getProfileUser(w, r)
+	if !ok {
+		return
+	}
+	audit, ok := h.permitProfileChange(w, r, user)
+	if !ok {
+		return
+	}
+
+	var updatedAt *time.Time
+	err := h.guard.Apply(r.Context(), audit, func(tx pgx.Tx) (any, error) {
+		var err error
+		updatedAt, err = h.repo.SetAvatarTx(r.Context(), tx, user.ID, contentType, data)
+		return map[string]any{"avatar": contentType, "bytes": len(data)}, err
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "user not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to update avatar"}`, http.StatusInternalServerError)
+		return
+	}
+	user.AvatarUpdatedAt = updatedAt
+	user.SetComputedFields()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.newTeamProfile(r, user))
+}
+
+// getProfileUser loads the team named by the {id} URL parameter
+// Writes the error response and returns false if it cannot be loaded
+func (h *UserHandler) getProfileUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid user ID"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	user, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "user not found"}`, http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return nil, false
+	}
+	return user, true
+}
+
+// permitProfileChange checks the request is made as the team (or an admin) and that the team's
+// profile may change, allowing an admin force-edit once the draft has started (the draft board
+// is built from profiles when the draft starts)
+func (h *UserHandler) permitProfileChange(w http.ResponseWriter, r *http.Request, user *models.User) (*models.AuditEntry, bool) {
+	if !h.guard.RequireTeamOrAdmin(w, r, user) {
+		return nil, false
+	}
+
+	event, err := h.eventRepo.GetByID(r.Context(), user.EventID)
+	if err != nil && err != pgx.ErrNoRows {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return nil, false
+	}
+	var conflict string
+	if event != nil && draftStarted(event.Status) {
+		conflict = "cannot change a team profile once the draft has started"
+	}
+	return h.guard.PermitUnlessRunning(w, r, user.EventID, models.AuditActionTeamProfile, conflict)
+}
+
+// validateProfile trims and checks the editable profile fields, returning nil for empty ones
+func validateProfile(displayName, color, email string) (*string, *string, *string, error) {
+	displayName = strings.TrimSpace(displayName)
+	if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+		return nil, nil, nil, errors.New("displayName must be at most 50 characters")
+	}
+
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color != "" && !colorPattern.MatchString(color) {
+		return nil, nil, nil, errors.New("color must be a hex color like #1f77b4")
+	}
+
+	email = strings.TrimSpace(email)
+	if email != "" {
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email || len(email) > maxEmailLength {
+			return nil, nil, nil, errors.New("email must be a valid email address")
+		}
+	}
+
+	return optionalString(displayName), optionalString(color), optionalString(email), nil
+}
+
+// optionalString returns nil for "" and a pointer to s otherwise
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+type TemplateHandler struct {
+	repo *repository.TemplateRepository
+}
+
+func NewTemplateHandler(repo *repository.TemplateRepository) *TemplateHandler {
+	return &TemplateHandler{repo: repo}
+}
+
+// createTemplateRequest is the body of POST /templates
+type createTemplateRequest struct {
+	Name    string `json:"name"`
+	EventID int    `json:"eventID"` // Event whose setup is saved
+}
+
+// templateEventRequest is the body of POST /templates/{id}/events
+type templateEventRequest struct {
+	Name    string  `json:"name"`
+	Passkey *string `json:"passkey"`
+}
+
+// ListTemplates handles GET /templates
+func (h *TemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.repo.GetAll(r.Context())
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(templates)
+}
+
+// GetTemplate handles GET /templates/{id}
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid template ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "template not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(template)
+}
+
+// CreateTemplate handles POST /templates
+// Saves an existing event's settings, stipulations and player pool under a name
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req createTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, `{"error": "name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.repo.CreateFromEvent(r.Context(), req.EventID, req.Name)
+	if err != nil {
+		switch {
+		case err == pgx.ErrNoRows:
+			http.Error(w, `{"error": "event not found"}`, http.StatusNotFound)
+		case errors.Is(err, repository.ErrTemplateNameTaken):
+			http.Error(w, `{"error": "a template with this name already exists"}`, http.StatusConflict)
+		default:
+			http.Error(w, `{"error": "failed to create template"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(template)
+}
+
+// DeleteTemplate handles DELETE /templates/{id}
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid template ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "template not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to delete template"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateEventFromTemplate handles POST /templates/{id}/events
+func (h *TemplateHandler) CreateEventFromTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid template ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	var req templateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, `{"error": "name is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "template not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	event := template.NewEvent(req.Name)
+	if !setPasskey(w, &event, req.Passkey) {
+		return
+	}
+
+	if err := h.repo.CreateEvent(r.Context(), template, &event); err != nil {
+		http.Error(w, `{"error": "failed to create event"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(event)
+}
@@ -12,11 +12,17 @@ import (
 )
 
 type UserHandler struct {
-	repo *repository.UserRepository
+	repo      *repository.UserRepository
+	eventRepo *repository.EventRepository
+	guard     *Guard
 }
 
-func NewUserHandler(repo *repository.UserRepository) *UserHandler {
-	return &UserHandler{repo: repo}
+func NewUserHandler(repo *repository.UserRepository, eventRepo *repository.EventRepository, guard *Guard) *UserHandler {
+	return &UserHandler{
+		repo:      repo,
+		eventRepo: eventRepo,
+		guard:     guard,
+	}
 }
 
 // GetUser handles GET /users/{id}
@@ -57,7 +63,12 @@ func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 }
 
 // CreateUser handles POST /users
+// Admin only: teams join through POST /events/join, which enforces the team limit and registration window
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	if !h.guard.RequireAdmin(w, r) {
+		return
+	}
+
 	var user models.User
 
 	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
@@ -116,7 +127,35 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.repo.Delete(r.Context(), id); err != nil {
+	user, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "failed to find user to delete"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to delete user"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Deleting a team removes its picks, so teams are locked once the draft has started
+	event, err := h.eventRepo.GetByID(r.Context(), user.EventID)
+	if err != nil && err != pgx.ErrNoRows {
+		http.Error(w, `{"error": "failed to delete user"}`, http.StatusInternalServerError)
+		return
+	}
+	var conflict string
+	if event != nil && draftStarted(event.Status) {
+		conflict = "cannot delete a team once the draft has started"
+	}
+	audit, ok := h.guard.PermitUnlessRunning(w, r, user.EventID, models.AuditActionDeleteUser, conflict)
+	if !ok {
+		return
+	}
+
+	err = h.guard.Apply(r.Context(), audit, func(tx pgx.Tx) (any, error) {
+		return map[string]any{"user": user}, h.repo.DeleteTx(r.Context(), tx, id)
+	})
+	if err != nil {
 		if err == pgx.ErrNoRows {
 			http.Error(w, `{"error": "failed to find user to delete"}`, http.StatusNotFound)
 			return
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/auth"
+	"github.com/sblackwood23/fantasy-draft-app/internal/draft"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+// Webhook limits
+const (
+	maxWebhookURLLength  = 2048
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// pingMessageType is the type of the test delivery sent by POST .../ping
+const pingMessageType = "ping"
+
+// WebhookHandler manages an event's webhooks; every route requires the admin token,
+// since a webhook makes the server send requests and its deliveries report the responses
+type WebhookHandler struct {
+	repo      *repository.WebhookRepository
+	eventRepo *repository.EventRepository
+	guard     *Guard
+}
+
+func NewWebhookHandler(repo *repository.WebhookRepository, eventRepo *repository.EventRepository, guard *Guard) *WebhookHandler {
+	return &WebhookHandler{repo: repo, eventRepo: eventRepo, guard: guard}
+}
+
+// webhookRequest is the body of POST and PUT /events/{id}/webhooks
+type webhookRequest struct {
+	URL          string   `json:"url"`
+	MessageTypes []string `json:"messageTypes"`
+	Active       *bool    `json:"active"` // Defaults to true
+}
+
+// webhookCreatedResponse includes the signing secret, which is only returned once
+type webhookCreatedResponse struct {
+	models.Webhook
+	Secret string `json:"secret"`
+}
+
+// ListWebhooks handles GET /events/{id}/webhooks
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !h.guard.RequireAdmin(w, r) {
+		return
+	}
+
+	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	webhooks, err := h.repo.GetByEvent(r.Context(), eventID)
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(webhooks)
+}
+
+// CreateWebhook handles POST /events/{id}/webhooks
+// The response carries the secret deliveries are signed with; it is not shown again
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if !h.guard.RequireAdmin(w, r) {
+		return
+	}
+
+	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		return
+	}
+
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+
+	hook := models.Webhook{EventID: eventID}
+	if !applyWebhookRequest(w, &hook, &req) {
+		return
+	}
+
+	if _, err := h.eventRepo.GetByID(r.Context(), eventID); err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "event not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	secret, err := auth.NewToken()
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+	hook.Secret = secret
+
+	if err := h.repo.Create(r.Context(), &hook); err != nil {
+		http.Error(w, `{"error": "failed to create webhook"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(webhookCreatedResponse{Webhook: hook, Secret: hook.Secret})
+}
+
+// UpdateWebhook handles PUT /events/{id}/webhooks/{webhookID}
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	if !h.guard.RequireAdmin(w, r) {
+		return
+	}
+
+	eventID, webhookID, ok := webhookParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid JSON"}`, http.StatusBadRequest)
+		return
+	}
+
+	hook := models.Webhook{ID: webhookID, EventID: eventID}
+	if !applyWebhookRequest(w, &hook, &req) {
+		return
+	}
+
+	if err := h.repo.Update(r.Context(), &hook); err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "webhook not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to update webhook"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(hook)
+}
+
+// DeleteWebhook handles DELETE /events/{id}/webhooks/{webhookID}
+// Deliveries still queued for it are dropped
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if !h.guard.RequireAdmin(w, r) {
+		return
+	}
+
+	eventID, webhookID, ok := webhookParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), eventID, webhookID); err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "webhook not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "failed to delete webhook"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries handles GET /events/{id}/webhooks/{webhookID}/deliveries
+// Returns the most recent deliveries, newest first; ?limit= caps how many (default 50)
+func (h *WebhookHandler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if !h.guard.RequireAdmin(w, r) {
+		return
+	}
+
+	eventID, webhookID, ok := webhookParams(w, r)
+	if !ok {
+		return
+	}
+
+	limit := defaultDeliveryLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxDeliveryLimit {
+			http.Error(w, `{"error": "limit must be between 1 and 500"}`, http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	if _, err := h.repo.GetByID(r.Context(), eventID, webhookID); err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "webhook not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	deliveries, err := h.repo.GetDeliveries(r.Context(), webhookID, limit)
+	if err != nil {
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// PingWebhook handles POST /events/{id}/webhooks/{webhookID}/ping
+// Queues a test delivery so an endpoint can be checked before the draft
+func (h *WebhookHandler) PingWebhook(w http.ResponseWriter, r *http.Request) {
+	if !h.guard.RequireAdmin(w, r) {
+		return
+	}
+
+	eventID, webhookID, ok := webhookParams(w, r)
+	if !ok {
+		return
+	}
+
+	hook, err := h.repo.GetByID(r.Context(), eventID, webhookID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, `{"error": "webhook not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	data, _ := json.Marshal(map[string]any{"type": pingMessageType, "webhookID": hook.ID})
+	delivery, err := h.repo.Enqueue(r.Context(), hook, pingMessageType, data)
+	if err != nil {
+		http.Error(w, `{"error": "failed to queue ping"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// applyWebhookRequest validates a webhook request and copies it onto the webhook
+// Writes an error response and returns false if it is invalid
+func applyWebhookRequest(w http.ResponseWriter, hook *models.Webhook, req *webhookRequest) bool {
+	// http is allowed so endpoints can be tested against a local server
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		http.Error(w, `{"error": "url must be an absolute http or https URL"}`, http.StatusBadRequest)
+		return false
+	}
+	if len(req.URL) > maxWebhookURLLength {
+		http.Error(w, `{"error": "url must be at most 2048 characters"}`, http.StatusBadRequest)
+		return false
+	}
+
+	messageTypes := []string{}
+	for _, msgType := range req.MessageTypes {
+		if !slices.Contains(draft.PublishedMessageTypes, msgType) && !slices.Contains(draft.NotificationMessageTypes, msgType) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "unknown message type: " + msgType})
+			return false
+		}
+		if !slices.Contains(messageTypes, msgType) {
+			messageTypes = append(messageTypes, msgType)
+		}
+	}
+
+	hook.URL = req.URL
+	hook.MessageTypes = messageTypes
+	hook.Active = req.Active == nil || *req.Active
+	return true
+}
+
+// webhookParams parses the {id} and {webhookID} URL parameters, writing a 400 if either is invalid
+func webhookParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	eventID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid event ID"}`, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	webhookID, err := strconv.Atoi(chi.URLParam(r, "webhookID"))
+	if err != nil {
+		http.Error(w, `{"error": "invalid webhook ID"}`, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return eventID, webhookID, true
+}
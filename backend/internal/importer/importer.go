@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+)
+
+// Row outcomes reported in a Result
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionSkipped = "skipped"
+)
+
+// Result summarizes an import
+type Result struct {
+	Created  int         `json:"created"`
+	Updated  int         `json:"updated"`
+	Skipped  int         `json:"skipped"`
+	EventID  int         `json:"eventID,omitempty"`
+	Attached int         `json:"attached"` // Players newly added to the event's pool
+	Rows     []RowResult `json:"rows"`
+}
+
+// RowResult reports what happened to a single row
+type RowResult struct {
+	Line     int    `json:"line"`
+	Name     string `json:"name"`
+	Action   string `json:"action"`
+	PlayerID int    `json:"playerID,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Importer loads player lists and rankings into the database
+type Importer struct {
+	pool         *pgxpool.Pool
+	players      *repository.PlayerRepository
+	eventPlayers *repository.EventPlayerRepository
+	rankings     *repository.RankingRepository
+}
+
+func NewImporter(pool *pgxpool.Pool, players *repository.PlayerRepository, eventPlayers *repository.EventPlayerRepository, rankings *repository.RankingRepository) *Importer {
+	return &Importer{
+		pool:         pool,
+		players:      players,
+		eventPlayers: eventPlayers,
+		rankings:     rankings,
+	}
+}
+
+// ImportPlayers upserts every valid row and, if eventID is non-zero, attaches all of the
+// imported players to that event's pool. Everything happens in one transaction, so a
+// database error leaves nothing half-imported. Invalid and duplicate rows are skipped.
+func (im *Importer) ImportPlayers(ctx context.Context, rows []Row, eventID int) (*Result, error) {
+	result := &Result{EventID: eventID, Rows: make([]RowResult, 0, len(rows))}
+
+	tx, err := im.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	seen := make(map[string]bool)
+	var playerIDs []int
+	for _, row := range rows {
+		rr := RowResult{Line: row.Line, Name: row.FirstName + " " + row.LastName}
+
+		player, err := row.player()
+		if err != nil {
+			rr.Action = ActionSkipped
+			rr.Reason = err.Error()
+			result.add(rr)
+			continue
+		}
+
+		key := dedupeKey(player)
+		if seen[key] {
+			rr.Action = ActionSkipped
+			rr.Reason = "duplicate of an earlier row"
+			result.add(rr)
+			continue
+		}
+		seen[key] = true
+
+		outcome, err := im.players.Upsert(ctx, tx, player)
+		if err != nil {
+			return nil, err
+		}
+
+		rr.PlayerID = player.ID
+		switch outcome {
+		case repository.UpsertCreated:
+			rr.Action = ActionCreated
+		case repository.UpsertUpdated:
+			rr.Action = ActionUpdated
+		default:
+			rr.Action = ActionSkipped
+			rr.Reason = "unchanged"
+		}
+		result.add(rr)
+		playerIDs = append(playerIDs, player.ID)
+	}
+
+	if eventID != 0 && len(playerIDs) > 0 {
+		result.Attached, err = im.eventPlayers.AddPlayersToEventTx(ctx, tx, eventID, playerIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// add records a row result and updates the totals
+func (r *Result) add(rr RowResult) {
+	switch rr.Action {
+	case ActionCreated:
+		r.Created++
+	case ActionUpdated:
+		r.Updated++
+	default:
+		r.Skipped++
+	}
+	r.Rows = append(r.Rows, rr)
+}
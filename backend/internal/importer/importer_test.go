@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/repository"
+	"github.com/sblackwood23/fantasy-draft-app/internal/testdb"
+)
+
+func TestImportPlayersUpsertsAndDedupes(t *testing.T) {
+	pool := testdb.Open(t)
+	im := NewImporter(pool, repository.NewPlayerRepository(pool), repository.NewEventPlayerRepository(pool), nil)
+	ctx := context.Background()
+	eventID := testdb.Event(t, pool, 4)
+
+	// Names and external IDs unique to this run, since players are shared across events
+	run := fmt.Sprintf("%d", time.Now().UnixNano())
+	externalID := "ext-" + run
+	lastName := func(name string) string { return name + " " + run }
+
+	var playerIDs []int
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM players WHERE id = ANY($1)`, playerIDs)
+	})
+
+	imports := []struct {
+		name string
+		rows []Row
+		want []string // Action of each row
+		// Totals
+		created, updated, skipped, attached int
+	}{
+		{
+			name: "first import",
+			rows: []Row{
+				{Line: 1, FirstName: "Ann", LastName: lastName("A"), CountryCode: "USA", ExternalID: externalID},
+				{Line: 2, FirstName: "Bea", LastName: lastName("B"), CountryCode: "USA"},
+				{Line: 3, FirstName: "Ann", LastName: lastName("Renamed"), CountryCode: "USA", ExternalID: externalID},
+				{Line: 4, FirstName: "BEA", LastName: lastName("b"), CountryCode: "usa"},
+				{Line: 5, FirstName: "Cat", CountryCode: "USA"},
+			},
+			want:    []string{ActionCreated, ActionCreated, ActionSkipped, ActionSkipped, ActionSkipped},
+			created: 2, skipped: 3, attached: 2,
+		},
+		{
+			name: "second import",
+			rows: []Row{
+				{Line: 1, FirstName: "Ann", LastName: lastName("A"), CountryCode: "USA", ExternalID: externalID},
+				{Line: 2, FirstName: "Bea", LastName: lastName("B"), CountryCode: "USA", Positions: []string{"G"}},
+				{Line: 3, FirstName: "Cat", LastName: lastName("C"), CountryCode: "USA"},
+			},
+			want:    []string{ActionSkipped, ActionUpdated, ActionCreated},
+			created: 1, updated: 1, skipped: 1, attached: 1,
+		},
+	}
+
+	ids := make(map[string]int) // Player ID of each name, which must not change between imports
+	for _, tt := range imports {
+		result, err := im.ImportPlayers(ctx, tt.rows, eventID)
+		if err != nil {
+			t.Fatalf("%s: ImportPlayers: %v", tt.name, err)
+		}
+		for _, rr := range result.Rows {
+			if rr.PlayerID != 0 {
+				playerIDs = append(playerIDs, rr.PlayerID)
+			}
+		}
+
+		if result.Created != tt.created || result.Updated != tt.updated || result.Skipped != tt.skipped || result.Attached != tt.attached {
+			t.Fatalf("%s: totals %+v, want created %d, updated %d, skipped %d, attached %d",
+				tt.name, result, tt.created, tt.updated, tt.skipped, tt.attached)
+		}
+		for i, rr := range result.Rows {
+			if rr.Action != tt.want[i] {
+				t.Fatalf("%s: line %d action %q (%s), want %q", tt.name, rr.Line, rr.Action, rr.Reason, tt.want[i])
+			}
+			if rr.PlayerID == 0 {
+				continue
+			}
+			if id, ok := ids[rr.Name]; ok && id != rr.PlayerID {
+				t.Fatalf("%s: %s was imported as player %d, then %d", tt.name, rr.Name, id, rr.PlayerID)
+			}
+			ids[rr.Name] = rr.PlayerID
+		}
+	}
+
+	var poolSize int
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM event_players WHERE event_id = $1`, eventID).Scan(&poolSize); err != nil {
+		t.Fatalf("count pool: %v", err)
+	}
+	if poolSize != 3 {
+		t.Fatalf("expected 3 players in the event's pool, got %d", poolSize)
+	}
+}
@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// Supported import formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Row is one player read from an import file, before validation
+type Row struct {
+	Line        int               `json:"line"` // CSV line or JSON array index (1-based)
+	FirstName   string            `json:"firstName"`
+	LastName    string            `json:"lastName"`
+	Status      string            `json:"status"`
+	CountryCode string            `json:"countryCode"`
+	ExternalID  string            `json:"externalID"`
+	Positions   []string          `json:"positions"`
+	Attributes  models.Attributes `json:"attributes"`
+}
+
+// csvColumns maps accepted CSV header names to Row fields
+var csvColumns = map[string]string{
+	"first_name":   "firstName",
+	"firstname":    "firstName",
+	"last_name":    "lastName",
+	"lastname":     "lastName",
+	"status":       "status",
+	"country_code": "countryCode",
+	"countrycode":  "countryCode",
+	"country":      "countryCode",
+	"external_id":  "externalID",
+	"externalid":   "externalID",
+	"positions":    "positions",
+	"position":     "positions",
+	"attributes":   "attributes",
+}
+
+// Parse reads player rows from a CSV (with a header row) or a JSON array
+func Parse(r io.Reader, format string) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSON:
+		return parseJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+}
+
+func parseCSV(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := csvColumns[key]; ok {
+			columns[field] = i
+		}
+	}
+	for _, required := range []string{"firstName", "lastName", "countryCode"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.New("CSV header must include first_name, last_name and country_code")
+		}
+	}
+
+	get := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	var rows []Row
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		row := Row{
+			Line:        line,
+			FirstName:   get(record, "firstName"),
+			LastName:    get(record, "lastName"),
+			Status:      get(record, "status"),
+			CountryCode: get(record, "countryCode"),
+			ExternalID:  get(record, "externalID"),
+			Positions:   splitPositions(get(record, "positions")),
+		}
+		// Attributes are a JSON object in a single column
+		if raw := strings.TrimSpace(get(record, "attributes")); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &row.Attributes); err != nil {
+				return nil, fmt.Errorf("invalid CSV: line %d: attributes must be a JSON object", line)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseJSON(r io.Reader) ([]Row, error) {
+	var rows []Row
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, errors.New("invalid JSON: expected an array of players")
+	}
+	for i := range rows {
+		rows[i].Line = i + 1
+	}
+	return rows, nil
+}
+
+// player validates and normalizes a row into a player
+func (row Row) player() (*models.Player, error) {
+	player := &models.Player{
+		FirstName:   strings.TrimSpace(row.FirstName),
+		LastName:    strings.TrimSpace(row.LastName),
+		Status:      strings.ToLower(strings.TrimSpace(row.Status)),
+		CountryCode: strings.ToUpper(strings.TrimSpace(row.CountryCode)),
+	}
+	if externalID := strings.TrimSpace(row.ExternalID); externalID != "" {
+		player.ExternalID = &externalID
+	}
+	// Positions and attributes stay nil when the file omits them so updates keep existing values
+	for _, position := range row.Positions {
+		if position = strings.ToUpper(strings.TrimSpace(position)); position != "" {
+			player.Positions = append(player.Positions, position)
+		}
+	}
+	player.Attributes = row.Attributes
+
+	if player.Status == "" {
+		player.Status = "professional"
+	}
+
+	switch {
+	case player.FirstName == "" || player.LastName == "":
+		return nil, errors.New("first and last name are required")
+	case player.CountryCode == "":
+		return nil, errors.New("country code is required")
+	case player.Status != "professional" && player.Status != "amateur":
+		return nil, fmt.Errorf("status must be professional or amateur, got %q", player.Status)
+	case len(player.FirstName) > 100 || len(player.LastName) > 100 || len(player.CountryCode) > 100:
+		return nil, errors.New("name and country code must be at most 100 characters")
+	case player.ExternalID != nil && len(*player.ExternalID) > 100:
+		return nil, errors.New("external ID must be at most 100 characters")
+	}
+
+	return player, nil
+}
+
+// splitPositions splits a CSV positions cell such as "QB|RB" or "QB;RB"
+func splitPositions(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return strings.FieldsFunc(s, func(r rune) bool { return r == '|' || r == ';' })
+}
+
+// dedupeKey identifies a player within one import file
+func dedupeKey(p *models.Player) string {
+	if p.ExternalID != nil {
+		return "id:" + *p.ExternalID
+	}
+	return "name:" + strings.ToLower(p.FirstName) + "|" + strings.ToLower(p.LastName) + "|" + p.CountryCode
+}
@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		input   string
+		want    []Row
+		wantErr string
+	}{
+		{
+			name:   "csv with aliased headers",
+			format: FormatCSV,
+			input: "\ufeffFirstName, Last_Name, country, external_id, position, attributes\n" +
+				"Scottie, Scheffler, USA, pga-1, G|W, \"{\"\"tour\"\": \"\"PGA\"\"}\"\n" +
+				"Rory, McIlroy, NIR\n",
+			want: []Row{
+				{Line: 2, FirstName: "Scottie", LastName: "Scheffler", CountryCode: "USA", ExternalID: "pga-1",
+					Positions: []string{"G", "W"}, Attributes: models.Attributes{"tour": "PGA"}},
+				{Line: 3, FirstName: "Rory", LastName: "McIlroy", CountryCode: "NIR"},
+			},
+		},
+		{
+			name:    "csv missing a required column",
+			format:  FormatCSV,
+			input:   "first_name,last_name\nRory,McIlroy\n",
+			wantErr: "CSV header must include first_name, last_name and country_code",
+		},
+		{
+			name:    "csv with bad attributes",
+			format:  FormatCSV,
+			input:   "first_name,last_name,country_code,attributes\nRory,McIlroy,NIR,[1]\n",
+			wantErr: "invalid CSV: line 2: attributes must be a JSON object",
+		},
+		{
+			name:    "empty csv",
+			format:  FormatCSV,
+			input:   "",
+			wantErr: "CSV file is empty",
+		},
+		{
+			name:   "json array",
+			format: FormatJSON,
+			input:  `[{"firstName": "Jon", "lastName": "Rahm", "countryCode": "ESP", "positions": ["G"]}]`,
+			want:   []Row{{Line: 1, FirstName: "Jon", LastName: "Rahm", CountryCode: "ESP", Positions: []string{"G"}}},
+		},
+		{
+			name:    "json object",
+			format:  FormatJSON,
+			input:   `{"firstName": "Jon"}`,
+			wantErr: "invalid JSON: expected an array of players",
+		},
+		{
+			name:    "unknown format",
+			format:  "xml",
+			wantErr: "unsupported import format: xml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Parse(strings.NewReader(tt.input), tt.format)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Fatalf("rows = %+v, want %+v", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestRowPlayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		row     Row
+		want    *models.Player
+		wantErr string
+	}{
+		{
+			name: "normalized",
+			row:  Row{FirstName: " Jon ", LastName: "Rahm", Status: "Amateur", CountryCode: "esp", ExternalID: " x1 ", Positions: []string{" g", ""}},
+			want: &models.Player{FirstName: "Jon", LastName: "Rahm", Status: "amateur", CountryCode: "ESP", ExternalID: ptr("x1"), Positions: []string{"G"}},
+		},
+		{
+			name: "status defaults to professional",
+			row:  Row{FirstName: "Jon", LastName: "Rahm", CountryCode: "ESP"},
+			want: &models.Player{FirstName: "Jon", LastName: "Rahm", Status: "professional", CountryCode: "ESP"},
+		},
+		{
+			name:    "missing name",
+			row:     Row{FirstName: "Jon", CountryCode: "ESP"},
+			wantErr: "first and last name are required",
+		},
+		{
+			name:    "missing country",
+			row:     Row{FirstName: "Jon", LastName: "Rahm"},
+			wantErr: "country code is required",
+		},
+		{
+			name:    "unknown status",
+			row:     Row{FirstName: "Jon", LastName: "Rahm", CountryCode: "ESP", Status: "retired"},
+			wantErr: `status must be professional or amateur, got "retired"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, err := tt.row.player()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("player: %v", err)
+			}
+			if !reflect.DeepEqual(player, tt.want) {
+				t.Fatalf("player = %+v, want %+v", player, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupeKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b models.Player
+		same bool
+	}{
+		{"same name in another case", models.Player{FirstName: "Jon", LastName: "Rahm", CountryCode: "ESP"}, models.Player{FirstName: "JON", LastName: "rahm", CountryCode: "ESP"}, true},
+		{"same name in another country", models.Player{FirstName: "Jon", LastName: "Rahm", CountryCode: "ESP"}, models.Player{FirstName: "Jon", LastName: "Rahm", CountryCode: "USA"}, false},
+		{"same external ID", models.Player{FirstName: "Jon", LastName: "Rahm", ExternalID: ptr("x1")}, models.Player{FirstName: "J.", LastName: "Rahm", ExternalID: ptr("x1")}, true},
+		{"different external IDs", models.Player{FirstName: "Jon", LastName: "Rahm", ExternalID: ptr("x1")}, models.Player{FirstName: "Jon", LastName: "Rahm", ExternalID: ptr("x2")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := dedupeKey(&tt.a) == dedupeKey(&tt.b); same != tt.same {
+				t.Fatalf("same key = %v, want %v", same, tt.same)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
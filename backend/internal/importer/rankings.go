@@ -0,0 +1,248 @@
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// rankingSourcePattern restricts ranking source names so they are safe in URLs
+var rankingSourcePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,99}$`)
+
+// ValidateRankingSource checks that a source name can be imported into
+func ValidateRankingSource(source string) error {
+	if !rankingSourcePattern.MatchString(source) {
+		return errors.New("ranking source must be 1-100 letters, digits, '.', '_' or '-'")
+	}
+	if strings.EqualFold(source, models.RankingSourceADP) {
+		return errors.New("the adp ranking is computed from completed drafts and cannot be imported")
+	}
+	return nil
+}
+
+// RankingRow is one ranking read from an import file, before validation
+// Rank defaults to the row's position in the file when omitted
+type RankingRow struct {
+	Line       int      `json:"line"`
+	PlayerID   int      `json:"playerID"`
+	ExternalID string   `json:"externalID"`
+	Rank       int      `json:"rank"`
+	Tier       *int     `json:"tier"`
+	Value      *float64 `json:"value"`
+}
+
+// RankingResult summarizes a ranking import
+type RankingResult struct {
+	Source   string      `json:"source"`
+	Imported int         `json:"imported"`
+	Skipped  int         `json:"skipped"`
+	Rows     []RowResult `json:"rows"` // Skipped rows only
+}
+
+// rankingColumns maps accepted CSV header names to RankingRow fields
+var rankingColumns = map[string]string{
+	"player_id":   "playerID",
+	"playerid":    "playerID",
+	"external_id": "externalID",
+	"externalid":  "externalID",
+	"rank":        "rank",
+	"tier":        "tier",
+	"value":       "value",
+}
+
+// ParseRankings reads ranking rows from a CSV (with a header row) or a JSON array
+func ParseRankings(r io.Reader, format string) ([]RankingRow, error) {
+	switch format {
+	case FormatCSV:
+		return parseRankingsCSV(r)
+	case FormatJSON:
+		var rows []RankingRow
+		if err := json.NewDecoder(r).Decode(&rows); err != nil {
+			return nil, errors.New("invalid JSON: expected an array of rankings")
+		}
+		for i := range rows {
+			rows[i].Line = i + 1
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+}
+
+func parseRankingsCSV(r io.Reader) ([]RankingRow, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := rankingColumns[key]; ok {
+			columns[field] = i
+		}
+	}
+	_, hasID := columns["playerID"]
+	_, hasExternalID := columns["externalID"]
+	if !hasID && !hasExternalID {
+		return nil, errors.New("CSV header must include player_id or external_id")
+	}
+
+	get := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []RankingRow
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		row := RankingRow{Line: line, ExternalID: get(record, "externalID")}
+		if raw := get(record, "playerID"); raw != "" {
+			if row.PlayerID, err = strconv.Atoi(raw); err != nil {
+				return nil, fmt.Errorf("invalid CSV: line %d: player_id must be a number", line)
+			}
+		}
+		if raw := get(record, "rank"); raw != "" {
+			if row.Rank, err = strconv.Atoi(raw); err != nil {
+				return nil, fmt.Errorf("invalid CSV: line %d: rank must be a number", line)
+			}
+		}
+		if raw := get(record, "tier"); raw != "" {
+			tier, err := strconv.Atoi(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CSV: line %d: tier must be a number", line)
+			}
+			row.Tier = &tier
+		}
+		if raw := get(record, "value"); raw != "" {
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CSV: line %d: value must be a number", line)
+			}
+			row.Value = &value
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ImportRankings replaces a ranking source with the given rows in one transaction
+// Rows naming an unknown player, a player already ranked earlier in the file, or an
+// invalid rank or tier are skipped and reported.
+func (im *Importer) ImportRankings(ctx context.Context, source string, rows []RankingRow) (*RankingResult, error) {
+	result := &RankingResult{Source: source, Rows: []RowResult{}}
+
+	tx, err := im.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Resolve every referenced player up front
+	var ids []int
+	var externalIDs []string
+	for _, row := range rows {
+		if row.PlayerID != 0 {
+			ids = append(ids, row.PlayerID)
+		} else if row.ExternalID != "" {
+			externalIDs = append(externalIDs, row.ExternalID)
+		}
+	}
+	existing, err := im.players.ExistingIDs(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byExternalID, err := im.players.IDsByExternalID(ctx, tx, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := func(row RankingRow, reason string) {
+		result.Skipped++
+		result.Rows = append(result.Rows, RowResult{Line: row.Line, Action: ActionSkipped, PlayerID: row.PlayerID, Reason: reason})
+	}
+
+	seen := make(map[int]bool)
+	var rankings []models.Ranking
+	for i, row := range rows {
+		playerID := row.PlayerID
+		switch {
+		case playerID != 0 && !existing[playerID]:
+			skip(row, "player not found")
+			continue
+		case playerID == 0 && row.ExternalID == "":
+			skip(row, "playerID or externalID is required")
+			continue
+		case playerID == 0:
+			id, ok := byExternalID[row.ExternalID]
+			if !ok {
+				skip(row, fmt.Sprintf("no player with external ID %q", row.ExternalID))
+				continue
+			}
+			playerID = id
+		}
+
+		rank := row.Rank
+		if rank == 0 {
+			rank = i + 1
+		}
+		switch {
+		case rank < 0:
+			skip(row, "rank must be at least 1")
+			continue
+		case row.Tier != nil && *row.Tier < 1:
+			skip(row, "tier must be at least 1")
+			continue
+		case seen[playerID]:
+			skip(row, "player already ranked earlier in the file")
+			continue
+		}
+		seen[playerID] = true
+
+		rankings = append(rankings, models.Ranking{
+			Source:   source,
+			PlayerID: playerID,
+			Rank:     rank,
+			Tier:     row.Tier,
+			Value:    row.Value,
+		})
+	}
+
+	result.Imported, err = im.rankings.ReplaceTx(ctx, tx, source, rankings)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
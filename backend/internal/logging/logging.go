@@ -0,0 +1,77 @@
+// Package logging sets up structured JSON logging and carries correlation IDs through contexts
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Setup makes a JSON logger on stdout the slog default, at the level in LOG_LEVEL
+// (debug, info, warn or error; default info). The standard log package writes through it too.
+func Setup() {
+	level, err := ParseLevel(os.Getenv("LOG_LEVEL"))
+	slog.SetDefault(New(os.Stdout, level))
+	if err != nil {
+		slog.Warn("Invalid LOG_LEVEL; logging at info", "err", err)
+	}
+}
+
+// New creates a JSON logger that adds the correlation IDs carried by each record's context
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// ParseLevel parses a level name, returning info for an empty string
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if strings.TrimSpace(s) == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return slog.LevelInfo, err
+	}
+	return level, nil
+}
+
+// NewID returns a random ID for correlating log records, e.g. of one request or pick
+func NewID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type attrsKey struct{}
+
+// With returns a context whose log records carry the given key-value pairs,
+// in addition to any the context already carries
+func With(ctx context.Context, args ...any) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	attrs := slices.Concat(existing, slog.Group("", args...).Value.Group())
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+// contextHandler adds the attributes carried by a record's context before handling it
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// InstrumentHTTP returns middleware recording request durations by method, route pattern and status
+// Requests that switch protocols (WebSocket upgrades) are left out, since they last as long as the connection
+func InstrumentHTTP(r *Registry) func(http.Handler) http.Handler {
+	duration := NewHistogramVec("http_request_duration_seconds", "Time to serve HTTP requests, by route pattern.",
+		DefaultBuckets, "method", "route", "status")
+	r.MustRegister(duration)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
+			next.ServeHTTP(ww, req)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status == http.StatusSwitchingProtocols {
+				return
+			}
+
+			// The pattern, not the path, so IDs do not create a series each
+			route := "unmatched"
+			if rctx := chi.RouteContext(req.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			duration.Observe(time.Since(start).Seconds(), req.Method, route, strconv.Itoa(status))
+		})
+	}
+}
@@ -0,0 +1,295 @@
+// Package metrics exposes counters, gauges and histograms in the Prometheus text format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are histogram upper bounds in seconds, from 5ms to 10s
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metric is a named metric a Registry can expose
+type Metric interface {
+	Name() string
+	write(w *bufio.Writer)
+}
+
+// Registry holds the metrics served by its handler
+type Registry struct {
+	mu      sync.Mutex
+	metrics []Metric
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default is the registry served at /metrics
+var Default = NewRegistry()
+
+// MustRegister adds metrics to the registry, panicking if a name is already taken
+func (r *Registry) MustRegister(metrics ...Metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range metrics {
+		if slices.ContainsFunc(r.metrics, func(existing Metric) bool { return existing.Name() == m.Name() }) {
+			panic("metrics: duplicate metric " + m.Name())
+		}
+		r.metrics = append(r.metrics, m)
+	}
+}
+
+// Handler serves every registered metric in the Prometheus text exposition format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		metrics := slices.Clone(r.metrics)
+		r.mu.Unlock()
+		slices.SortFunc(metrics, func(a, b Metric) int { return strings.Compare(a.Name(), b.Name()) })
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		bw := bufio.NewWriter(w)
+		for _, m := range metrics {
+			m.write(bw)
+		}
+		bw.Flush()
+	})
+}
+
+// seriesKey joins a series' label values into a map key
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+// desc holds what every metric has in common
+type desc struct {
+	name       string
+	help       string
+	labelNames []string
+}
+
+func (d *desc) Name() string {
+	return d.name
+}
+
+func (d *desc) writeHeader(w *bufio.Writer, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.name, metricType)
+}
+
+func (d *desc) checkLabels(labelValues []string) {
+	if len(labelValues) != len(d.labelNames) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", d.name, len(d.labelNames), len(labelValues)))
+	}
+}
+
+// writeSample writes one sample line, with an extra label (e.g. le) if extraName is set
+func writeSample(w *bufio.Writer, name string, labelNames, labelValues []string, extraName, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(labelNames) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, labelName := range labelNames {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, labelName, labelValues[i])
+		}
+		if extraName != "" {
+			if len(labelNames) > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, extraName, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+func writeLabel(w *bufio.Writer, name, value string) {
+	w.WriteString(name)
+	w.WriteString(`="`)
+	w.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value))
+	w.WriteByte('"')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns map keys in order, so output is stable between scrapes
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// CounterVec is a counter partitioned by labels
+type CounterVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec creates a counter with the given label names
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		desc:   desc{name: name, help: help, labelNames: labelNames},
+		values: make(map[string]*counterSeries),
+	}
+}
+
+// Inc adds one to the series with the given label values
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds a non-negative amount to the series with the given label values
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	c.checkLabels(labelValues)
+	if delta < 0 {
+		panic("metrics: counter " + c.name + " cannot decrease")
+	}
+
+	key := seriesKey(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.values[key]
+	if !ok {
+		s = &counterSeries{labelValues: slices.Clone(labelValues)}
+		c.values[key] = s
+	}
+	s.value += delta
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeHeader(w, "counter")
+	for _, key := range sortedKeys(c.values) {
+		s := c.values[key]
+		writeSample(w, c.name, c.labelNames, s.labelValues, "", "", s.value)
+	}
+}
+
+// Sample is one gauge reading
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// GaugeFunc is a gauge read when scraped, for values that already live elsewhere (e.g. connection counts)
+type GaugeFunc struct {
+	desc
+	collect func() []Sample
+}
+
+// NewGaugeFunc creates a gauge whose samples come from collect at scrape time
+func NewGaugeFunc(name, help string, labelNames []string, collect func() []Sample) *GaugeFunc {
+	return &GaugeFunc{
+		desc:    desc{name: name, help: help, labelNames: labelNames},
+		collect: collect,
+	}
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	g.writeHeader(w, "gauge")
+	for _, s := range g.collect() {
+		g.checkLabels(s.LabelValues)
+		writeSample(w, g.name, g.labelNames, s.LabelValues, "", "", s.Value)
+	}
+}
+
+// HistogramVec is a histogram partitioned by labels
+type HistogramVec struct {
+	desc
+	buckets []float64 // Upper bounds, ascending; +Inf is implied
+	mu      sync.Mutex
+	values  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // Per bucket, not cumulative; the last is the +Inf bucket
+	sum         float64
+	count       uint64
+}
+
+// NewHistogramVec creates a histogram with the given bucket upper bounds and label names
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &HistogramVec{
+		desc:    desc{name: name, help: help, labelNames: labelNames},
+		buckets: buckets,
+		values:  make(map[string]*histogramSeries),
+	}
+}
+
+// Observe records a value in the series with the given label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.checkLabels(labelValues)
+
+	key := seriesKey(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.values[key]
+	if !ok {
+		s = &histogramSeries{
+			labelValues: slices.Clone(labelValues),
+			counts:      make([]uint64, len(h.buckets)+1),
+		}
+		h.values[key] = s
+	}
+
+	bucket, _ := slices.BinarySearch(h.buckets, value)
+	s.counts[bucket]++
+	s.sum += value
+	s.count++
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.writeHeader(w, "histogram")
+	for _, key := range sortedKeys(h.values) {
+		s := h.values[key]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, h.name+"_bucket", h.labelNames, s.labelValues, "le", formatFloat(upper), float64(cumulative))
+		}
+		writeSample(w, h.name+"_bucket", h.labelNames, s.labelValues, "le", "+Inf", float64(s.count))
+		writeSample(w, h.name+"_sum", h.labelNames, s.labelValues, "", "", s.sum)
+		writeSample(w, h.name+"_count", h.labelNames, s.labelValues, "", "", float64(s.count))
+	}
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -13,18 +15,103 @@ const (
 	EventStatusCompleted  = "completed"
 )
 
+// Pick provenance constants (how a pick came to be made)
+const (
+	PickProvenanceManual = "manual" // Made by the team on their turn
+	PickProvenanceAuto   = "auto"   // Auto-drafted when the turn timer expired
+	PickProvenanceAdmin  = "admin"  // Made on a team's behalf by the commissioner or admin
+	PickProvenanceKeeper = "keeper" // A player the team kept, assigned to its pick by the commissioner or admin
+)
+
+// ErrPlayerNotDraftable matches (via errors.Is) pick errors saying a player can never be drafted
+// with the pick that was tried, so retrying the same pick is pointless
+var ErrPlayerNotDraftable = errors.New("player cannot be drafted")
+
 // Event represents a draft event with configuration
 type Event struct {
-	ID                int          `json:"id"`
-	Name              string       `json:"name"`
-	MaxPicksPerTeam   int          `json:"maxPicksPerTeam"`
-	MaxTeamsPerPlayer int          `json:"maxTeamsPerPlayer"`
-	Stipulations      Stipulations `json:"stipulations"`
-	Status            string       `json:"status"`
-	Passkey           *string      `json:"passkey,omitempty"`
-	CreatedAt         time.Time    `json:"createdAt"`
-	StartedAt         *time.Time   `json:"startedAt,omitempty"`
-	CompletedAt       *time.Time   `json:"completedAt,omitempty"`
+	ID                   int          `json:"id"`
+	Name                 string       `json:"name"`
+	MaxPicksPerTeam      int          `json:"maxPicksPerTeam"`
+	MaxTeamsPerPlayer    int          `json:"maxTeamsPerPlayer"`
+	MaxSpectators        int          `json:"maxSpectators"`
+	MaxTeams             int          `json:"maxTeams"` // Registered teams allowed; 0 on create means DefaultMaxTeams
+	LeagueID             *int         `json:"leagueID"` // League the event belongs to, if any
+	CommissionerID       *int         `json:"commissionerUserID,omitempty"`
+	Stipulations         Stipulations `json:"stipulations"`
+	RosterSlots          RosterSlots  `json:"rosterSlots"`
+	RankingSource        *string      `json:"rankingSource"` // Ranking used to order the player pool and auto-draft
+	Status               string       `json:"status"`
+	PasskeyHash          *string      `json:"-"`                    // Salted hash of the join passkey; never serialized
+	HasPasskey           bool         `json:"hasPasskey"`           // Whether the event has a passkey set
+	RegistrationOpensAt  *time.Time   `json:"registrationOpensAt"`  // New teams may join from this time; nil for no limit
+	RegistrationClosesAt *time.Time   `json:"registrationClosesAt"` // New teams may join until this time; nil for no limit
+	LateRegistration     bool         `json:"lateRegistration"`     // Whether new teams may join after the draft starts
+	CreatedAt            time.Time    `json:"createdAt"`
+	StartedAt            *time.Time   `json:"startedAt,omitempty"`
+	CompletedAt          *time.Time   `json:"completedAt,omitempty"`
+}
+
+// DefaultMaxTeams is the team capacity of an event created without one
+const DefaultMaxTeams = 12
+
+// Settings of an event created without copying them, matching the events column defaults
+const (
+	DefaultMaxPicksPerTeam   = 6
+	DefaultMaxTeamsPerPlayer = 1
+	DefaultMaxSpectators     = 20
+)
+
+// NewEvent returns a not yet started event with default settings
+func NewEvent(name string) Event {
+	return Event{
+		Name:              name,
+		MaxPicksPerTeam:   DefaultMaxPicksPerTeam,
+		MaxTeamsPerPlayer: DefaultMaxTeamsPerPlayer,
+		MaxSpectators:     DefaultMaxSpectators,
+		MaxTeams:          DefaultMaxTeams,
+		Stipulations:      Stipulations{},
+		RosterSlots:       RosterSlots{},
+		Status:            EventStatusNotStarted,
+	}
+}
+
+// CopySettings copies another event's draft and registration settings onto the event
+// The registration window is tied to a date, so it is not copied.
+func (e *Event) CopySettings(src *Event) {
+	e.MaxPicksPerTeam = src.MaxPicksPerTeam
+	e.MaxTeamsPerPlayer = src.MaxTeamsPerPlayer
+	e.MaxSpectators = src.MaxSpectators
+	e.MaxTeams = src.MaxTeams
+	e.LeagueID = src.LeagueID
+	e.RosterSlots = src.RosterSlots
+	e.RankingSource = src.RankingSource
+	e.LateRegistration = src.LateRegistration
+}
+
+// ValidateRegistration checks the event's team capacity and registration window
+func (e *Event) ValidateRegistration() error {
+	if e.MaxTeams < 1 {
+		return errors.New("maxTeams must be at least 1")
+	}
+	if e.RegistrationOpensAt != nil && e.RegistrationClosesAt != nil && !e.RegistrationClosesAt.After(*e.RegistrationOpensAt) {
+		return errors.New("registrationClosesAt must be after registrationOpensAt")
+	}
+	return nil
+}
+
+// RegistrationClosedReason explains why new teams cannot join the event at a time, or returns "" if they can
+// Capacity is not considered; it is checked when a team registers.
+func (e *Event) RegistrationClosedReason(now time.Time) string {
+	switch {
+	case e.RegistrationOpensAt != nil && now.Before(*e.RegistrationOpensAt):
+		return "registration has not opened yet"
+	case e.RegistrationClosesAt != nil && !now.Before(*e.RegistrationClosesAt):
+		return "registration has closed"
+	case (e.Status == EventStatusInProgress || e.Status == EventStatusCompleted) && !e.LateRegistration:
+		return "registration closed when the draft started"
+	default:
+		return ""
+	}
 }
 
 // Stipulations represents JSONB draft rules stored in events table
@@ -48,23 +135,345 @@ func (s *Stipulations) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, s)
 }
 
+// RosterSlot is a roster position every team must fill, e.g. QB x1 or FLEX x1 (RB/WR/TE)
+type RosterSlot struct {
+	Name      string   `json:"name"`
+	Count     int      `json:"count"`
+	Positions []string `json:"positions,omitempty"` // Eligible player positions; defaults to Name, "*" accepts anyone
+}
+
+// AnyPosition is the roster slot position that accepts any player (e.g. bench)
+const AnyPosition = "*"
+
+// Eligible returns the player positions that can fill the slot
+func (s RosterSlot) Eligible() []string {
+	if len(s.Positions) == 0 {
+		return []string{s.Name}
+	}
+	return s.Positions
+}
+
+// RosterSlots represents the JSONB roster slot definitions stored in events table
+// An empty list means picks are not restricted by position
+type RosterSlots []RosterSlot
+
+// Validate checks that every slot has a name and a positive count
+func (r RosterSlots) Validate() error {
+	for _, slot := range r {
+		if slot.Name == "" {
+			return errors.New("roster slot name is required")
+		}
+		if slot.Count < 1 {
+			return fmt.Errorf("roster slot %s must have a count of at least 1", slot.Name)
+		}
+	}
+	return nil
+}
+
+// Size returns the total number of roster spots
+func (r RosterSlots) Size() int {
+	size := 0
+	for _, slot := range r {
+		size += slot.Count
+	}
+	return size
+}
+
+// Value implements driver.Valuer for database storage
+func (r RosterSlots) Value() (driver.Value, error) {
+	if r == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements sql.Scanner for database retrieval
+func (r *RosterSlots) Scan(value interface{}) error {
+	*r = RosterSlots{}
+	data, ok := jsonBytes(value)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(data, r)
+}
+
+// Attributes represents sport-specific JSONB player data (e.g. team, bye week, world ranking)
+type Attributes map[string]interface{}
+
+// Value implements driver.Valuer for database storage
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan implements sql.Scanner for database retrieval
+func (a *Attributes) Scan(value interface{}) error {
+	*a = make(Attributes)
+	data, ok := jsonBytes(value)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(data, a)
+}
+
+// jsonBytes returns the raw JSON from a scanned database value
+func jsonBytes(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case []byte:
+		return v, true
+	case string:
+		return []byte(v), true
+	default:
+		return nil, false
+	}
+}
+
 // Player represents a player in the draft pool
 type Player struct {
-	ID          int    `json:"id"`
-	FirstName   string `json:"firstName"`
-	LastName    string `json:"lastName"`
-	Status      string `json:"status"`
-	CountryCode string `json:"countryCode"`
+	ID          int        `json:"id"`
+	FirstName   string     `json:"firstName"`
+	LastName    string     `json:"lastName"`
+	Status      string     `json:"status"`
+	CountryCode string     `json:"countryCode"`
+	ExternalID  *string    `json:"externalID,omitempty"`
+	Positions   []string   `json:"positions"`
+	Attributes  Attributes `json:"attributes"`
+	Rank        *int       `json:"rank,omitempty"` // From the event's ranking source, on event player listings
+	Tier        *int       `json:"tier,omitempty"`
+}
+
+// RankingSourceADP is the ranking recomputed from completed drafts; it cannot be imported
+const RankingSourceADP = "adp"
+
+// Ranking is a player's place in a ranking source
+type Ranking struct {
+	Source   string   `json:"source"`
+	PlayerID int      `json:"playerID"`
+	Rank     int      `json:"rank"`
+	Tier     *int     `json:"tier,omitempty"`
+	Value    *float64 `json:"value,omitempty"` // Source-specific score, e.g. the average pick number for ADP
+}
+
+// RankingSource summarizes a set of rankings
+type RankingSource struct {
+	Name      string    `json:"name"`
+	Players   int       `json:"players"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Audit actions recorded for admin force-edits
+const (
+	AuditActionUpdateEvent  = "update_event"
+	AuditActionDeleteEvent  = "delete_event"
+	AuditActionChangePool   = "change_player_pool"
+	AuditActionDeleteUser   = "delete_user"
+	AuditActionRecreateRoom = "recreate_draft_room"
+	AuditActionTeamProfile  = "update_team_profile"
+)
+
+// AuditEntry records an admin force-edit of an event whose draft has started
+type AuditEntry struct {
+	ID        int             `json:"id"`
+	EventID   int             `json:"eventID"`
+	Action    string          `json:"action"`
+	Actor     string          `json:"actor"`
+	Reason    string          `json:"reason"`
+	Details   json.RawMessage `json:"details"`
+	CreatedAt time.Time       `json:"createdAt"`
 }
 
 // User represents a team/participant in the draft
 type User struct {
+	ID              int        `json:"id"`
+	EventID         int        `json:"eventID"`
+	Username        string     `json:"username"`
+	LeagueMemberID  *int       `json:"leagueMemberID"` // League member who owns the team, in a league event
+	DisplayName     *string    `json:"displayName"`    // Shown on the draft board instead of the username
+	Color           *string    `json:"color"`          // Board color as #rrggbb
+	Email           *string    `json:"-"`              // Owner contact email; only returned by the team profile endpoints
+	AvatarUpdatedAt *time.Time `json:"-"`              // When the avatar was last uploaded; nil without one
+	AvatarURL       string     `json:"avatarURL,omitempty"`
+	PinHash         *string    `json:"-"`             // Salted hash of the team's PIN; never serialized
+	RejoinTokenHash *string    `json:"-"`             // SHA-256 of the team's unused rejoin link token
+	HasPin          bool       `json:"hasPin"`        // Whether reclaiming the team accepts a PIN
+	HasRejoinLink   bool       `json:"hasRejoinLink"` // Whether the team has an unused rejoin link
+	Unclaimed       bool       `json:"unclaimed"`     // Copied from another event; no one can join as it until the commissioner resets it
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// SetComputedFields sets the fields derived from stored columns: HasPin, HasRejoinLink and AvatarURL
+// The avatar URL carries the upload time so clients refetch it after a change.
+func (u *User) SetComputedFields() {
+	u.HasPin = u.PinHash != nil
+	u.HasRejoinLink = u.RejoinTokenHash != nil
+	u.AvatarURL = ""
+	if u.AvatarUpdatedAt != nil {
+		u.AvatarURL = fmt.Sprintf("/users/%d/avatar?v=%d", u.ID, u.AvatarUpdatedAt.Unix())
+	}
+}
+
+// Name returns the team's display name, or its username if it has none
+func (u *User) Name() string {
+	if u.DisplayName != nil {
+		return *u.DisplayName
+	}
+	return u.Username
+}
+
+// Protected reports whether reclaiming the team from a new device needs its PIN or rejoin link
+func (u *User) Protected() bool {
+	return u.PinHash != nil || u.RejoinTokenHash != nil
+}
+
+// League is a group of people who draft together across events
+type League struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// LeagueMember is a person in a league, linked to their team (user) in each league event
+type LeagueMember struct {
 	ID        int       `json:"id"`
-	EventID   int       `json:"eventID"`
-	Username  string    `json:"username"`
+	LeagueID  int       `json:"leagueID"`
+	Name      string    `json:"name"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// MemberTeam is a league member's team in one of the league's events
+type MemberTeam struct {
+	MemberID    int         `json:"-"`
+	EventID     int         `json:"eventID"`
+	EventName   string      `json:"eventName"`
+	Status      string      `json:"status"`
+	StartedAt   *time.Time  `json:"startedAt,omitempty"`
+	CompletedAt *time.Time  `json:"completedAt,omitempty"`
+	UserID      int         `json:"userID"`
+	TeamName    string      `json:"teamName"`
+	Picks       []DraftPick `json:"picks"`
+}
+
+// MemberHistory is a league member's teams across the league's events, oldest event first
+type MemberHistory struct {
+	Member LeagueMember `json:"member"`
+	Teams  []MemberTeam `json:"teams"`
+}
+
+// EventTemplate is a saved event setup (settings, stipulations and player pool) to create events from
+type EventTemplate struct {
+	ID                int          `json:"id"`
+	Name              string       `json:"name"`
+	MaxPicksPerTeam   int          `json:"maxPicksPerTeam"`
+	MaxTeamsPerPlayer int          `json:"maxTeamsPerPlayer"`
+	MaxSpectators     int          `json:"maxSpectators"`
+	MaxTeams          int          `json:"maxTeams"`
+	Stipulations      Stipulations `json:"stipulations"`
+	RosterSlots       RosterSlots  `json:"rosterSlots"`
+	RankingSource     *string      `json:"rankingSource"`
+	LateRegistration  bool         `json:"lateRegistration"`
+	PlayerIDs         []int        `json:"playerIDs"` // The player pool
+	CreatedAt         time.Time    `json:"createdAt"`
+}
+
+// NewEvent returns a not yet started event set up from the template, without its player pool
+func (t *EventTemplate) NewEvent(name string) Event {
+	event := NewEvent(name)
+	event.MaxPicksPerTeam = t.MaxPicksPerTeam
+	event.MaxTeamsPerPlayer = t.MaxTeamsPerPlayer
+	event.MaxSpectators = t.MaxSpectators
+	event.MaxTeams = t.MaxTeams
+	event.Stipulations = t.Stipulations
+	event.RosterSlots = t.RosterSlots
+	event.RankingSource = t.RankingSource
+	event.LateRegistration = t.LateRegistration
+	return event
+}
+
+// Turn notification channels a team can opt in to
+const (
+	NotifyChannelEmail   = "email"   // Sent to the team's contact email
+	NotifyChannelWebhook = "webhook" // Posted to the event's webhooks
+)
+
+// MaxOnDeckPicks is the furthest ahead a team can ask to be told its turn is coming
+const MaxOnDeckPicks = 10
+
+// maxClockWarningSeconds caps how early a team can ask to be warned its turn is running out
+const maxClockWarningSeconds = 3600
+
+// NotificationPrefs are a team's turn notification settings; the zero value sends nothing
+type NotificationPrefs struct {
+	UserID              int       `json:"userID"`
+	Email               bool      `json:"email"`               // Notify by email (needs the team profile's contact email)
+	Webhook             bool      `json:"webhook"`             // Notify through the event's webhooks
+	OnDeckPicks         int       `json:"onDeckPicks"`         // Notify when the team's turn is this many picks away or closer; 0 for never
+	ClockWarningSeconds int       `json:"clockWarningSeconds"` // Notify when the team's turn has this many seconds left; 0 for never
+	UpdatedAt           time.Time `json:"updatedAt"`
+	TeamName            string    `json:"-"` // Filled in when loaded for a draft, for the notification text
+	TeamEmail           *string   `json:"-"`
+	EventName           string    `json:"-"`
+}
+
+// Validate checks the preferences are within range
+func (p *NotificationPrefs) Validate() error {
+	if p.OnDeckPicks < 0 || p.OnDeckPicks > MaxOnDeckPicks {
+		return fmt.Errorf("onDeckPicks must be between 0 and %d", MaxOnDeckPicks)
+	}
+	if p.ClockWarningSeconds < 0 || p.ClockWarningSeconds > maxClockWarningSeconds {
+		return fmt.Errorf("clockWarningSeconds must be between 0 and %d", maxClockWarningSeconds)
+	}
+	return nil
+}
+
+// Enabled reports whether the team opted in to a notification channel
+func (p *NotificationPrefs) Enabled(channel string) bool {
+	switch channel {
+	case NotifyChannelEmail:
+		return p.Email
+	case NotifyChannelWebhook:
+		return p.Webhook
+	default:
+		return false
+	}
+}
+
+// Webhook delivery status constants
+const (
+	WebhookDeliveryPending   = "pending"   // Waiting for its first or next attempt
+	WebhookDeliveryDelivered = "delivered" // The endpoint answered with a 2xx status
+	WebhookDeliveryFailed    = "failed"    // Every attempt failed; it will not be retried
+)
+
+// Webhook is a subscription that posts an event's draft messages to a URL
+type Webhook struct {
+	ID           int       `json:"id"`
+	EventID      int       `json:"eventID"`
+	URL          string    `json:"url"`
+	Secret       string    `json:"-"`            // HMAC key for signing deliveries; only returned when created
+	MessageTypes []string  `json:"messageTypes"` // Draft message types delivered; empty for all
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// WebhookDelivery is one message queued in the webhook outbox
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int             `json:"webhookID"`
+	MessageType    string          `json:"messageType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastStatusCode *int            `json:"lastStatusCode"`
+	LastError      *string         `json:"lastError"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+	URL            string          `json:"-"` // Copied from the webhook when the delivery is claimed
+	Secret         string          `json:"-"`
+}
+
 // DraftResult represents a pick made during a draft
 type DraftResult struct {
 	ID         int       `json:"id"`
@@ -73,5 +482,31 @@ type DraftResult struct {
 	PlayerID   int       `json:"playerID"`
 	PickNumber int       `json:"pickNumber"`
 	Round      int       `json:"round"`
+	Provenance string    `json:"provenance"`
 	CreatedAt  time.Time `json:"createdAt"`
 }
+
+// DraftPick is a draft result joined with the team and player it belongs to
+type DraftPick struct {
+	DraftResult
+	Username string `json:"username"`
+	Player   Player `json:"player"`
+}
+
+// TeamRoster is a team's picks in an event
+type TeamRoster struct {
+	UserID         int         `json:"userID"`
+	Username       string      `json:"username"`
+	Picks          []DraftPick `json:"picks"`
+	RemainingPicks int         `json:"remainingPicks"`
+}
+
+// ChatMessage represents a chat message posted in a draft room
+type ChatMessage struct {
+	ID        int        `json:"id"`
+	EventID   int        `json:"eventID"`
+	UserID    int        `json:"userID"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"createdAt"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
@@ -0,0 +1,129 @@
+// Package notify sends draft turn notifications outside the draft room
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/draft"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+const defaultSMTPPort = "587"
+
+// SMTPConfig holds the mail server settings for email notifications
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string // Leave empty for servers that do not require auth
+	Password string
+	From     string
+}
+
+// SMTPConfigFromEnv reads SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM
+// Returns false if SMTP_HOST is not set, leaving email notifications disabled
+func SMTPConfigFromEnv() (SMTPConfig, bool) {
+	config := SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if config.Host == "" {
+		return config, false
+	}
+	if config.Port == "" {
+		config.Port = defaultSMTPPort
+	}
+	if config.From == "" {
+		config.From = config.Username
+	}
+	return config, true
+}
+
+// EmailNotifier emails a team's contact address over SMTP
+type EmailNotifier struct {
+	config SMTPConfig
+}
+
+// NewEmailNotifier creates an email notifier for a mail server
+func NewEmailNotifier(config SMTPConfig) *EmailNotifier {
+	return &EmailNotifier{config: config}
+}
+
+// Channel returns the preference channel that opts a team in to email
+func (e *EmailNotifier) Channel() string {
+	return models.NotifyChannelEmail
+}
+
+// Notify emails the team, doing nothing if it has no contact email
+func (e *EmailNotifier) Notify(ctx context.Context, n draft.TurnNotification) error {
+	if n.Email == "" {
+		return nil
+	}
+
+	subject, body := emailContent(n)
+	msg := strings.Join([]string{
+		"From: " + e.config.From,
+		"To: " + headerValue(n.Email),
+		"Subject: " + mime.QEncoding.Encode("utf-8", headerValue(subject)),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	// net/smtp takes no context, so the send runs until ctx expires and is then abandoned
+	done := make(chan error, 1)
+	go func() {
+		done <- e.send(n.Email, []byte(msg))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// send delivers a message, using STARTTLS when the server offers it
+func (e *EmailNotifier) send(to string, msg []byte) error {
+	var auth smtp.Auth
+	if e.config.Username != "" {
+		auth = smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)
+	}
+	return smtp.SendMail(net.JoinHostPort(e.config.Host, e.config.Port), auth, e.config.From, []string{to}, msg)
+}
+
+// emailContent returns the subject and plain text body of a notification email
+func emailContent(n draft.TurnNotification) (string, string) {
+	switch n.Type {
+	case draft.MsgTypeOnDeck:
+		picks := "1 pick"
+		if n.PicksAway != 1 {
+			picks = fmt.Sprintf("%d picks", n.PicksAway)
+		}
+		subject := fmt.Sprintf("%s: you're up in %s", n.EventName, picks)
+		body := fmt.Sprintf("%s, you're up in %s in %s (pick %d, round %d).\r\n", n.TeamName, picks, n.EventName, n.PickNumber, n.Round)
+		return subject, body
+	case draft.MsgTypeOnTheClock:
+		subject := fmt.Sprintf("%s: %d seconds left to pick", n.EventName, n.RemainingSeconds)
+		body := fmt.Sprintf("%s, you're on the clock in %s with %d seconds left (pick %d, round %d).\r\n"+
+			"If time runs out, a player is picked for you.\r\n", n.TeamName, n.EventName, n.RemainingSeconds, n.PickNumber, n.Round)
+		return subject, body
+	}
+	return n.EventName, ""
+}
+
+// headerValue strips line breaks so names cannot inject extra mail headers
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
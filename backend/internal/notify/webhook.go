@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/draft"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// webhookNotification is the data of an on_deck or on_the_clock webhook delivery
+type webhookNotification struct {
+	Type             string `json:"type"`
+	UserID           int    `json:"userID"`
+	TeamName         string `json:"teamName"`
+	PicksAway        int    `json:"picksAway"`
+	PickNumber       int    `json:"pickNumber"`
+	Round            int    `json:"round"`
+	TurnDeadline     *int64 `json:"turnDeadline,omitempty"`     // Only for on_the_clock
+	RemainingSeconds *int   `json:"remainingSeconds,omitempty"` // Only for on_the_clock
+}
+
+// WebhookNotifier queues notifications for the event's webhooks subscribed to on_deck or on_the_clock
+type WebhookNotifier struct {
+	publisher draft.Publisher
+}
+
+// NewWebhookNotifier creates a webhook notifier that queues through the webhook outbox
+func NewWebhookNotifier(publisher draft.Publisher) *WebhookNotifier {
+	return &WebhookNotifier{publisher: publisher}
+}
+
+// Channel returns the preference channel that opts a team in to webhooks
+func (wn *WebhookNotifier) Channel() string {
+	return models.NotifyChannelWebhook
+}
+
+// Notify queues the notification; the webhook dispatcher delivers it
+func (wn *WebhookNotifier) Notify(ctx context.Context, n draft.TurnNotification) error {
+	data := webhookNotification{
+		Type:       n.Type,
+		UserID:     n.UserID,
+		TeamName:   n.TeamName,
+		PicksAway:  n.PicksAway,
+		PickNumber: n.PickNumber,
+		Round:      n.Round,
+	}
+	if n.Type == draft.MsgTypeOnTheClock {
+		deadline := n.TurnDeadline.Unix()
+		data.TurnDeadline = &deadline
+		data.RemainingSeconds = &n.RemainingSeconds
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return wn.publisher.Publish(ctx, n.EventID, n.Type, payload)
+}
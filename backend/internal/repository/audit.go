@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+type AuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{pool: pool}
+}
+
+// WithEntry runs mutate in a transaction and, when entry is non-nil, inserts entry in the same
+// transaction once mutate succeeds, so a change and its audit entry are saved together or not at all
+func (r *AuditRepository) WithEntry(ctx context.Context, entry *models.AuditEntry, mutate func(tx pgx.Tx) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := mutate(tx); err != nil {
+		return err
+	}
+	if entry != nil {
+		if err := r.CreateTx(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CreateTx inserts an audit entry within a transaction, setting its ID and creation time
+func (r *AuditRepository) CreateTx(ctx context.Context, tx pgx.Tx, entry *models.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (event_id, action, actor, reason, details)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	details := entry.Details
+	if details == nil {
+		details = []byte("{}")
+	}
+
+	return tx.QueryRow(ctx, query,
+		entry.EventID,
+		entry.Action,
+		entry.Actor,
+		entry.Reason,
+		details,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+// GetByEvent returns an event's audit entries, oldest first
+func (r *AuditRepository) GetByEvent(ctx context.Context, eventID int) ([]models.AuditEntry, error) {
+	query := `
+		SELECT id, event_id, action, actor, reason, details, created_at
+		FROM audit_log
+		WHERE event_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var entry models.AuditEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.EventID,
+			&entry.Action,
+			&entry.Actor,
+			&entry.Reason,
+			&entry.Details,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
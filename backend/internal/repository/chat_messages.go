@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+type ChatMessageRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewChatMessageRepository(pool *pgxpool.Pool) *ChatMessageRepository {
+	return &ChatMessageRepository{pool: pool}
+}
+
+// SaveChatMessage inserts a chat message (implements draft.ChatStore interface)
+func (r *ChatMessageRepository) SaveChatMessage(ctx context.Context, eventID, userID int, body string) (*models.ChatMessage, error) {
+	query := `
+		INSERT INTO chat_messages (event_id, user_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, event_id, user_id, body, created_at, deleted_at
+	`
+
+	var msg models.ChatMessage
+	err := r.pool.QueryRow(ctx, query, eventID, userID, body).Scan(
+		&msg.ID,
+		&msg.EventID,
+		&msg.UserID,
+		&msg.Body,
+		&msg.CreatedAt,
+		&msg.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+// DeleteChatMessage soft-deletes a chat message in an event (implements draft.ChatStore interface)
+func (r *ChatMessageRepository) DeleteChatMessage(ctx context.Context, eventID, messageID int) error {
+	query := `
+		UPDATE chat_messages SET deleted_at = NOW()
+		WHERE event_id = $1 AND id = $2 AND deleted_at IS NULL
+	`
+
+	commandTag, err := r.pool.Exec(ctx, query, eventID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetRecentChatMessages returns the latest non-deleted messages for an event, oldest first
+// (implements draft.ChatStore interface)
+func (r *ChatMessageRepository) GetRecentChatMessages(ctx context.Context, eventID, limit int) ([]models.ChatMessage, error) {
+	query := `
+		SELECT id, event_id, user_id, body, created_at, deleted_at
+		FROM (
+			SELECT id, event_id, user_id, body, created_at, deleted_at
+			FROM chat_messages
+			WHERE event_id = $1 AND deleted_at IS NULL
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		) recent
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []models.ChatMessage{}
+	for rows.Next() {
+		var msg models.ChatMessage
+		if err := rows.Scan(
+			&msg.ID,
+			&msg.EventID,
+			&msg.UserID,
+			&msg.Body,
+			&msg.CreatedAt,
+			&msg.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// SetChatMuted mutes or unmutes a user in an event's chat (implements draft.ChatStore interface)
+func (r *ChatMessageRepository) SetChatMuted(ctx context.Context, eventID, userID int, muted bool) error {
+	query := `DELETE FROM chat_mutes WHERE event_id = $1 AND user_id = $2`
+	if muted {
+		query = `
+			INSERT INTO chat_mutes (event_id, user_id)
+			VALUES ($1, $2)
+			ON CONFLICT (event_id, user_id) DO NOTHING
+		`
+	}
+
+	_, err := r.pool.Exec(ctx, query, eventID, userID)
+	return err
+}
+
+// GetChatMutes returns the IDs of the users muted in an event's chat
+// (implements draft.ChatStore interface)
+func (r *ChatMessageRepository) GetChatMutes(ctx context.Context, eventID int) ([]int, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id FROM chat_mutes WHERE event_id = $1`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	userIDs := []int{}
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sblackwood23/fantasy-draft-app/internal/models"
 )
@@ -15,28 +19,96 @@ func NewDraftResultRepository(pool *pgxpool.Pool) *DraftResultRepository {
 	return &DraftResultRepository{pool: pool}
 }
 
-// SavePick inserts a pick into the database (implements draft.PickSaver interface)
-func (r *DraftResultRepository) SavePick(ctx context.Context, eventID, userID, playerID, pickNumber, round int) error {
-	_, err := r.Create(ctx, eventID, userID, playerID, pickNumber, round)
-	return err
+// Errors returned by SavePick when the database rejects a pick
+// All but ErrPickNumberTaken wrap models.ErrPlayerNotDraftable
+var (
+	ErrPlayerNotInPool     = fmt.Errorf("%w: not in this event's player pool", models.ErrPlayerNotDraftable)
+	ErrPickNumberTaken     = errors.New("pick number has already been made")
+	ErrPlayerDraftedOut    = fmt.Errorf("%w: drafted by the maximum number of teams", models.ErrPlayerNotDraftable)
+	ErrPlayerAlreadyOnTeam = fmt.Errorf("%w: already drafted by this team", models.ErrPlayerNotDraftable)
+)
+
+// uniqueViolation is the Postgres error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+// SavePick validates and inserts a pick in a single transaction (implements draft.PickSaver interface)
+// The event row is locked so concurrent picks for the same event are serialized, and
+// UNIQUE(event_id, user_id, player_id) is the final guard against a duplicate pick
+func (r *DraftResultRepository) SavePick(ctx context.Context, eventID, userID, playerID, pickNumber, round int, provenance string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var maxTeamsPerPlayer int
+	err = tx.QueryRow(ctx, `SELECT max_teams_per_player FROM events WHERE id = $1 FOR UPDATE`, eventID).Scan(&maxTeamsPerPlayer)
+	if err != nil {
+		return err
+	}
+
+	var inPool bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM event_players WHERE event_id = $1 AND player_id = $2)
+	`, eventID, playerID).Scan(&inPool)
+	if err != nil {
+		return err
+	}
+	if !inPool {
+		return ErrPlayerNotInPool
+	}
+
+	var pickTaken bool
+	var timesDrafted int
+	err = tx.QueryRow(ctx, `
+		SELECT
+			COALESCE(BOOL_OR(pick_number = $2), false),
+			COUNT(*) FILTER (WHERE player_id = $3)
+		FROM draft_results
+		WHERE event_id = $1
+	`, eventID, pickNumber, playerID).Scan(&pickTaken, &timesDrafted)
+	if err != nil {
+		return err
+	}
+	if pickTaken {
+		return ErrPickNumberTaken
+	}
+	if timesDrafted >= maxTeamsPerPlayer {
+		return ErrPlayerDraftedOut
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO draft_results (event_id, user_id, player_id, pick_number, round, provenance)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, eventID, userID, playerID, pickNumber, round, provenance)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrPlayerAlreadyOnTeam
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 // Create inserts a new draft result (pick) into the database
-func (r *DraftResultRepository) Create(ctx context.Context, eventID, userID, playerID, pickNumber, round int) (*models.DraftResult, error) {
+func (r *DraftResultRepository) Create(ctx context.Context, eventID, userID, playerID, pickNumber, round int, provenance string) (*models.DraftResult, error) {
 	query := `
-		INSERT INTO draft_results (event_id, user_id, player_id, pick_number, round)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, event_id, user_id, player_id, pick_number, round, created_at
+		INSERT INTO draft_results (event_id, user_id, player_id, pick_number, round, provenance)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, event_id, user_id, player_id, pick_number, round, provenance, created_at
 	`
 
 	var result models.DraftResult
-	err := r.pool.QueryRow(ctx, query, eventID, userID, playerID, pickNumber, round).Scan(
+	err := r.pool.QueryRow(ctx, query, eventID, userID, playerID, pickNumber, round, provenance).Scan(
 		&result.ID,
 		&result.EventID,
 		&result.UserID,
 		&result.PlayerID,
 		&result.PickNumber,
 		&result.Round,
+		&result.Provenance,
 		&result.CreatedAt,
 	)
 	if err != nil {
@@ -49,7 +121,7 @@ func (r *DraftResultRepository) Create(ctx context.Context, eventID, userID, pla
 // GetByEvent returns all draft results for a given event
 func (r *DraftResultRepository) GetByEvent(ctx context.Context, eventID int) ([]models.DraftResult, error) {
 	query := `
-		SELECT id, event_id, user_id, player_id, pick_number, round, created_at
+		SELECT id, event_id, user_id, player_id, pick_number, round, provenance, created_at
 		FROM draft_results
 		WHERE event_id = $1
 		ORDER BY pick_number
@@ -71,6 +143,7 @@ func (r *DraftResultRepository) GetByEvent(ctx context.Context, eventID int) ([]
 			&result.PlayerID,
 			&result.PickNumber,
 			&result.Round,
+			&result.Provenance,
 			&result.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -84,7 +157,7 @@ func (r *DraftResultRepository) GetByEvent(ctx context.Context, eventID int) ([]
 // GetByEventAndUser returns all draft results for a given event and user
 func (r *DraftResultRepository) GetByEventAndUser(ctx context.Context, eventID, userID int) ([]models.DraftResult, error) {
 	query := `
-		SELECT id, event_id, user_id, player_id, pick_number, round, created_at
+		SELECT id, event_id, user_id, player_id, pick_number, round, provenance, created_at
 		FROM draft_results
 		WHERE event_id = $1 AND user_id = $2
 		ORDER BY pick_number
@@ -106,6 +179,7 @@ func (r *DraftResultRepository) GetByEventAndUser(ctx context.Context, eventID,
 			&result.PlayerID,
 			&result.PickNumber,
 			&result.Round,
+			&result.Provenance,
 			&result.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -115,3 +189,94 @@ func (r *DraftResultRepository) GetByEventAndUser(ctx context.Context, eventID,
 
 	return results, nil
 }
+
+// GetPicksByEvent returns the pick board for an event, joined with team and player details
+func (r *DraftResultRepository) GetPicksByEvent(ctx context.Context, eventID int) ([]models.DraftPick, error) {
+	query := `
+		SELECT dr.id, dr.event_id, dr.user_id, dr.player_id, dr.pick_number, dr.round, dr.provenance, dr.created_at,
+			u.username, p.id, p.first_name, p.last_name, p.status, p.country_code, p.external_id, p.positions, p.attributes
+		FROM draft_results dr
+		JOIN users u ON u.id = dr.user_id
+		JOIN players p ON p.id = dr.player_id
+		WHERE dr.event_id = $1
+		ORDER BY dr.pick_number
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDraftPicks(rows)
+}
+
+// GetPicksByEventAndUser returns one team's picks for an event, joined with player details
+func (r *DraftResultRepository) GetPicksByEventAndUser(ctx context.Context, eventID, userID int) ([]models.DraftPick, error) {
+	query := `
+		SELECT dr.id, dr.event_id, dr.user_id, dr.player_id, dr.pick_number, dr.round, dr.provenance, dr.created_at,
+			u.username, p.id, p.first_name, p.last_name, p.status, p.country_code, p.external_id, p.positions, p.attributes
+		FROM draft_results dr
+		JOIN users u ON u.id = dr.user_id
+		JOIN players p ON p.id = dr.player_id
+		WHERE dr.event_id = $1 AND dr.user_id = $2
+		ORDER BY dr.pick_number
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDraftPicks(rows)
+}
+
+// GetPicksByUsers returns the picks of several teams, across events, joined with player details
+func (r *DraftResultRepository) GetPicksByUsers(ctx context.Context, userIDs []int) ([]models.DraftPick, error) {
+	query := `
+		SELECT dr.id, dr.event_id, dr.user_id, dr.player_id, dr.pick_number, dr.round, dr.provenance, dr.created_at,
+			u.username, p.id, p.first_name, p.last_name, p.status, p.country_code, p.external_id, p.positions, p.attributes
+		FROM draft_results dr
+		JOIN users u ON u.id = dr.user_id
+		JOIN players p ON p.id = dr.player_id
+		WHERE dr.user_id = ANY($1::int[])
+		ORDER BY dr.event_id, dr.pick_number
+	`
+
+	rows, err := r.pool.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return scanDraftPicks(rows)
+}
+
+// scanDraftPicks reads the rows of a joined pick query and closes them
+func scanDraftPicks(rows pgx.Rows) ([]models.DraftPick, error) {
+	defer rows.Close()
+
+	picks := []models.DraftPick{}
+	for rows.Next() {
+		var pick models.DraftPick
+		if err := rows.Scan(
+			&pick.ID,
+			&pick.EventID,
+			&pick.UserID,
+			&pick.PlayerID,
+			&pick.PickNumber,
+			&pick.Round,
+			&pick.Provenance,
+			&pick.CreatedAt,
+			&pick.Username,
+			&pick.Player.ID,
+			&pick.Player.FirstName,
+			&pick.Player.LastName,
+			&pick.Player.Status,
+			&pick.Player.CountryCode,
+			&pick.Player.ExternalID,
+			&pick.Player.Positions,
+			&pick.Player.Attributes,
+		); err != nil {
+			return nil, err
+		}
+		picks = append(picks, pick)
+	}
+
+	return picks, rows.Err()
+}
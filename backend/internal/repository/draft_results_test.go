@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/testdb"
+)
+
+func TestSavePick(t *testing.T) {
+	pool := testdb.Open(t)
+	repo := NewDraftResultRepository(pool)
+	ctx := context.Background()
+
+	eventID := testdb.Event(t, pool, 4)
+	team1 := testdb.Team(t, pool, eventID, "Team 1")
+	team2 := testdb.Team(t, pool, eventID, "Team 2")
+	players := testdb.Players(t, pool, eventID, 2)
+	outsider := testdb.Players(t, pool, 0, 1)[0]
+
+	if err := repo.SavePick(ctx, eventID, team1, players[0], 1, 1, models.PickProvenanceManual); err != nil {
+		t.Fatalf("first pick: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		userID     int
+		playerID   int
+		pickNumber int
+		want       error
+	}{
+		{"player outside the pool", team2, outsider, 2, ErrPlayerNotInPool},
+		{"pick number already made", team2, players[1], 1, ErrPickNumberTaken},
+		{"player drafted by the maximum number of teams", team2, players[0], 2, ErrPlayerDraftedOut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.SavePick(ctx, eventID, tt.userID, tt.playerID, tt.pickNumber, 1, models.PickProvenanceManual)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("SavePick = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if !errors.Is(ErrPlayerNotInPool, models.ErrPlayerNotDraftable) || errors.Is(ErrPickNumberTaken, models.ErrPlayerNotDraftable) {
+		t.Fatal("only errors about the player may match models.ErrPlayerNotDraftable")
+	}
+
+	// Nothing rejected was written
+	results, err := repo.GetByEvent(ctx, eventID)
+	if err != nil {
+		t.Fatalf("GetByEvent: %v", err)
+	}
+	if len(results) != 1 || results[0].PlayerID != players[0] || results[0].Provenance != models.PickProvenanceManual {
+		t.Fatalf("expected only the first pick to be saved, got %+v", results)
+	}
+}
+
+func TestSavePickConcurrentSamePickNumber(t *testing.T) {
+	pool := testdb.Open(t)
+	repo := NewDraftResultRepository(pool)
+
+	eventID := testdb.Event(t, pool, 4)
+	teams := []int{
+		testdb.Team(t, pool, eventID, "Team 1"),
+		testdb.Team(t, pool, eventID, "Team 2"),
+		testdb.Team(t, pool, eventID, "Team 3"),
+		testdb.Team(t, pool, eventID, "Team 4"),
+	}
+	players := testdb.Players(t, pool, eventID, len(teams))
+
+	// The event row lock serializes the saves, so only one can take pick 1
+	var wg sync.WaitGroup
+	errs := make([]error, len(teams))
+	for i := range teams {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = repo.SavePick(context.Background(), eventID, teams[i], players[i], 1, 1, models.PickProvenanceManual)
+		}()
+	}
+	wg.Wait()
+
+	saved := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			saved++
+		case !errors.Is(err, ErrPickNumberTaken):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if saved != 1 {
+		t.Fatalf("expected exactly one save of pick 1, got %d: %v", saved, errs)
+	}
+}
@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sblackwood23/fantasy-draft-app/internal/models"
 )
@@ -37,13 +41,18 @@ func (r *EventPlayerRepository) GetPlayerIDsByEvent(ctx context.Context, eventID
 	return playerIDs, nil
 }
 
-// GetPlayersByEvent returns full player objects for a given event
+// GetPlayersByEvent returns full player objects for a given event, with their rank in the
+// event's ranking source, best ranked first
 func (r *EventPlayerRepository) GetPlayersByEvent(ctx context.Context, eventID int) ([]models.Player, error) {
 	query := `
-		SELECT p.id, p.first_name, p.last_name, p.status, p.country_code
+		SELECT p.id, p.first_name, p.last_name, p.status, p.country_code, p.external_id, p.positions, p.attributes,
+		       r.rank, r.tier
 		FROM players p
 		INNER JOIN event_players ep ON p.id = ep.player_id
+		INNER JOIN events e ON e.id = ep.event_id
+		LEFT JOIN rankings r ON r.source = e.ranking_source AND r.player_id = p.id
 		WHERE ep.event_id = $1
+		ORDER BY r.rank NULLS LAST, p.id
 	`
 
 	rows, err := r.pool.Query(ctx, query, eventID)
@@ -61,6 +70,11 @@ func (r *EventPlayerRepository) GetPlayersByEvent(ctx context.Context, eventID i
 			&player.LastName,
 			&player.Status,
 			&player.CountryCode,
+			&player.ExternalID,
+			&player.Positions,
+			&player.Attributes,
+			&player.Rank,
+			&player.Tier,
 		); err != nil {
 			return nil, err
 		}
@@ -70,33 +84,237 @@ func (r *EventPlayerRepository) GetPlayersByEvent(ctx context.Context, eventID i
 	return players, nil
 }
 
-// AddPlayerToEvent adds a player to an event
-func (r *EventPlayerRepository) AddPlayerToEvent(ctx context.Context, eventID, playerID int) error {
-	query := `INSERT INTO event_players (event_id, player_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
-	_, err := r.pool.Exec(ctx, query, eventID, playerID)
-	return err
-}
+// SearchPlayersByEvent returns an event's players matching a filter and the total number of matches
+// Players carry their rank and tier in the event's ranking source, and are ordered by it unless
+// the filter sorts otherwise
+func (r *EventPlayerRepository) SearchPlayersByEvent(ctx context.Context, eventID int, filter PlayerFilter) ([]models.Player, int, error) {
+	if filter.Sort == "" {
+		filter.Sort = PlayerSortRank
+	}
+	conditions, args := filter.where([]any{eventID})
+	conditions = append([]string{"ep.event_id = $1"}, conditions...)
+	if filter.AvailableOnly {
+		conditions = append(conditions, `(
+			SELECT COUNT(*) FROM draft_results dr
+			WHERE dr.event_id = ep.event_id AND dr.player_id = p.id
+		) < e.max_teams_per_player`)
+	}
+	from := `
+		FROM players p
+		INNER JOIN event_players ep ON p.id = ep.player_id
+		INNER JOIN events e ON e.id = ep.event_id
+		LEFT JOIN rankings r ON r.source = e.ranking_source AND r.player_id = p.id
+		WHERE ` + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) `+from, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	page, args := filter.page(args)
+	query := `
+		SELECT p.id, p.first_name, p.last_name, p.status, p.country_code, p.external_id, p.positions, p.attributes,
+		       r.rank, r.tier
+		` + from + `
+		` + filter.orderBy() + page
 
-// AddPlayersToEvent adds multiple players to an event
-func (r *EventPlayerRepository) AddPlayersToEvent(ctx context.Context, eventID int, playerIDs []int) error {
-	for _, playerID := range playerIDs {
-		if err := r.AddPlayerToEvent(ctx, eventID, playerID); err != nil {
-			return err
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	players := []models.Player{}
+	for rows.Next() {
+		var player models.Player
+		if err := rows.Scan(
+			&player.ID,
+			&player.FirstName,
+			&player.LastName,
+			&player.Status,
+			&player.CountryCode,
+			&player.ExternalID,
+			&player.Positions,
+			&player.Attributes,
+			&player.Rank,
+			&player.Tier,
+		); err != nil {
+			return nil, 0, err
 		}
+		players = append(players, player)
+	}
+
+	return players, total, rows.Err()
+}
+
+// ErrPoolLocked is returned when an event's player pool is changed after its draft has started
+var ErrPoolLocked = errors.New("player pool cannot change once the draft has started")
+
+// UnknownPlayersError is returned when a pool change names players that do not exist
+type UnknownPlayersError struct {
+	PlayerIDs []int
+}
+
+func (e *UnknownPlayersError) Error() string {
+	return fmt.Sprintf("unknown player IDs: %v", e.PlayerIDs)
+}
+
+// PoolChange reports the outcome of a bulk change to an event's player pool
+type PoolChange struct {
+	Added          []int `json:"added"`
+	Removed        []int `json:"removed"`
+	AlreadyPresent []int `json:"alreadyPresent"` // Requested additions that were already in the pool
+	NotInPool      []int `json:"notInPool"`      // Requested removals that were not in the pool
+}
+
+func newPoolChange() *PoolChange {
+	return &PoolChange{Added: []int{}, Removed: []int{}, AlreadyPresent: []int{}, NotInPool: []int{}}
+}
+
+// AddPlayersTx adds players to an event's pool within a transaction
+// force skips the check that the event's draft has not started.
+// Every ID must exist (otherwise nothing is added and an *UnknownPlayersError is returned);
+// players already in the pool are reported rather than treated as an error.
+func (r *EventPlayerRepository) AddPlayersTx(ctx context.Context, tx pgx.Tx, eventID int, playerIDs []int, force bool) (*PoolChange, error) {
+	if err := lockPool(ctx, tx, eventID, force); err != nil {
+		return nil, err
+	}
+	if err := checkPlayersExist(ctx, tx, playerIDs); err != nil {
+		return nil, err
+	}
+
+	change := newPoolChange()
+	var err error
+	if change.Added, err = insertPoolPlayers(ctx, tx, eventID, playerIDs); err != nil {
+		return nil, err
+	}
+	change.AlreadyPresent = missingFrom(playerIDs, change.Added)
+	return change, nil
+}
+
+// RemovePlayersTx removes players from an event's pool within a transaction (force as for AddPlayersTx)
+// Players that were not in the pool are reported rather than treated as an error.
+func (r *EventPlayerRepository) RemovePlayersTx(ctx context.Context, tx pgx.Tx, eventID int, playerIDs []int, force bool) (*PoolChange, error) {
+	if err := lockPool(ctx, tx, eventID, force); err != nil {
+		return nil, err
+	}
+
+	change := newPoolChange()
+	var err error
+	query := `DELETE FROM event_players WHERE event_id = $1 AND player_id = ANY($2::int[]) RETURNING player_id`
+	if change.Removed, err = collectIDs(tx.Query(ctx, query, eventID, playerIDs)); err != nil {
+		return nil, err
+	}
+	change.NotInPool = missingFrom(playerIDs, change.Removed)
+	return change, nil
+}
+
+// ReplacePlayersTx makes an event's pool exactly the given players within a transaction (force as for AddPlayersTx)
+// Every ID must exist (otherwise nothing changes and an *UnknownPlayersError is returned).
+// AlreadyPresent lists the players that were kept.
+func (r *EventPlayerRepository) ReplacePlayersTx(ctx context.Context, tx pgx.Tx, eventID int, playerIDs []int, force bool) (*PoolChange, error) {
+	if err := lockPool(ctx, tx, eventID, force); err != nil {
+		return nil, err
+	}
+	if err := checkPlayersExist(ctx, tx, playerIDs); err != nil {
+		return nil, err
+	}
+
+	change := newPoolChange()
+	var err error
+	query := `DELETE FROM event_players WHERE event_id = $1 AND player_id <> ALL($2::int[]) RETURNING player_id`
+	if change.Removed, err = collectIDs(tx.Query(ctx, query, eventID, playerIDs)); err != nil {
+		return nil, err
+	}
+	if change.Added, err = insertPoolPlayers(ctx, tx, eventID, playerIDs); err != nil {
+		return nil, err
+	}
+	change.AlreadyPresent = missingFrom(playerIDs, change.Added)
+	return change, nil
+}
+
+// AddPlayersToEventTx adds multiple players to an event within a transaction
+// Players already in the event are ignored. Returns the number of players newly added.
+func (r *EventPlayerRepository) AddPlayersToEventTx(ctx context.Context, tx pgx.Tx, eventID int, playerIDs []int) (int, error) {
+	if err := lockPool(ctx, tx, eventID, false); err != nil {
+		return 0, err
+	}
+	added, err := insertPoolPlayers(ctx, tx, eventID, playerIDs)
+	if err != nil {
+		return 0, err
+	}
+	return len(added), nil
+}
+
+// lockPool locks an event row for the rest of the transaction and checks its pool can still change
+// (always, when force is set). Returns pgx.ErrNoRows if the event does not exist
+func lockPool(ctx context.Context, tx pgx.Tx, eventID int, force bool) error {
+	var status string
+	if err := tx.QueryRow(ctx, `SELECT status FROM events WHERE id = $1 FOR UPDATE`, eventID).Scan(&status); err != nil {
+		return err
+	}
+	if !force && (status == models.EventStatusInProgress || status == models.EventStatusCompleted) {
+		return ErrPoolLocked
 	}
 	return nil
 }
 
-// RemovePlayerFromEvent removes a player from an event
-func (r *EventPlayerRepository) RemovePlayerFromEvent(ctx context.Context, eventID, playerID int) error {
-	query := `DELETE FROM event_players WHERE event_id = $1 AND player_id = $2`
-	_, err := r.pool.Exec(ctx, query, eventID, playerID)
-	return err
+// checkPlayersExist returns an *UnknownPlayersError naming any IDs with no player
+func checkPlayersExist(ctx context.Context, tx pgx.Tx, playerIDs []int) error {
+	query := `
+		SELECT DISTINCT t.player_id FROM UNNEST($1::int[]) AS t(player_id)
+		WHERE NOT EXISTS (SELECT 1 FROM players p WHERE p.id = t.player_id)
+		ORDER BY t.player_id
+	`
+	unknown, err := collectIDs(tx.Query(ctx, query, playerIDs))
+	if err != nil {
+		return err
+	}
+	if len(unknown) > 0 {
+		return &UnknownPlayersError{PlayerIDs: unknown}
+	}
+	return nil
+}
+
+// insertPoolPlayers adds players to an event's pool, skipping ones already there
+// Returns the IDs that were newly added
+func insertPoolPlayers(ctx context.Context, tx pgx.Tx, eventID int, playerIDs []int) ([]int, error) {
+	query := `
+		INSERT INTO event_players (event_id, player_id)
+		SELECT DISTINCT $1::int, player_id FROM UNNEST($2::int[]) AS player_id
+		ON CONFLICT DO NOTHING
+		RETURNING player_id
+	`
+	return collectIDs(tx.Query(ctx, query, eventID, playerIDs))
+}
+
+// collectIDs reads a single integer column from every row of a query
+func collectIDs(rows pgx.Rows, err error) ([]int, error) {
+	if err != nil {
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		ids = []int{}
+	}
+	return ids, nil
 }
 
-// ClearEventPlayers removes all players from an event
-func (r *EventPlayerRepository) ClearEventPlayers(ctx context.Context, eventID int) error {
-	query := `DELETE FROM event_players WHERE event_id = $1`
-	_, err := r.pool.Exec(ctx, query, eventID)
-	return err
+// missingFrom returns the distinct requested IDs that are not in got, in request order
+func missingFrom(requested, got []int) []int {
+	seen := make(map[int]bool, len(got))
+	for _, id := range got {
+		seen[id] = true
+	}
+	missing := []int{}
+	for _, id := range requested {
+		if !seen[id] {
+			missing = append(missing, id)
+			seen[id] = true
+		}
+	}
+	return missing
 }
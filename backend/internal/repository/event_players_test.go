@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/testdb"
+)
+
+func TestPoolChanges(t *testing.T) {
+	pool := testdb.Open(t)
+	repo := NewEventPlayerRepository(pool)
+	ctx := context.Background()
+
+	type changeFunc func(ctx context.Context, tx pgx.Tx, eventID int, playerIDs []int, force bool) (*PoolChange, error)
+	const unknown = -1 // Player IDs are never negative
+
+	// Each case starts from an event whose pool holds players 0 and 1, with player 2 outside it.
+	// Requests and results name players by those indexes (or unknown).
+	tests := []struct {
+		name    string
+		change  changeFunc
+		request []int
+		started bool
+		force   bool
+		want    *PoolChange
+		wantErr error // Matched with errors.Is, or an *UnknownPlayersError
+		// Pool after the transaction commits, or rolls back on error
+		wantPool []int
+	}{
+		{
+			name:     "add",
+			change:   repo.AddPlayersTx,
+			request:  []int{1, 2, 2},
+			want:     &PoolChange{Added: []int{2}, Removed: []int{}, AlreadyPresent: []int{1}, NotInPool: []int{}},
+			wantPool: []int{0, 1, 2},
+		},
+		{
+			name:     "add unknown player",
+			change:   repo.AddPlayersTx,
+			request:  []int{2, unknown},
+			wantErr:  &UnknownPlayersError{PlayerIDs: []int{unknown}},
+			wantPool: []int{0, 1},
+		},
+		{
+			name:     "remove",
+			change:   repo.RemovePlayersTx,
+			request:  []int{0, 2},
+			want:     &PoolChange{Added: []int{}, Removed: []int{0}, AlreadyPresent: []int{}, NotInPool: []int{2}},
+			wantPool: []int{1},
+		},
+		{
+			name:     "replace",
+			change:   repo.ReplacePlayersTx,
+			request:  []int{1, 2},
+			want:     &PoolChange{Added: []int{2}, Removed: []int{0}, AlreadyPresent: []int{1}, NotInPool: []int{}},
+			wantPool: []int{1, 2},
+		},
+		{
+			name:     "replace with unknown player",
+			change:   repo.ReplacePlayersTx,
+			request:  []int{2, unknown},
+			wantErr:  &UnknownPlayersError{PlayerIDs: []int{unknown}},
+			wantPool: []int{0, 1},
+		},
+		{
+			name:     "replace after the draft started",
+			change:   repo.ReplacePlayersTx,
+			request:  []int{2},
+			started:  true,
+			wantErr:  ErrPoolLocked,
+			wantPool: []int{0, 1},
+		},
+		{
+			name:     "remove after the draft started",
+			change:   repo.RemovePlayersTx,
+			request:  []int{0},
+			started:  true,
+			wantErr:  ErrPoolLocked,
+			wantPool: []int{0, 1},
+		},
+		{
+			name:     "forced add after the draft started",
+			change:   repo.AddPlayersTx,
+			request:  []int{2},
+			started:  true,
+			force:    true,
+			want:     &PoolChange{Added: []int{2}, Removed: []int{}, AlreadyPresent: []int{}, NotInPool: []int{}},
+			wantPool: []int{0, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventID := testdb.Event(t, pool, 4)
+			players := append(testdb.Players(t, pool, eventID, 2), testdb.Players(t, pool, 0, 1)...)
+			if tt.started {
+				if _, err := pool.Exec(ctx, `UPDATE events SET status = 'in_progress' WHERE id = $1`, eventID); err != nil {
+					t.Fatalf("start event: %v", err)
+				}
+			}
+			// Maps indexes to player IDs and back
+			toIDs := func(indexes []int) []int {
+				ids := []int{}
+				for _, i := range indexes {
+					if i == unknown {
+						ids = append(ids, unknown)
+					} else {
+						ids = append(ids, players[i])
+					}
+				}
+				return ids
+			}
+			toIndexes := func(ids []int) []int {
+				indexes := []int{}
+				for _, id := range ids {
+					if i := slices.Index(players, id); i >= 0 {
+						indexes = append(indexes, i)
+					} else {
+						indexes = append(indexes, id)
+					}
+				}
+				slices.Sort(indexes)
+				return indexes
+			}
+
+			tx, err := pool.Begin(ctx)
+			if err != nil {
+				t.Fatalf("begin: %v", err)
+			}
+			defer tx.Rollback(ctx)
+			change, err := tt.change(ctx, tx, eventID, toIDs(tt.request), tt.force)
+
+			var wantUnknown *UnknownPlayersError
+			switch {
+			case errors.As(tt.wantErr, &wantUnknown):
+				var unknownErr *UnknownPlayersError
+				if !errors.As(err, &unknownErr) || !reflect.DeepEqual(unknownErr.PlayerIDs, wantUnknown.PlayerIDs) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("change: %v", err)
+			default:
+				got := &PoolChange{
+					Added:          toIndexes(change.Added),
+					Removed:        toIndexes(change.Removed),
+					AlreadyPresent: toIndexes(change.AlreadyPresent),
+					NotInPool:      toIndexes(change.NotInPool),
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("change = %+v, want %+v", got, tt.want)
+				}
+				if err := tx.Commit(ctx); err != nil {
+					t.Fatalf("commit: %v", err)
+				}
+			}
+			tx.Rollback(ctx)
+
+			poolIDs, err := repo.GetPlayerIDsByEvent(ctx, eventID)
+			if err != nil {
+				t.Fatalf("read pool: %v", err)
+			}
+			if got := toIndexes(poolIDs); !reflect.DeepEqual(got, tt.wantPool) {
+				t.Fatalf("pool = %v, want %v", got, tt.wantPool)
+			}
+		})
+	}
+}
+
+func TestPoolChangeUnknownEvent(t *testing.T) {
+	pool := testdb.Open(t)
+	repo := NewEventPlayerRepository(pool)
+	ctx := context.Background()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	if _, err := repo.AddPlayersTx(ctx, tx, -1, []int{}, false); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("error = %v, want %v", err, pgx.ErrNoRows)
+	}
+}
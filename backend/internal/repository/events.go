@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -21,7 +22,8 @@ func NewEventRepository(pool *pgxpool.Pool) *EventRepository {
 func (r *EventRepository) GetByID(ctx context.Context, id int) (*models.Event, error) {
 	query := `
 		SELECT id, name, max_picks_per_team, max_teams_per_player,
-		       stipulations, status, passkey, created_at, started_at, completed_at
+		       max_spectators, max_teams, league_id, commissioner_user_id, stipulations, roster_slots, ranking_source, status, passkey_hash,
+		       registration_opens_at, registration_closes_at, late_registration, created_at, started_at, completed_at
 		FROM events
 		WHERE id = $1
 	`
@@ -32,9 +34,18 @@ func (r *EventRepository) GetByID(ctx context.Context, id int) (*models.Event, e
 		&event.Name,
 		&event.MaxPicksPerTeam,
 		&event.MaxTeamsPerPlayer,
+		&event.MaxSpectators,
+		&event.MaxTeams,
+		&event.LeagueID,
+		&event.CommissionerID,
 		&event.Stipulations,
+		&event.RosterSlots,
+		&event.RankingSource,
 		&event.Status,
-		&event.Passkey,
+		&event.PasskeyHash,
+		&event.RegistrationOpensAt,
+		&event.RegistrationClosesAt,
+		&event.LateRegistration,
 		&event.CreatedAt,
 		&event.StartedAt,
 		&event.CompletedAt,
@@ -43,19 +54,31 @@ func (r *EventRepository) GetByID(ctx context.Context, id int) (*models.Event, e
 	if err != nil {
 		return nil, err
 	}
+	event.HasPasskey = event.PasskeyHash != nil
 
 	return &event, nil
 }
 
 // Retrieves all events
 func (r *EventRepository) GetAll(ctx context.Context) ([]models.Event, error) {
+	return r.list(ctx, "")
+}
+
+// GetByLeague returns a league's events, oldest first
+func (r *EventRepository) GetByLeague(ctx context.Context, leagueID int) ([]models.Event, error) {
+	return r.list(ctx, "WHERE league_id = $1 ORDER BY created_at, id", leagueID)
+}
+
+// list returns the events selected by a WHERE/ORDER BY clause
+func (r *EventRepository) list(ctx context.Context, clause string, args ...any) ([]models.Event, error) {
 	query := `
 		SELECT id, name, max_picks_per_team, max_teams_per_player,
-		       stipulations, status, passkey, created_at, started_at, completed_at
+		       max_spectators, max_teams, league_id, commissioner_user_id, stipulations, roster_slots, ranking_source, status, passkey_hash,
+		       registration_opens_at, registration_closes_at, late_registration, created_at, started_at, completed_at
 		FROM events
-	`
+	` + clause
 
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -69,9 +92,18 @@ func (r *EventRepository) GetAll(ctx context.Context) ([]models.Event, error) {
 			&event.Name,
 			&event.MaxPicksPerTeam,
 			&event.MaxTeamsPerPlayer,
+			&event.MaxSpectators,
+			&event.MaxTeams,
+			&event.LeagueID,
+			&event.CommissionerID,
 			&event.Stipulations,
+			&event.RosterSlots,
+			&event.RankingSource,
 			&event.Status,
-			&event.Passkey,
+			&event.PasskeyHash,
+			&event.RegistrationOpensAt,
+			&event.RegistrationClosesAt,
+			&event.LateRegistration,
 			&event.CreatedAt,
 			&event.StartedAt,
 			&event.CompletedAt,
@@ -79,6 +111,7 @@ func (r *EventRepository) GetAll(ctx context.Context) ([]models.Event, error) {
 		if err != nil {
 			return nil, err
 		}
+		event.HasPasskey = event.PasskeyHash != nil
 		events = append(events, event)
 	}
 
@@ -87,37 +120,68 @@ func (r *EventRepository) GetAll(ctx context.Context) ([]models.Event, error) {
 
 // Create new record in events table
 func (r *EventRepository) Create(ctx context.Context, event *models.Event) error {
+	query, args := eventInsert(event)
+	return r.pool.QueryRow(ctx, query, args...).Scan(&event.ID, &event.CreatedAt)
+}
+
+// CreateTx creates an event within a transaction
+func (r *EventRepository) CreateTx(ctx context.Context, tx pgx.Tx, event *models.Event) error {
+	query, args := eventInsert(event)
+	return tx.QueryRow(ctx, query, args...).Scan(&event.ID, &event.CreatedAt)
+}
+
+// eventInsert returns the query and arguments that insert an event, returning its id and created_at
+func eventInsert(event *models.Event) (string, []any) {
 	query := `
-    INSERT INTO events (name, max_picks_per_team, max_teams_per_player, stipulations, status, passkey)
-    VALUES ($1, $2, $3, $4, $5, $6)
+    INSERT INTO events (name, max_picks_per_team, max_teams_per_player, max_spectators, max_teams, league_id, commissioner_user_id, stipulations, roster_slots, ranking_source, status, passkey_hash,
+                        registration_opens_at, registration_closes_at, late_registration)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
     RETURNING id, created_at
 `
-	err := r.pool.QueryRow(ctx, query,
+	return query, []any{
 		event.Name,
 		event.MaxPicksPerTeam,
 		event.MaxTeamsPerPlayer,
+		event.MaxSpectators,
+		event.MaxTeams,
+		event.LeagueID,
+		event.CommissionerID,
 		event.Stipulations,
+		event.RosterSlots,
+		event.RankingSource,
 		event.Status,
-		event.Passkey,
-	).Scan(&event.ID, &event.CreatedAt)
-
-	return err
+		event.PasskeyHash,
+		event.RegistrationOpensAt,
+		event.RegistrationClosesAt,
+		event.LateRegistration,
+	}
 }
 
-// Update record in events table
-func (r *EventRepository) Update(ctx context.Context, event *models.Event) error {
+// UpdateTx updates a record in the events table within a transaction
+// The commissioner is left as it is: only joining (Register) and league events set it.
+func (r *EventRepository) UpdateTx(ctx context.Context, tx pgx.Tx, event *models.Event) error {
 	query := `
-		UPDATE events SET name=$1, max_picks_per_team=$2, max_teams_per_player=$3, stipulations=$4, status=$5, passkey=$6
-		WHERE id=$7
+		UPDATE events SET name=$1, max_picks_per_team=$2, max_teams_per_player=$3, max_spectators=$4, max_teams=$5, league_id=$6,
+		       stipulations=$7, roster_slots=$8, ranking_source=$9, status=$10, passkey_hash=$11,
+		       registration_opens_at=$12, registration_closes_at=$13, late_registration=$14
+		WHERE id=$15
 	`
 
-	commandTag, err := r.pool.Exec(ctx, query,
+	commandTag, err := tx.Exec(ctx, query,
 		event.Name,
 		event.MaxPicksPerTeam,
 		event.MaxTeamsPerPlayer,
+		event.MaxSpectators,
+		event.MaxTeams,
+		event.LeagueID,
 		event.Stipulations,
+		event.RosterSlots,
+		event.RankingSource,
 		event.Status,
-		event.Passkey,
+		event.PasskeyHash,
+		event.RegistrationOpensAt,
+		event.RegistrationClosesAt,
+		event.LateRegistration,
 		event.ID,
 	)
 
@@ -132,14 +196,14 @@ func (r *EventRepository) Update(ctx context.Context, event *models.Event) error
 	return nil
 }
 
-// Delete record from events table
-func (r *EventRepository) Delete(ctx context.Context, id int) error {
+// DeleteTx deletes a record from the events table within a transaction
+func (r *EventRepository) DeleteTx(ctx context.Context, tx pgx.Tx, id int) error {
 	query := `
 		DELETE FROM events
 		WHERE id=$1
 	`
 
-	commandTag, err := r.pool.Exec(ctx, query, id)
+	commandTag, err := tx.Exec(ctx, query, id)
 
 	if err != nil {
 		return err
@@ -152,34 +216,10 @@ func (r *EventRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
-// GetByPasskey retrieves an event by its passkey
-func (r *EventRepository) GetByPasskey(ctx context.Context, passkey string) (*models.Event, error) {
-	query := `
-		SELECT id, name, max_picks_per_team, max_teams_per_player,
-		       stipulations, status, passkey, created_at, started_at, completed_at
-		FROM events
-		WHERE passkey = $1
-	`
-
-	var event models.Event
-	err := r.pool.QueryRow(ctx, query, passkey).Scan(
-		&event.ID,
-		&event.Name,
-		&event.MaxPicksPerTeam,
-		&event.MaxTeamsPerPlayer,
-		&event.Stipulations,
-		&event.Status,
-		&event.Passkey,
-		&event.CreatedAt,
-		&event.StartedAt,
-		&event.CompletedAt,
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return &event, nil
+// SetPasskeyHash replaces an event's stored passkey hash, e.g. to upgrade an old hash format
+func (r *EventRepository) SetPasskeyHash(ctx context.Context, eventID int, hash string) error {
+	_, err := r.pool.Exec(ctx, `UPDATE events SET passkey_hash = $1 WHERE id = $2`, hash, eventID)
+	return err
 }
 
 // UpdateStatus updates only the status field and corresponding timestamp
@@ -207,3 +247,90 @@ func (r *EventRepository) UpdateStatus(ctx context.Context, eventID int, status
 
 	return nil
 }
+
+// GetMaxSpectators returns an event's spectator cap (implements draft.SpectatorLimits)
+func (r *EventRepository) GetMaxSpectators(ctx context.Context, eventID int) (int, error) {
+	var maxSpectators int
+	if err := r.pool.QueryRow(ctx, `SELECT max_spectators FROM events WHERE id = $1`, eventID).Scan(&maxSpectators); err != nil {
+		return 0, err
+	}
+
+	return maxSpectators, nil
+}
+
+// IsCommissioner reports whether the user is the event's commissioner (implements draft.CommissionerChecker)
+func (r *EventRepository) IsCommissioner(ctx context.Context, eventID, userID int) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND commissioner_user_id = $2)`
+
+	var ok bool
+	if err := r.pool.QueryRow(ctx, query, eventID, userID).Scan(&ok); err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+// ErrTooManyTeams is returned by Clone when the source event has more teams than the new event allows
+var ErrTooManyTeams = errors.New("source event has more teams than maxTeams")
+
+// Clone creates an event from another one in a single transaction, optionally copying its
+// player pool and its teams. Copied teams keep their profiles and avatars but not their PINs
+// or rejoin links, so they are unclaimed until the commissioner resets them; they stay linked
+// to their league members if both events are in the same league. The team named like the
+// source's commissioner becomes the new commissioner.
+func (r *EventRepository) Clone(ctx context.Context, sourceID int, event *models.Event, playerPool, teams bool) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	event.CommissionerID = nil
+	if err := r.CreateTx(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if playerPool {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO event_players (event_id, player_id)
+			SELECT $2, player_id FROM event_players WHERE event_id = $1
+		`, sourceID, event.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if teams {
+		commandTag, err := tx.Exec(ctx, `
+			INSERT INTO users (event_id, username, display_name, color, email, avatar, avatar_content_type, avatar_updated_at, league_member_id, unclaimed)
+			SELECT $2, u.username, u.display_name, u.color, u.email, u.avatar, u.avatar_content_type, u.avatar_updated_at,
+			       CASE WHEN src.league_id IS NOT DISTINCT FROM $3 THEN u.league_member_id END, true
+			FROM users u
+			JOIN events src ON src.id = u.event_id
+			WHERE u.event_id = $1
+			ORDER BY u.id
+		`, sourceID, event.ID, event.LeagueID)
+		if err != nil {
+			return err
+		}
+		if int(commandTag.RowsAffected()) > event.MaxTeams {
+			return ErrTooManyTeams
+		}
+
+		err = tx.QueryRow(ctx, `
+			UPDATE events SET commissioner_user_id = (
+				SELECT n.id FROM users n
+				JOIN users o ON o.username = n.username
+				JOIN events src ON src.commissioner_user_id = o.id
+				WHERE src.id = $1 AND n.event_id = $2
+			)
+			WHERE id = $2
+			RETURNING commissioner_user_id
+		`, sourceID, event.ID).Scan(&event.CommissionerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
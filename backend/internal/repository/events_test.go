@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/testdb"
+)
+
+func TestClone(t *testing.T) {
+	pool := testdb.Open(t)
+	repo := NewEventRepository(pool)
+	ctx := context.Background()
+
+	// The source has two players in its pool and two teams, the second with a PIN and the commissioner's
+	source := testdb.Event(t, pool, 4)
+	players := testdb.Players(t, pool, source, 2)
+	testdb.Team(t, pool, source, "Ann")
+	commissioner := testdb.Team(t, pool, source, "Bob")
+	_, err := pool.Exec(ctx, `UPDATE users SET pin_hash = 'hash' WHERE id = $1`, commissioner)
+	if err == nil {
+		_, err = pool.Exec(ctx, `UPDATE events SET commissioner_user_id = $2 WHERE id = $1`, source, commissioner)
+	}
+	if err != nil {
+		t.Fatalf("set up source event: %v", err)
+	}
+
+	// A copied team's name, and whether it is unclaimed and has no PIN
+	type team struct {
+		name             string
+		unclaimed, noPIN bool
+	}
+
+	tests := []struct {
+		name             string
+		maxTeams         int
+		playerPool       bool
+		teams            bool
+		wantErr          error
+		wantPool         []int
+		wantTeams        []team
+		wantCommissioner string // Name of the new commissioner's team, if any
+	}{
+		{
+			name:      "settings only",
+			maxTeams:  4,
+			wantPool:  []int{},
+			wantTeams: []team{},
+		},
+		{
+			name:       "player pool",
+			maxTeams:   4,
+			playerPool: true,
+			wantPool:   players,
+			wantTeams:  []team{},
+		},
+		{
+			name:             "teams",
+			maxTeams:         4,
+			teams:            true,
+			wantPool:         []int{},
+			wantTeams:        []team{{"Ann", true, true}, {"Bob", true, true}},
+			wantCommissioner: "Bob",
+		},
+		{
+			name:       "more teams than allowed",
+			maxTeams:   1,
+			playerPool: true,
+			teams:      true,
+			wantErr:    ErrTooManyTeams,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := models.NewEvent(t.Name())
+			event.MaxTeams = tt.maxTeams
+			err := repo.Clone(ctx, source, &event, tt.playerPool, tt.teams)
+			if event.ID != 0 {
+				t.Cleanup(func() { pool.Exec(context.Background(), `DELETE FROM events WHERE id = $1`, event.ID) })
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Clone = %v, want %v", err, tt.wantErr)
+				}
+				// Nothing was created, not even the event
+				var count int
+				if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM events WHERE name = $1`, t.Name()).Scan(&count); err != nil {
+					t.Fatalf("count events: %v", err)
+				}
+				if count != 0 {
+					t.Fatalf("expected the clone to roll back, found %d events", count)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Clone: %v", err)
+			}
+
+			poolIDs, err := NewEventPlayerRepository(pool).GetPlayerIDsByEvent(ctx, event.ID)
+			if err != nil {
+				t.Fatalf("read pool: %v", err)
+			}
+			slices.Sort(poolIDs)
+			if fmt.Sprint(poolIDs) != fmt.Sprint(tt.wantPool) {
+				t.Fatalf("pool = %v, want %v", poolIDs, tt.wantPool)
+			}
+
+			rows, err := pool.Query(ctx, `SELECT id, username, unclaimed, pin_hash IS NULL FROM users WHERE event_id = $1 ORDER BY id`, event.ID)
+			if err != nil {
+				t.Fatalf("read teams: %v", err)
+			}
+			teams := []team{}
+			names := make(map[int]string)
+			for rows.Next() {
+				var id int
+				var tm team
+				if err := rows.Scan(&id, &tm.name, &tm.unclaimed, &tm.noPIN); err != nil {
+					t.Fatalf("scan team: %v", err)
+				}
+				teams = append(teams, tm)
+				names[id] = tm.name
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatalf("read teams: %v", err)
+			}
+			if !reflect.DeepEqual(teams, tt.wantTeams) {
+				t.Fatalf("teams = %+v, want %+v", teams, tt.wantTeams)
+			}
+
+			switch {
+			case tt.wantCommissioner == "" && event.CommissionerID != nil:
+				t.Fatalf("expected no commissioner, got team %d", *event.CommissionerID)
+			case tt.wantCommissioner != "" && (event.CommissionerID == nil || names[*event.CommissionerID] != tt.wantCommissioner):
+				t.Fatalf("expected %s's copy to be the commissioner, got %v", tt.wantCommissioner, event.CommissionerID)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+type LeagueRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLeagueRepository(pool *pgxpool.Pool) *LeagueRepository {
+	return &LeagueRepository{pool: pool}
+}
+
+// Errors returned when league members and teams conflict
+var (
+	ErrMemberNameTaken  = errors.New("league already has a member with this name")
+	ErrMemberHasTeam    = errors.New("member already has a team in this event")
+	ErrTooManyMembers   = errors.New("league has more members than the event's maxTeams")
+	ErrMemberNotInEvent = errors.New("commissioner must be a member of the league")
+)
+
+// GetByID retrieves a single league
+func (r *LeagueRepository) GetByID(ctx context.Context, id int) (*models.League, error) {
+	query := `SELECT id, name, created_at FROM leagues WHERE id = $1`
+
+	var league models.League
+	if err := r.pool.QueryRow(ctx, query, id).Scan(&league.ID, &league.Name, &league.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &league, nil
+}
+
+// GetAll retrieves every league
+func (r *LeagueRepository) GetAll(ctx context.Context) ([]models.League, error) {
+	query := `SELECT id, name, created_at FROM leagues ORDER BY id`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leagues := []models.League{}
+	for rows.Next() {
+		var league models.League
+		if err := rows.Scan(&league.ID, &league.Name, &league.CreatedAt); err != nil {
+			return nil, err
+		}
+		leagues = append(leagues, league)
+	}
+
+	return leagues, rows.Err()
+}
+
+// Create inserts a league, setting its ID and creation time
+func (r *LeagueRepository) Create(ctx context.Context, league *models.League) error {
+	query := `INSERT INTO leagues (name) VALUES ($1) RETURNING id, created_at`
+	return r.pool.QueryRow(ctx, query, league.Name).Scan(&league.ID, &league.CreatedAt)
+}
+
+// Update renames a league
+func (r *LeagueRepository) Update(ctx context.Context, league *models.League) error {
+	query := `UPDATE leagues SET name = $1 WHERE id = $2 RETURNING created_at`
+	return r.pool.QueryRow(ctx, query, league.Name, league.ID).Scan(&league.CreatedAt)
+}
+
+// Delete removes a league and its members
+// The league's events and teams are kept, no longer linked to it.
+func (r *LeagueRepository) Delete(ctx context.Context, id int) error {
+	commandTag, err := r.pool.Exec(ctx, `DELETE FROM leagues WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetMembers returns a league's members in the order they were added
+func (r *LeagueRepository) GetMembers(ctx context.Context, leagueID int) ([]models.LeagueMember, error) {
+	query := `
+		SELECT id, league_id, name, created_at
+		FROM league_members
+		WHERE league_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.pool.Query(ctx, query, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.LeagueMember{}
+	for rows.Next() {
+		var member models.LeagueMember
+		if err := rows.Scan(&member.ID, &member.LeagueID, &member.Name, &member.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// GetMember retrieves one member of a league
+func (r *LeagueRepository) GetMember(ctx context.Context, leagueID, memberID int) (*models.LeagueMember, error) {
+	query := `SELECT id, league_id, name, created_at FROM league_members WHERE id = $1 AND league_id = $2`
+
+	var member models.LeagueMember
+	err := r.pool.QueryRow(ctx, query, memberID, leagueID).Scan(&member.ID, &member.LeagueID, &member.Name, &member.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// AddMember adds a person to a league
+// Returns ErrMemberNameTaken if the league already has a member with the name
+func (r *LeagueRepository) AddMember(ctx context.Context, member *models.LeagueMember) error {
+	query := `INSERT INTO league_members (league_id, name) VALUES ($1, $2) RETURNING id, created_at`
+
+	err := r.pool.QueryRow(ctx, query, member.LeagueID, member.Name).Scan(&member.ID, &member.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return ErrMemberNameTaken
+	}
+	return err
+}
+
+// RemoveMember removes a person from a league; their teams are kept but unlinked
+func (r *LeagueRepository) RemoveMember(ctx context.Context, leagueID, memberID int) error {
+	commandTag, err := r.pool.Exec(ctx, `DELETE FROM league_members WHERE id = $1 AND league_id = $2`, memberID, leagueID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// LinkTeam makes a member the owner of a team in one of the league's events
+// Returns pgx.ErrNoRows unless the member is in the league and the team's event belongs to it,
+// and ErrMemberHasTeam if the member already owns another team in that event.
+func (r *LeagueRepository) LinkTeam(ctx context.Context, leagueID, memberID, userID int) error {
+	query := `
+		UPDATE users u SET league_member_id = lm.id
+		FROM league_members lm, events e
+		WHERE u.id = $3 AND lm.id = $2 AND lm.league_id = $1
+		  AND e.id = u.event_id AND e.league_id = $1
+	`
+
+	commandTag, err := r.pool.Exec(ctx, query, leagueID, memberID, userID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrMemberHasTeam
+		}
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// UnlinkTeam removes a member's ownership of a team
+func (r *LeagueRepository) UnlinkTeam(ctx context.Context, leagueID, memberID, userID int) error {
+	query := `
+		UPDATE users SET league_member_id = NULL
+		WHERE id = $3 AND league_member_id = $2
+		  AND EXISTS (SELECT 1 FROM league_members WHERE id = $2 AND league_id = $1)
+	`
+
+	commandTag, err := r.pool.Exec(ctx, query, leagueID, memberID, userID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// CreateEvent creates an event in a league with a team for every member, in one transaction
+// Teams are named after their members. The commissioner is the given member's team, or the
+// first member's when commissionerMemberID is nil. Returns pgx.ErrNoRows if the league does not exist.
+func (r *LeagueRepository) CreateEvent(ctx context.Context, leagueID int, event *models.Event, commissionerMemberID *int) ([]models.User, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the league so its membership cannot change while the teams are created
+	if err := tx.QueryRow(ctx, `SELECT id FROM leagues WHERE id = $1 FOR UPDATE`, leagueID).Scan(&leagueID); err != nil {
+		return nil, err
+	}
+
+	var members int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM league_members WHERE league_id = $1`, leagueID).Scan(&members); err != nil {
+		return nil, err
+	}
+	if members > event.MaxTeams {
+		return nil, ErrTooManyMembers
+	}
+
+	event.LeagueID = &leagueID
+	event.CommissionerID = nil
+	query, args := eventInsert(event)
+	if err := tx.QueryRow(ctx, query, args...).Scan(&event.ID, &event.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		INSERT INTO users (event_id, username, league_member_id)
+		SELECT $1, name, id FROM league_members WHERE league_id = $2
+		ORDER BY id
+		RETURNING id, event_id, username, league_member_id, created_at
+	`, event.ID, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.User, error) {
+		var user models.User
+		err := row.Scan(&user.ID, &user.EventID, &user.Username, &user.LeagueMemberID, &user.CreatedAt)
+		user.SetComputedFields()
+		return user, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if teams == nil {
+		teams = []models.User{}
+	}
+
+	// RETURNING rows come back in insertion order, so the first team is the first member's
+	for _, team := range teams {
+		if commissionerMemberID == nil || *team.LeagueMemberID == *commissionerMemberID {
+			event.CommissionerID = &team.ID
+			break
+		}
+	}
+	if commissionerMemberID != nil && event.CommissionerID == nil {
+		return nil, ErrMemberNotInEvent
+	}
+	if event.CommissionerID != nil {
+		_, err := tx.Exec(ctx, `UPDATE events SET commissioner_user_id = $2 WHERE id = $1`, event.ID, *event.CommissionerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// GetMemberTeams returns the teams owned by a league's members in the league's events,
+// oldest event first, without their picks
+func (r *LeagueRepository) GetMemberTeams(ctx context.Context, leagueID int) ([]models.MemberTeam, error) {
+	query := `
+		SELECT u.league_member_id, e.id, e.name, e.status, e.started_at, e.completed_at, u.id, u.username
+		FROM users u
+		JOIN events e ON e.id = u.event_id
+		JOIN league_members lm ON lm.id = u.league_member_id
+		WHERE lm.league_id = $1 AND e.league_id = $1
+		ORDER BY e.created_at, e.id, u.id
+	`
+
+	rows, err := r.pool.Query(ctx, query, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []models.MemberTeam{}
+	for rows.Next() {
+		var team models.MemberTeam
+		if err := rows.Scan(
+			&team.MemberID,
+			&team.EventID,
+			&team.EventName,
+			&team.Status,
+			&team.StartedAt,
+			&team.CompletedAt,
+			&team.UserID,
+			&team.TeamName,
+		); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, rows.Err()
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+type NotificationPrefsRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationPrefsRepository(pool *pgxpool.Pool) *NotificationPrefsRepository {
+	return &NotificationPrefsRepository{pool: pool}
+}
+
+// GetByUser returns a team's notification preferences, or the defaults if it never set any
+// Returns pgx.ErrNoRows if the team does not exist
+func (r *NotificationPrefsRepository) GetByUser(ctx context.Context, userID int) (*models.NotificationPrefs, error) {
+	query := `
+		SELECT u.id, COALESCE(np.email, FALSE), COALESCE(np.webhook, FALSE),
+		       COALESCE(np.on_deck_picks, 0), COALESCE(np.clock_warning_seconds, 0), COALESCE(np.updated_at, u.created_at)
+		FROM users u
+		LEFT JOIN notification_preferences np ON np.user_id = u.id
+		WHERE u.id = $1
+	`
+
+	var prefs models.NotificationPrefs
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&prefs.UserID,
+		&prefs.Email,
+		&prefs.Webhook,
+		&prefs.OnDeckPicks,
+		&prefs.ClockWarningSeconds,
+		&prefs.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &prefs, nil
+}
+
+// Upsert saves a team's notification preferences
+// Returns pgx.ErrNoRows if the team does not exist
+func (r *NotificationPrefsRepository) Upsert(ctx context.Context, prefs *models.NotificationPrefs) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, email, webhook, on_deck_picks, clock_warning_seconds)
+		SELECT id, $2, $3, $4, $5 FROM users WHERE id = $1
+		ON CONFLICT (user_id) DO UPDATE
+		SET email = EXCLUDED.email, webhook = EXCLUDED.webhook, on_deck_picks = EXCLUDED.on_deck_picks,
+		    clock_warning_seconds = EXCLUDED.clock_warning_seconds, updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	return r.pool.QueryRow(ctx, query, prefs.UserID, prefs.Email, prefs.Webhook, prefs.OnDeckPicks, prefs.ClockWarningSeconds).Scan(&prefs.UpdatedAt)
+}
+
+// GetNotificationPrefs returns the preferences of an event's teams that opted in to a notification,
+// with the team's name, contact email and the event's name filled in
+func (r *NotificationPrefsRepository) GetNotificationPrefs(ctx context.Context, eventID int) ([]models.NotificationPrefs, error) {
+	query := `
+		SELECT np.user_id, np.email, np.webhook, np.on_deck_picks, np.clock_warning_seconds, np.updated_at,
+		       COALESCE(u.display_name, u.username), u.email, e.name
+		FROM notification_preferences np
+		JOIN users u ON u.id = np.user_id
+		JOIN events e ON e.id = u.event_id
+		WHERE u.event_id = $1 AND (np.email OR np.webhook) AND (np.on_deck_picks > 0 OR np.clock_warning_seconds > 0)
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prefs := []models.NotificationPrefs{}
+	for rows.Next() {
+		var p models.NotificationPrefs
+		err := rows.Scan(
+			&p.UserID,
+			&p.Email,
+			&p.Webhook,
+			&p.OnDeckPicks,
+			&p.ClockWarningSeconds,
+			&p.UpdatedAt,
+			&p.TeamName,
+			&p.TeamEmail,
+			&p.EventName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		prefs = append(prefs, p)
+	}
+
+	return prefs, rows.Err()
+}
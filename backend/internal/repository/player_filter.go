@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// Player sort fields accepted by PlayerFilter
+const (
+	PlayerSortID          = "id"
+	PlayerSortName        = "name"
+	PlayerSortCountryCode = "countryCode"
+	PlayerSortStatus      = "status"
+	PlayerSortRank        = "rank" // Event player listings only: the event's ranking source
+)
+
+// playerSortColumns maps sort fields to ORDER BY columns (p is the players table)
+var playerSortColumns = map[string][]string{
+	PlayerSortID:          {"p.id"},
+	PlayerSortName:        {"p.last_name", "p.first_name"},
+	PlayerSortCountryCode: {"p.country_code", "p.last_name", "p.first_name"},
+	PlayerSortStatus:      {"p.status", "p.last_name", "p.first_name"},
+	PlayerSortRank:        {"r.rank"},
+}
+
+// PlayerFilter narrows, sorts and pages a player listing
+type PlayerFilter struct {
+	Search        string // Case- and accent-insensitive substring of the full name
+	Status        string
+	CountryCode   string
+	Position      string                 // Players who can play this position
+	Attributes    map[string]interface{} // Players whose attributes contain all of these key/value pairs
+	Sort          string                 // One of the PlayerSort constants; defaults to id (rank for event players)
+	Desc          bool
+	Limit         int // 0 returns every match
+	Offset        int
+	AvailableOnly bool // Event players only: exclude players drafted the maximum number of times
+}
+
+// ValidSort reports whether a sort field is supported
+func ValidSort(field string) bool {
+	_, ok := playerSortColumns[field]
+	return ok
+}
+
+// where builds the WHERE conditions for the filter, appending bind values to args
+func (f PlayerFilter) where(args []any) ([]string, []any) {
+	var conditions []string
+
+	if f.Search != "" {
+		args = append(args, escapeLike(f.Search))
+		conditions = append(conditions, fmt.Sprintf(
+			"f_unaccent(LOWER(p.first_name || ' ' || p.last_name)) LIKE '%%' || f_unaccent(LOWER($%d)) || '%%'", len(args)))
+	}
+	if f.Status != "" {
+		args = append(args, f.Status)
+		conditions = append(conditions, fmt.Sprintf("p.status = $%d", len(args)))
+	}
+	if f.CountryCode != "" {
+		args = append(args, strings.ToUpper(f.CountryCode))
+		conditions = append(conditions, fmt.Sprintf("p.country_code = $%d", len(args)))
+	}
+	if f.Position != "" {
+		args = append(args, strings.ToUpper(f.Position))
+		conditions = append(conditions, fmt.Sprintf("p.positions @> ARRAY[$%d::text]", len(args)))
+	}
+	if len(f.Attributes) > 0 {
+		// JSONB containment so the GIN index on attributes can be used
+		args = append(args, models.Attributes(f.Attributes))
+		conditions = append(conditions, fmt.Sprintf("p.attributes @> $%d::jsonb", len(args)))
+	}
+
+	return conditions, args
+}
+
+// orderBy builds the ORDER BY clause, always ending with the ID so paging is stable
+func (f PlayerFilter) orderBy() string {
+	direction := "ASC"
+	if f.Desc {
+		direction = "DESC"
+	}
+
+	columns, ok := playerSortColumns[f.Sort]
+	if !ok {
+		columns = playerSortColumns[PlayerSortID]
+	}
+
+	terms := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		if column == "r.rank" {
+			// Unranked players always come after ranked ones
+			terms = append(terms, column+" "+direction+" NULLS LAST")
+			continue
+		}
+		terms = append(terms, column+" "+direction)
+	}
+	if columns[len(columns)-1] != "p.id" {
+		terms = append(terms, "p.id "+direction)
+	}
+	return "ORDER BY " + strings.Join(terms, ", ")
+}
+
+// page builds the LIMIT/OFFSET clause, appending bind values to args
+func (f PlayerFilter) page(args []any) (string, []any) {
+	var clause string
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		clause = fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if f.Offset > 0 {
+		args = append(args, f.Offset)
+		clause += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+	return clause, args
+}
+
+// escapeLike escapes LIKE wildcards so a search term matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
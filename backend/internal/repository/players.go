@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -18,7 +21,7 @@ func NewPlayerRepository(pool *pgxpool.Pool) *PlayerRepository {
 
 func (r *PlayerRepository) GetByID(ctx context.Context, id int) (*models.Player, error) {
 	query := `
-		SELECT id, first_name, last_name, status, country_code
+		SELECT id, first_name, last_name, status, country_code, external_id, positions, attributes
 		FROM players
 		WHERE id = $1
 	`
@@ -30,6 +33,9 @@ func (r *PlayerRepository) GetByID(ctx context.Context, id int) (*models.Player,
 		&player.LastName,
 		&player.Status,
 		&player.CountryCode,
+		&player.ExternalID,
+		&player.Positions,
+		&player.Attributes,
 	)
 
 	if err != nil {
@@ -42,7 +48,7 @@ func (r *PlayerRepository) GetByID(ctx context.Context, id int) (*models.Player,
 // Retrieves all players
 func (r *PlayerRepository) GetAll(ctx context.Context) ([]models.Player, error) {
 	query := `
-		SELECT id, first_name, last_name, status, country_code
+		SELECT id, first_name, last_name, status, country_code, external_id, positions, attributes
 		FROM players
 	`
 
@@ -61,6 +67,9 @@ func (r *PlayerRepository) GetAll(ctx context.Context) ([]models.Player, error)
 			&player.LastName,
 			&player.Status,
 			&player.CountryCode,
+			&player.ExternalID,
+			&player.Positions,
+			&player.Attributes,
 		)
 		if err != nil {
 			return nil, err
@@ -71,11 +80,59 @@ func (r *PlayerRepository) GetAll(ctx context.Context) ([]models.Player, error)
 	return players, nil
 }
 
+// Search returns the players matching a filter and the total number of matches (ignoring paging)
+func (r *PlayerRepository) Search(ctx context.Context, filter PlayerFilter) ([]models.Player, int, error) {
+	conditions, args := filter.where(nil)
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM players p `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	page, args := filter.page(args)
+	query := `
+		SELECT p.id, p.first_name, p.last_name, p.status, p.country_code, p.external_id, p.positions, p.attributes
+		FROM players p
+		` + where + `
+		` + filter.orderBy() + page
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	players := []models.Player{}
+	for rows.Next() {
+		var player models.Player
+		err := rows.Scan(
+			&player.ID,
+			&player.FirstName,
+			&player.LastName,
+			&player.Status,
+			&player.CountryCode,
+			&player.ExternalID,
+			&player.Positions,
+			&player.Attributes,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		players = append(players, player)
+	}
+
+	return players, total, rows.Err()
+}
+
 // Create new record in players table
 func (r *PlayerRepository) Create(ctx context.Context, player *models.Player) error {
 	query := `
-		INSERT INTO players (first_name, last_name, status, country_code)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO players (first_name, last_name, status, country_code, external_id, positions, attributes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
 	err := r.pool.QueryRow(ctx, query,
@@ -83,6 +140,9 @@ func (r *PlayerRepository) Create(ctx context.Context, player *models.Player) er
 		player.LastName,
 		player.Status,
 		player.CountryCode,
+		player.ExternalID,
+		positionsOrEmpty(player.Positions),
+		player.Attributes,
 	).Scan(&player.ID)
 
 	return err
@@ -91,8 +151,9 @@ func (r *PlayerRepository) Create(ctx context.Context, player *models.Player) er
 // Update record in players table
 func (r *PlayerRepository) Update(ctx context.Context, player *models.Player) error {
 	query := `
-		UPDATE players SET first_name=$1, last_name=$2, status=$3, country_code=$4
-		WHERE id=$5
+		UPDATE players SET first_name=$1, last_name=$2, status=$3, country_code=$4, external_id=$5,
+		       positions=$6, attributes=$7
+		WHERE id=$8
 	`
 
 	commandTag, err := r.pool.Exec(ctx, query,
@@ -100,6 +161,9 @@ func (r *PlayerRepository) Update(ctx context.Context, player *models.Player) er
 		player.LastName,
 		player.Status,
 		player.CountryCode,
+		player.ExternalID,
+		positionsOrEmpty(player.Positions),
+		player.Attributes,
 		player.ID,
 	)
 
@@ -133,3 +197,164 @@ func (r *PlayerRepository) Delete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+// Player upsert outcomes
+const (
+	UpsertCreated   = "created"
+	UpsertUpdated   = "updated"
+	UpsertUnchanged = "unchanged"
+)
+
+// Upsert creates a player or updates the existing one within a transaction
+// A player matches on external ID when one is given, otherwise on name + country
+// (case-insensitive), as long as the match doesn't already carry a different external ID.
+// Sets player.ID and returns whether the player was created, updated or unchanged.
+func (r *PlayerRepository) Upsert(ctx context.Context, tx pgx.Tx, player *models.Player) (string, error) {
+	query := `
+		SELECT id, first_name, last_name, status, country_code, external_id, positions, attributes
+		FROM players
+		WHERE ($1::text IS NOT NULL AND external_id = $1::text)
+			OR (LOWER(first_name) = LOWER($2) AND LOWER(last_name) = LOWER($3) AND country_code = $4
+				AND (external_id IS NULL OR $1::text IS NULL OR external_id = $1::text))
+		ORDER BY (external_id IS NOT DISTINCT FROM $1::text) DESC, id
+		LIMIT 1
+		FOR UPDATE
+	`
+
+	var existing models.Player
+	err := tx.QueryRow(ctx, query,
+		player.ExternalID,
+		player.FirstName,
+		player.LastName,
+		player.CountryCode,
+	).Scan(
+		&existing.ID,
+		&existing.FirstName,
+		&existing.LastName,
+		&existing.Status,
+		&existing.CountryCode,
+		&existing.ExternalID,
+		&existing.Positions,
+		&existing.Attributes,
+	)
+	if err == pgx.ErrNoRows {
+		err = tx.QueryRow(ctx, `
+			INSERT INTO players (first_name, last_name, status, country_code, external_id, positions, attributes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`,
+			player.FirstName,
+			player.LastName,
+			player.Status,
+			player.CountryCode,
+			player.ExternalID,
+			positionsOrEmpty(player.Positions),
+			player.Attributes,
+		).Scan(&player.ID)
+		if err != nil {
+			return "", err
+		}
+		return UpsertCreated, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	player.ID = existing.ID
+	// Keep what we already know about anything the import doesn't specify
+	if player.ExternalID == nil {
+		player.ExternalID = existing.ExternalID
+	}
+	if player.Positions == nil {
+		player.Positions = existing.Positions
+	}
+	if player.Attributes == nil {
+		player.Attributes = existing.Attributes
+	}
+
+	if existing.FirstName == player.FirstName &&
+		existing.LastName == player.LastName &&
+		existing.Status == player.Status &&
+		existing.CountryCode == player.CountryCode &&
+		equalStringPtr(existing.ExternalID, player.ExternalID) &&
+		slices.Equal(existing.Positions, player.Positions) &&
+		reflect.DeepEqual(existing.Attributes, player.Attributes) {
+		return UpsertUnchanged, nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE players SET first_name=$1, last_name=$2, status=$3, country_code=$4, external_id=$5,
+		       positions=$6, attributes=$7
+		WHERE id=$8
+	`,
+		player.FirstName,
+		player.LastName,
+		player.Status,
+		player.CountryCode,
+		player.ExternalID,
+		positionsOrEmpty(player.Positions),
+		player.Attributes,
+		player.ID,
+	)
+	if err != nil {
+		return "", err
+	}
+	return UpsertUpdated, nil
+}
+
+// ExistingIDs returns which of the given player IDs exist, within a transaction
+func (r *PlayerRepository) ExistingIDs(ctx context.Context, tx pgx.Tx, ids []int) (map[int]bool, error) {
+	rows, err := tx.Query(ctx, `SELECT id FROM players WHERE id = ANY($1::int[])`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		existing[id] = true
+	}
+
+	return existing, rows.Err()
+}
+
+// IDsByExternalID maps external IDs to player IDs, within a transaction
+// External IDs that match no player are left out
+func (r *PlayerRepository) IDsByExternalID(ctx context.Context, tx pgx.Tx, externalIDs []string) (map[string]int, error) {
+	rows, err := tx.Query(ctx, `SELECT external_id, id FROM players WHERE external_id = ANY($1::text[])`, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int)
+	for rows.Next() {
+		var externalID string
+		var id int
+		if err := rows.Scan(&externalID, &id); err != nil {
+			return nil, err
+		}
+		ids[externalID] = id
+	}
+
+	return ids, rows.Err()
+}
+
+// positionsOrEmpty stores a missing position list as an empty array rather than NULL
+func positionsOrEmpty(positions []string) []string {
+	if positions == nil {
+		return []string{}
+	}
+	return positions
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
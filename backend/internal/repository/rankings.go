@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+type RankingRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRankingRepository(pool *pgxpool.Pool) *RankingRepository {
+	return &RankingRepository{pool: pool}
+}
+
+// ListSources returns every ranking source with its player count
+func (r *RankingRepository) ListSources(ctx context.Context) ([]models.RankingSource, error) {
+	query := `
+		SELECT source, COUNT(*), MAX(updated_at)
+		FROM rankings
+		GROUP BY source
+		ORDER BY source
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sources := []models.RankingSource{}
+	for rows.Next() {
+		var source models.RankingSource
+		if err := rows.Scan(&source.Name, &source.Players, &source.UpdatedAt); err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	return sources, rows.Err()
+}
+
+// GetBySource returns a source's rankings, best first
+func (r *RankingRepository) GetBySource(ctx context.Context, source string) ([]models.Ranking, error) {
+	query := `
+		SELECT source, player_id, rank, tier, value
+		FROM rankings
+		WHERE source = $1
+		ORDER BY rank, player_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rankings := []models.Ranking{}
+	for rows.Next() {
+		var ranking models.Ranking
+		if err := rows.Scan(
+			&ranking.Source,
+			&ranking.PlayerID,
+			&ranking.Rank,
+			&ranking.Tier,
+			&ranking.Value,
+		); err != nil {
+			return nil, err
+		}
+		rankings = append(rankings, ranking)
+	}
+
+	return rankings, rows.Err()
+}
+
+// ReplaceTx replaces every ranking in a source within a transaction
+// Returns the number of rankings stored
+func (r *RankingRepository) ReplaceTx(ctx context.Context, tx pgx.Tx, source string, rankings []models.Ranking) (int, error) {
+	if _, err := tx.Exec(ctx, `DELETE FROM rankings WHERE source = $1`, source); err != nil {
+		return 0, err
+	}
+	if len(rankings) == 0 {
+		return 0, nil
+	}
+
+	playerIDs := make([]int, len(rankings))
+	ranks := make([]int, len(rankings))
+	tiers := make([]*int, len(rankings))
+	values := make([]*float64, len(rankings))
+	for i, ranking := range rankings {
+		playerIDs[i] = ranking.PlayerID
+		ranks[i] = ranking.Rank
+		tiers[i] = ranking.Tier
+		values[i] = ranking.Value
+	}
+
+	query := `
+		INSERT INTO rankings (source, player_id, rank, tier, value)
+		SELECT $1, player_id, rank, tier, value
+		FROM UNNEST($2::int[], $3::int[], $4::int[], $5::float8[]) AS t(player_id, rank, tier, value)
+	`
+	commandTag, err := tx.Exec(ctx, query, source, playerIDs, ranks, tiers, values)
+	if err != nil {
+		return 0, err
+	}
+	return int(commandTag.RowsAffected()), nil
+}
+
+// DeleteSource removes every ranking in a source
+func (r *RankingRepository) DeleteSource(ctx context.Context, source string) error {
+	commandTag, err := r.pool.Exec(ctx, `DELETE FROM rankings WHERE source = $1`, source)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// RecomputeADP rebuilds the adp ranking from every completed event's draft results
+// (implements draft.RankingUpdater interface). Players are ranked by their average pick
+// number; keeper picks are left out since they were not chosen in the draft.
+func (r *RankingRepository) RecomputeADP(ctx context.Context) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM rankings WHERE source = $1`, models.RankingSourceADP); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO rankings (source, player_id, rank, value)
+		SELECT $1, dr.player_id,
+		       ROW_NUMBER() OVER (ORDER BY AVG(dr.pick_number), COUNT(*) DESC, dr.player_id),
+		       ROUND(AVG(dr.pick_number), 2)::float8
+		FROM draft_results dr
+		INNER JOIN events e ON e.id = dr.event_id
+		WHERE e.status = $2 AND dr.provenance <> $3
+		GROUP BY dr.player_id
+	`
+	if _, err := tx.Exec(ctx, query, models.RankingSourceADP, models.EventStatusCompleted, models.PickProvenanceKeeper); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+type TemplateRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTemplateRepository(pool *pgxpool.Pool) *TemplateRepository {
+	return &TemplateRepository{pool: pool}
+}
+
+// ErrTemplateNameTaken is returned when saving a template under a name already in use
+var ErrTemplateNameTaken = errors.New("a template with this name already exists")
+
+// GetByID retrieves a template with its player pool
+func (r *TemplateRepository) GetByID(ctx context.Context, id int) (*models.EventTemplate, error) {
+	templates, err := r.list(ctx, "WHERE t.id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return &templates[0], nil
+}
+
+// GetAll retrieves every template with its player pool
+func (r *TemplateRepository) GetAll(ctx context.Context) ([]models.EventTemplate, error) {
+	return r.list(ctx, "ORDER BY t.name")
+}
+
+// list returns the templates selected by a WHERE/ORDER BY clause
+func (r *TemplateRepository) list(ctx context.Context, clause string, args ...any) ([]models.EventTemplate, error) {
+	query := `
+		SELECT t.id, t.name, t.max_picks_per_team, t.max_teams_per_player, t.max_spectators, t.max_teams,
+		       t.stipulations, t.roster_slots, t.ranking_source, t.late_registration, t.created_at,
+		       COALESCE((SELECT array_agg(player_id ORDER BY player_id) FROM event_template_players WHERE template_id = t.id), '{}')
+		FROM event_templates t
+	` + clause
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.EventTemplate{}
+	for rows.Next() {
+		var template models.EventTemplate
+		err := rows.Scan(
+			&template.ID,
+			&template.Name,
+			&template.MaxPicksPerTeam,
+			&template.MaxTeamsPerPlayer,
+			&template.MaxSpectators,
+			&template.MaxTeams,
+			&template.Stipulations,
+			&template.RosterSlots,
+			&template.RankingSource,
+			&template.LateRegistration,
+			&template.CreatedAt,
+			&template.PlayerIDs,
+		)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, rows.Err()
+}
+
+// CreateFromEvent saves an event's settings, stipulations and player pool as a new template
+// Returns pgx.ErrNoRows if the event does not exist and ErrTemplateNameTaken if the name is in use
+func (r *TemplateRepository) CreateFromEvent(ctx context.Context, eventID int, name string) (*models.EventTemplate, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO event_templates (name, max_picks_per_team, max_teams_per_player, max_spectators, max_teams,
+		                             stipulations, roster_slots, ranking_source, late_registration)
+		SELECT $2, max_picks_per_team, max_teams_per_player, max_spectators, max_teams,
+		       COALESCE(stipulations, '{}'::jsonb), roster_slots, ranking_source, late_registration
+		FROM events WHERE id = $1
+		RETURNING id
+	`, eventID, name).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return nil, ErrTemplateNameTaken
+		}
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO event_template_players (template_id, player_id)
+		SELECT $2, player_id FROM event_players WHERE event_id = $1
+	`, eventID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Delete removes a template; events created from it are unaffected
+func (r *TemplateRepository) Delete(ctx context.Context, id int) error {
+	commandTag, err := r.pool.Exec(ctx, `DELETE FROM event_templates WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// CreateEvent creates an event and fills its player pool from a template, in one transaction
+// The event should come from the template's NewEvent.
+func (r *TemplateRepository) CreateEvent(ctx context.Context, template *models.EventTemplate, event *models.Event) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query, args := eventInsert(event)
+	if err := tx.QueryRow(ctx, query, args...).Scan(&event.ID, &event.CreatedAt); err != nil {
+		return err
+	}
+
+	// Players deleted since the template was saved have already dropped out of it
+	_, err = tx.Exec(ctx, `
+		INSERT INTO event_players (event_id, player_id)
+		SELECT $2, player_id FROM event_template_players WHERE template_id = $1
+	`, template.ID, event.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
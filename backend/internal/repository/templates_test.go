@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/testdb"
+)
+
+func TestTemplates(t *testing.T) {
+	pool := testdb.Open(t)
+	repo := NewTemplateRepository(pool)
+	ctx := context.Background()
+
+	source := testdb.Event(t, pool, 6)
+	players := testdb.Players(t, pool, source, 3)
+	_, err := pool.Exec(ctx, `UPDATE events SET max_picks_per_team = 5, late_registration = true WHERE id = $1`, source)
+	if err != nil {
+		t.Fatalf("set up source event: %v", err)
+	}
+
+	template, err := repo.CreateFromEvent(ctx, source, t.Name())
+	if err != nil {
+		t.Fatalf("CreateFromEvent: %v", err)
+	}
+	t.Cleanup(func() { repo.Delete(context.Background(), template.ID) })
+	if template.MaxTeams != 6 || template.MaxPicksPerTeam != 5 || !template.LateRegistration || fmt.Sprint(template.PlayerIDs) != fmt.Sprint(players) {
+		t.Fatalf("template does not match its event: %+v", template)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			eventID      int
+			templateName string
+			want         error
+		}{
+			{"name taken", source, template.Name, ErrTemplateNameTaken},
+			{"unknown event", -1, t.Name(), pgx.ErrNoRows},
+		}
+		for _, tt := range tests {
+			if _, err := repo.CreateFromEvent(ctx, tt.eventID, tt.templateName); !errors.Is(err, tt.want) {
+				t.Fatalf("%s: CreateFromEvent = %v, want %v", tt.name, err, tt.want)
+			}
+		}
+	})
+
+	t.Run("create event", func(t *testing.T) {
+		// A player deleted after the template was saved drops out of events created from it
+		if _, err := pool.Exec(ctx, `DELETE FROM players WHERE id = $1`, players[2]); err != nil {
+			t.Fatalf("delete player: %v", err)
+		}
+		template, err := repo.GetByID(ctx, template.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+
+		event := template.NewEvent(t.Name())
+		if err := repo.CreateEvent(ctx, template, &event); err != nil {
+			t.Fatalf("CreateEvent: %v", err)
+		}
+		t.Cleanup(func() { pool.Exec(context.Background(), `DELETE FROM events WHERE id = $1`, event.ID) })
+
+		created, err := NewEventRepository(pool).GetByID(ctx, event.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if created.MaxTeams != 6 || created.MaxPicksPerTeam != 5 || !created.LateRegistration || created.Status != models.EventStatusNotStarted {
+			t.Fatalf("event does not match its template: %+v", created)
+		}
+		poolIDs, err := NewEventPlayerRepository(pool).GetPlayerIDsByEvent(ctx, event.ID)
+		if err != nil {
+			t.Fatalf("read pool: %v", err)
+		}
+		slices.Sort(poolIDs)
+		if fmt.Sprint(poolIDs) != fmt.Sprint(players[:2]) {
+			t.Fatalf("pool = %v, want %v", poolIDs, players[:2])
+		}
+	})
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sblackwood23/fantasy-draft-app/internal/models"
 )
@@ -18,7 +21,7 @@ func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
 
 func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
 	query := `
-		SELECT id, event_id, username, created_at
+		SELECT id, event_id, username, league_member_id, display_name, color, email, avatar_updated_at, pin_hash, rejoin_token_hash, unclaimed, created_at
 		FROM users
 		WHERE id = $1
 	`
@@ -28,12 +31,21 @@ func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, err
 		&user.ID,
 		&user.EventID,
 		&user.Username,
+		&user.LeagueMemberID,
+		&user.DisplayName,
+		&user.Color,
+		&user.Email,
+		&user.AvatarUpdatedAt,
+		&user.PinHash,
+		&user.RejoinTokenHash,
+		&user.Unclaimed,
 		&user.CreatedAt,
 	)
 
 	if err != nil {
 		return nil, err
 	}
+	user.SetComputedFields()
 
 	return &user, nil
 }
@@ -41,7 +53,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, err
 // Retrieves all users
 func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
 	query := `
-		SELECT id, event_id, username, created_at
+		SELECT id, event_id, username, league_member_id, display_name, color, email, avatar_updated_at, pin_hash, rejoin_token_hash, unclaimed, created_at
 		FROM users
 	`
 
@@ -58,11 +70,62 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
 			&user.ID,
 			&user.EventID,
 			&user.Username,
+			&user.LeagueMemberID,
+			&user.DisplayName,
+			&user.Color,
+			&user.Email,
+			&user.AvatarUpdatedAt,
+			&user.PinHash,
+			&user.RejoinTokenHash,
+			&user.Unclaimed,
 			&user.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		user.SetComputedFields()
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// GetByEvent returns the teams (users) in an event, in join order
+func (r *UserRepository) GetByEvent(ctx context.Context, eventID int) ([]models.User, error) {
+	query := `
+		SELECT id, event_id, username, league_member_id, display_name, color, email, avatar_updated_at, pin_hash, rejoin_token_hash, unclaimed, created_at
+		FROM users
+		WHERE event_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.EventID,
+			&user.Username,
+			&user.LeagueMemberID,
+			&user.DisplayName,
+			&user.Color,
+			&user.Email,
+			&user.AvatarUpdatedAt,
+			&user.PinHash,
+			&user.RejoinTokenHash,
+			&user.Unclaimed,
+			&user.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		user.SetComputedFields()
 		users = append(users, user)
 	}
 
@@ -72,14 +135,17 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
 // Create new record in users table
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
-		INSERT INTO users (event_id, username)
-		VALUES ($1, $2)
+		INSERT INTO users (event_id, username, pin_hash, rejoin_token_hash)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id, created_at
 	`
 	err := r.pool.QueryRow(ctx, query,
 		user.EventID,
 		user.Username,
+		user.PinHash,
+		user.RejoinTokenHash,
 	).Scan(&user.ID, &user.CreatedAt)
+	user.SetComputedFields()
 
 	return err
 }
@@ -107,14 +173,14 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	return nil
 }
 
-// Delete record from users table
-func (r *UserRepository) Delete(ctx context.Context, id int) error {
+// DeleteTx deletes a record from the users table within a transaction
+func (r *UserRepository) DeleteTx(ctx context.Context, tx pgx.Tx, id int) error {
 	query := `
 		DELETE FROM users
 		WHERE id=$1
 	`
 
-	commandTag, err := r.pool.Exec(ctx, query, id)
+	commandTag, err := tx.Exec(ctx, query, id)
 
 	if err != nil {
 		return err
@@ -130,7 +196,7 @@ func (r *UserRepository) Delete(ctx context.Context, id int) error {
 // GetByEventAndUsername finds a user by event ID and username
 func (r *UserRepository) GetByEventAndUsername(ctx context.Context, eventID int, username string) (*models.User, error) {
 	query := `
-		SELECT id, event_id, username, created_at
+		SELECT id, event_id, username, league_member_id, display_name, color, email, avatar_updated_at, pin_hash, rejoin_token_hash, unclaimed, created_at
 		FROM users
 		WHERE event_id = $1 AND username = $2
 	`
@@ -140,25 +206,197 @@ func (r *UserRepository) GetByEventAndUsername(ctx context.Context, eventID int,
 		&user.ID,
 		&user.EventID,
 		&user.Username,
+		&user.LeagueMemberID,
+		&user.DisplayName,
+		&user.Color,
+		&user.Email,
+		&user.AvatarUpdatedAt,
+		&user.PinHash,
+		&user.RejoinTokenHash,
+		&user.Unclaimed,
 		&user.CreatedAt,
 	)
 
 	if err != nil {
 		return nil, err
 	}
+	user.SetComputedFields()
 
 	return &user, nil
 }
 
-// CountByEvent returns the number of users registered for an event
-func (r *UserRepository) CountByEvent(ctx context.Context, eventID int) (int, error) {
-	query := `SELECT COUNT(*) FROM users WHERE event_id = $1`
+// Errors returned by Register when a team cannot join an event
+var (
+	ErrEventFull     = errors.New("draft room is full")
+	ErrTeamNameTaken = errors.New("team name is already taken")
+)
+
+// RegistrationClosedError is returned by Register when the event is not taking new teams
+type RegistrationClosedError struct {
+	Reason string
+}
+
+func (e *RegistrationClosedError) Error() string {
+	return e.Reason
+}
+
+// Register adds a new team to an event in a single transaction, checking the event's
+// registration window and capacity. The event row is locked so concurrent joiners are
+// serialized and cannot overfill it. The first team registered becomes the commissioner.
+// Returns pgx.ErrNoRows if the event does not exist.
+func (r *UserRepository) Register(ctx context.Context, user *models.User, now time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var event models.Event
+	err = tx.QueryRow(ctx, `
+		SELECT status, max_teams, registration_opens_at, registration_closes_at, late_registration
+		FROM events WHERE id = $1 FOR UPDATE
+	`, user.EventID).Scan(
+		&event.Status,
+		&event.MaxTeams,
+		&event.RegistrationOpensAt,
+		&event.RegistrationClosesAt,
+		&event.LateRegistration,
+	)
+	if err != nil {
+		return err
+	}
+	if reason := event.RegistrationClosedReason(now); reason != "" {
+		return &RegistrationClosedError{Reason: reason}
+	}
 
 	var count int
-	err := r.pool.QueryRow(ctx, query, eventID).Scan(&count)
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE event_id = $1`, user.EventID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= event.MaxTeams {
+		return ErrEventFull
+	}
+
+	// A new team is never linked to a league member here, even one with the same name:
+	// anyone can pick a name, so linking is left to LeagueRepository.LinkTeam
+	err = tx.QueryRow(ctx, `
+		INSERT INTO users (event_id, username, pin_hash, rejoin_token_hash)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, league_member_id, created_at
+	`, user.EventID, user.Username, user.PinHash, user.RejoinTokenHash).Scan(&user.ID, &user.LeagueMemberID, &user.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrTeamNameTaken
+		}
+		return err
+	}
+	user.SetComputedFields()
+
+	_, err = tx.Exec(ctx, `UPDATE events SET commissioner_user_id = $2 WHERE id = $1 AND commissioner_user_id IS NULL`, user.EventID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetSecrets replaces a team's PIN hash and rejoin token hash (nil clears either)
+func (r *UserRepository) SetSecrets(ctx context.Context, userID int, pinHash, rejoinTokenHash *string) error {
+	query := `UPDATE users SET pin_hash = $2, rejoin_token_hash = $3 WHERE id = $1`
+
+	commandTag, err := r.pool.Exec(ctx, query, userID, pinHash, rejoinTokenHash)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// RotateRejoinToken replaces a team's rejoin token hash only if it is still oldHash, so each
+// rejoin link works once even when used from two devices at the same time
+// Returns false if the token was already used or replaced
+func (r *UserRepository) RotateRejoinToken(ctx context.Context, userID int, oldHash, newHash string) (bool, error) {
+	query := `UPDATE users SET rejoin_token_hash = $3 WHERE id = $1 AND rejoin_token_hash = $2`
+
+	commandTag, err := r.pool.Exec(ctx, query, userID, oldHash, newHash)
+	if err != nil {
+		return false, err
+	}
+
+	return commandTag.RowsAffected() == 1, nil
+}
+
+// ResetSecrets replaces the PIN hash of a team in an event, clears its rejoin link and claims it
+// if it was unclaimed. With a nil pinHash the team's next join reclaims it by name (implements draft.TeamStore)
+func (r *UserRepository) ResetSecrets(ctx context.Context, eventID, userID int, pinHash *string) error {
+	query := `UPDATE users SET pin_hash = $3, rejoin_token_hash = NULL, unclaimed = false WHERE id = $1 AND event_id = $2`
+
+	commandTag, err := r.pool.Exec(ctx, query, userID, eventID, pinHash)
 	if err != nil {
-		return 0, err
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateProfileTx saves a team's display name, color and contact email within a transaction
+func (r *UserRepository) UpdateProfileTx(ctx context.Context, tx pgx.Tx, user *models.User) error {
+	query := `UPDATE users SET display_name = $2, color = $3, email = $4 WHERE id = $1`
+
+	commandTag, err := tx.Exec(ctx, query, user.ID, user.DisplayName, user.Color, user.Email)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetAvatarTx stores a team's avatar image within a transaction, or removes it when data is nil
+// Returns the new upload time (nil after a removal)
+func (r *UserRepository) SetAvatarTx(ctx context.Context, tx pgx.Tx, userID int, contentType string, data []byte) (*time.Time, error) {
+	query := `
+		UPDATE users SET avatar = $2, avatar_content_type = $3,
+		       avatar_updated_at = CASE WHEN $2::bytea IS NULL THEN NULL ELSE NOW() END
+		WHERE id = $1
+		RETURNING avatar_updated_at
+	`
+
+	var contentTypeArg *string
+	if data != nil {
+		contentTypeArg = &contentType
+	}
+
+	var updatedAt *time.Time
+	if err := tx.QueryRow(ctx, query, userID, data, contentTypeArg).Scan(&updatedAt); err != nil {
+		return nil, err
+	}
+
+	return updatedAt, nil
+}
+
+// GetAvatar returns a team's avatar image and content type
+// Returns pgx.ErrNoRows if the team does not exist or has no avatar
+func (r *UserRepository) GetAvatar(ctx context.Context, userID int) ([]byte, string, error) {
+	query := `SELECT avatar, avatar_content_type FROM users WHERE id = $1 AND avatar IS NOT NULL`
+
+	var data []byte
+	var contentType string
+	if err := r.pool.QueryRow(ctx, query, userID).Scan(&data, &contentType); err != nil {
+		return nil, "", err
 	}
 
-	return count, nil
+	return data, contentType, nil
 }
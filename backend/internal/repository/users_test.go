@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+	"github.com/sblackwood23/fantasy-draft-app/internal/testdb"
+)
+
+func TestRegisterConcurrentJoinsDoNotOverfill(t *testing.T) {
+	pool := testdb.Open(t)
+	repo := NewUserRepository(pool)
+	const maxTeams, joiners = 4, 20
+	eventID := testdb.Event(t, pool, maxTeams)
+
+	var wg sync.WaitGroup
+	errs := make([]error, joiners)
+	users := make([]*models.User, joiners)
+	for i := range joiners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			users[i] = &models.User{EventID: eventID, Username: fmt.Sprintf("Team %d", i+1)}
+			errs[i] = repo.Register(context.Background(), users[i], time.Now())
+		}()
+	}
+	wg.Wait()
+
+	var registered []*models.User
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			registered = append(registered, users[i])
+		case !errors.Is(err, ErrEventFull):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(registered) != maxTeams {
+		t.Fatalf("expected %d teams to register, got %d", maxTeams, len(registered))
+	}
+
+	teams, err := repo.GetByEvent(context.Background(), eventID)
+	if err != nil {
+		t.Fatalf("GetByEvent: %v", err)
+	}
+	if len(teams) != maxTeams {
+		t.Fatalf("expected %d teams in the event, got %d", maxTeams, len(teams))
+	}
+
+	// The first team to register, and only it, became the commissioner
+	var commissionerID *int
+	err = pool.QueryRow(context.Background(), `SELECT commissioner_user_id FROM events WHERE id = $1`, eventID).Scan(&commissionerID)
+	if err != nil {
+		t.Fatalf("read commissioner: %v", err)
+	}
+	if commissionerID == nil || *commissionerID != teams[0].ID {
+		t.Fatalf("expected team %d to be the commissioner, got %v", teams[0].ID, commissionerID)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	pool := testdb.Open(t)
+	repo := NewUserRepository(pool)
+	ctx := context.Background()
+	now := time.Now()
+
+	full := testdb.Event(t, pool, 1)
+	testdb.Team(t, pool, full, "Taken")
+	open := testdb.Event(t, pool, 4)
+	testdb.Team(t, pool, open, "Taken")
+	notOpen := testdb.Event(t, pool, 4)
+	closed := testdb.Event(t, pool, 4)
+	started := testdb.Event(t, pool, 4)
+	late := testdb.Event(t, pool, 4)
+	for _, update := range []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE events SET registration_opens_at = $2 WHERE id = $1`, []any{notOpen, now.Add(time.Hour)}},
+		{`UPDATE events SET registration_closes_at = $2 WHERE id = $1`, []any{closed, now.Add(-time.Hour)}},
+		{`UPDATE events SET status = 'in_progress' WHERE id = $1`, []any{started}},
+		{`UPDATE events SET status = 'in_progress', late_registration = true WHERE id = $1`, []any{late}},
+	} {
+		if _, err := pool.Exec(ctx, update.query, update.args...); err != nil {
+			t.Fatalf("set up event: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		eventID    int
+		username   string
+		want       error  // Matched with errors.Is
+		wantReason string // For a RegistrationClosedError
+	}{
+		{name: "open event", eventID: open, username: "New"},
+		{name: "late registration", eventID: late, username: "New"},
+		{name: "full event", eventID: full, username: "New", want: ErrEventFull},
+		{name: "name taken", eventID: open, username: "Taken", want: ErrTeamNameTaken},
+		{name: "unknown event", eventID: -1, username: "New", want: pgx.ErrNoRows},
+		{name: "before the window", eventID: notOpen, username: "New", wantReason: "registration has not opened yet"},
+		{name: "after the window", eventID: closed, username: "New", wantReason: "registration has closed"},
+		{name: "draft started", eventID: started, username: "New", wantReason: "registration closed when the draft started"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{EventID: tt.eventID, Username: tt.username}
+			err := repo.Register(ctx, user, now)
+
+			var closedErr *RegistrationClosedError
+			switch {
+			case tt.wantReason != "":
+				if !errors.As(err, &closedErr) || closedErr.Reason != tt.wantReason {
+					t.Fatalf("Register = %v, want registration closed: %s", err, tt.wantReason)
+				}
+			case !errors.Is(err, tt.want):
+				t.Fatalf("Register = %v, want %v", err, tt.want)
+			case err == nil && user.ID == 0:
+				t.Fatal("registered team has no ID")
+			}
+		})
+	}
+}
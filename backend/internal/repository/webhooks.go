@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+type WebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{pool: pool}
+}
+
+// webhookPayload is the JSON body posted to webhook endpoints
+type webhookPayload struct {
+	Type       string          `json:"type"`
+	EventID    int             `json:"eventID"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"` // The message as sent to the draft room
+}
+
+// GetByEvent returns an event's webhooks, oldest first
+func (r *WebhookRepository) GetByEvent(ctx context.Context, eventID int) ([]models.Webhook, error) {
+	query := `
+		SELECT id, event_id, url, secret, message_types, active, created_at
+		FROM webhooks
+		WHERE event_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		var hook models.Webhook
+		if err := rows.Scan(&hook.ID, &hook.EventID, &hook.URL, &hook.Secret, &hook.MessageTypes, &hook.Active, &hook.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, hook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// GetByID retrieves one of an event's webhooks
+func (r *WebhookRepository) GetByID(ctx context.Context, eventID, id int) (*models.Webhook, error) {
+	query := `
+		SELECT id, event_id, url, secret, message_types, active, created_at
+		FROM webhooks
+		WHERE id = $1 AND event_id = $2
+	`
+
+	var hook models.Webhook
+	err := r.pool.QueryRow(ctx, query, id, eventID).Scan(
+		&hook.ID, &hook.EventID, &hook.URL, &hook.Secret, &hook.MessageTypes, &hook.Active, &hook.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hook, nil
+}
+
+// Create inserts a webhook, setting its ID and creation time
+func (r *WebhookRepository) Create(ctx context.Context, hook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (event_id, url, secret, message_types, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	return r.pool.QueryRow(ctx, query, hook.EventID, hook.URL, hook.Secret, hook.MessageTypes, hook.Active).Scan(&hook.ID, &hook.CreatedAt)
+}
+
+// Update changes a webhook's URL, message types and whether it is active
+func (r *WebhookRepository) Update(ctx context.Context, hook *models.Webhook) error {
+	query := `
+		UPDATE webhooks SET url = $3, message_types = $4, active = $5
+		WHERE id = $1 AND event_id = $2
+		RETURNING secret, created_at
+	`
+	return r.pool.QueryRow(ctx, query, hook.ID, hook.EventID, hook.URL, hook.MessageTypes, hook.Active).Scan(&hook.Secret, &hook.CreatedAt)
+}
+
+// Delete removes a webhook and its queued deliveries
+func (r *WebhookRepository) Delete(ctx context.Context, eventID, id int) error {
+	commandTag, err := r.pool.Exec(ctx, `DELETE FROM webhooks WHERE id = $1 AND event_id = $2`, id, eventID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetDeliveries returns a webhook's most recent deliveries, newest first
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID, limit int) ([]models.WebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_id, message_type, payload, status, attempts, next_attempt_at,
+		       last_status_code, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.MessageType,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// Publish queues a draft message for every active webhook of the event subscribed to its type
+func (r *WebhookRepository) Publish(ctx context.Context, eventID int, msgType string, data []byte) error {
+	payload, err := json.Marshal(webhookPayload{Type: msgType, EventID: eventID, OccurredAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, message_type, payload)
+		SELECT id, $2, $3 FROM webhooks
+		WHERE event_id = $1 AND active AND (cardinality(message_types) = 0 OR $2 = ANY(message_types))
+	`
+	_, err = r.pool.Exec(ctx, query, eventID, msgType, payload)
+	return err
+}
+
+// Enqueue queues a message for one webhook regardless of its message types, e.g. a test ping
+func (r *WebhookRepository) Enqueue(ctx context.Context, hook *models.Webhook, msgType string, data []byte) (*models.WebhookDelivery, error) {
+	payload, err := json.Marshal(webhookPayload{Type: msgType, EventID: hook.EventID, OccurredAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, message_type, payload)
+		VALUES ($1, $2, $3)
+		RETURNING id, payload, status, attempts, next_attempt_at, created_at
+	`
+
+	delivery := models.WebhookDelivery{WebhookID: hook.ID, MessageType: msgType}
+	err = r.pool.QueryRow(ctx, query, hook.ID, msgType, payload).Scan(
+		&delivery.ID, &delivery.Payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// ClaimDue claims up to limit pending deliveries that are due, oldest first, counting an attempt
+// for each. A claimed delivery is not due again until the lease expires, so a dispatcher that
+// dies mid-delivery only delays it. Deliveries of inactive webhooks wait until reactivated.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM webhooks w
+		WHERE w.id = d.webhook_id AND d.id IN (
+			SELECT pd.id FROM webhook_deliveries pd
+			JOIN webhooks pw ON pw.id = pd.webhook_id
+			WHERE pd.status = 'pending' AND pd.next_attempt_at <= NOW() AND pw.active
+			ORDER BY pd.next_attempt_at, pd.id
+			LIMIT $1
+			FOR UPDATE OF pd SKIP LOCKED
+		)
+		RETURNING d.id, d.webhook_id, d.message_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.created_at, w.url, w.secret
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.MessageType,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.CreatedAt,
+			&delivery.URL,
+			&delivery.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// MarkDelivered records a successful delivery
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', last_status_code = $2, last_error = NULL, delivered_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, query, id, statusCode)
+	return err
+}
+
+// MarkFailed records a failed attempt, to be retried at retryAt
+// A nil retryAt gives up on the delivery.
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id int64, statusCode *int, errMsg string, retryAt *time.Time) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = CASE WHEN $4::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+		    next_attempt_at = COALESCE($4, next_attempt_at), last_status_code = $2, last_error = $3
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, query, id, statusCode, errMsg, retryAt)
+	return err
+}
@@ -0,0 +1,87 @@
+// Package testdb connects tests to a PostgreSQL database and creates the rows they need
+package testdb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Open connects to the database in TEST_DATABASE_URL, skipping the test when it is not set
+// The database must already be migrated, e.g. migrate -path migrations -database "$TEST_DATABASE_URL" up.
+// Tests share it, so each test creates its own rows (see Event and Players) and they are deleted when it ends.
+func Open(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set; skipping database test")
+	}
+
+	pool, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+	if err := pool.Ping(context.Background()); err != nil {
+		pool.Close()
+		t.Fatalf("ping test database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// Event inserts an event allowing maxTeams teams, with the column defaults for everything else
+// Deleting it when the test ends deletes its teams, pool and picks with it.
+func Event(t testing.TB, pool *pgxpool.Pool, maxTeams int) int {
+	t.Helper()
+	var id int
+	err := pool.QueryRow(context.Background(),
+		`INSERT INTO events (name, max_teams) VALUES ($1, $2) RETURNING id`, t.Name(), maxTeams).Scan(&id)
+	if err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM events WHERE id = $1`, id)
+	})
+	return id
+}
+
+// Team inserts a team into an event
+func Team(t testing.TB, pool *pgxpool.Pool, eventID int, username string) int {
+	t.Helper()
+	var id int
+	err := pool.QueryRow(context.Background(),
+		`INSERT INTO users (event_id, username) VALUES ($1, $2) RETURNING id`, eventID, username).Scan(&id)
+	if err != nil {
+		t.Fatalf("insert team: %v", err)
+	}
+	return id
+}
+
+// Players inserts n players, adding them to the event's pool unless eventID is 0
+// They are deleted when the test ends.
+func Players(t testing.TB, pool *pgxpool.Pool, eventID, n int) []int {
+	t.Helper()
+	ctx := context.Background()
+	ids := make([]int, n)
+	for i := range ids {
+		err := pool.QueryRow(ctx, `
+			INSERT INTO players (first_name, last_name, status, country_code)
+			VALUES ($1, $2, 'professional', 'USA') RETURNING id
+		`, "Player", fmt.Sprintf("%s %d", t.Name(), i+1)).Scan(&ids[i])
+		if err != nil {
+			t.Fatalf("insert player: %v", err)
+		}
+		if eventID != 0 {
+			if _, err := pool.Exec(ctx, `INSERT INTO event_players (event_id, player_id) VALUES ($1, $2)`, eventID, ids[i]); err != nil {
+				t.Fatalf("add player to pool: %v", err)
+			}
+		}
+	}
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM players WHERE id = ANY($1)`, ids)
+	})
+	return ids
+}
@@ -0,0 +1,232 @@
+// Package webhook delivers queued draft messages from the webhook outbox to subscriber URLs
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sblackwood23/fantasy-draft-app/internal/models"
+)
+
+// Delivery tuning
+const (
+	pollInterval   = time.Second
+	batchSize      = 20
+	requestTimeout = 10 * time.Second
+	leaseDuration  = time.Minute // Longer than requestTimeout, so a delivery is never sent twice at once
+	maxErrorLength = 500
+
+	// MaxAttempts is how many times a delivery is tried before it is marked failed
+	MaxAttempts = 8
+)
+
+// Headers sent with every delivery
+const (
+	HeaderDeliveryID  = "X-Webhook-ID"
+	HeaderType        = "X-Webhook-Type"
+	HeaderTimestamp   = "X-Webhook-Timestamp"
+	HeaderSignature   = "X-Webhook-Signature"
+	signaturePrefix   = "sha256="
+	deliveryUserAgent = "fantasy-draft-webhooks/1"
+)
+
+// Store is the webhook outbox
+type Store interface {
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, statusCode int) error
+	MarkFailed(ctx context.Context, id int64, statusCode *int, errMsg string, retryAt *time.Time) error
+}
+
+// Dispatcher polls the outbox and posts due deliveries, retrying failures with backoff
+// It runs apart from the draft room, so a slow endpoint only delays its own deliveries.
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+}
+
+// NewDispatcher creates a dispatcher for the outbox
+// Unless allowPrivateNetworks is set (e.g. to test against a local server), deliveries are
+// never sent to loopback, private or link-local addresses, so a webhook cannot reach
+// services on the server's own network.
+func NewDispatcher(store Store, allowPrivateNetworks bool) *Dispatcher {
+	dialer := &net.Dialer{Timeout: requestTimeout}
+	if !allowPrivateNetworks {
+		dialer.Control = blockPrivateAddresses
+	}
+	return &Dispatcher{
+		store: store,
+		client: &http.Client{
+			Timeout: requestTimeout,
+			// No proxy: the address checked must be the one actually dialled
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: requestTimeout,
+				MaxIdleConnsPerHost: 2,
+			},
+		},
+	}
+}
+
+// blockPrivateAddresses refuses connections to addresses inside the server's network
+// It runs after DNS resolution on every dial, including redirects, so a hostname
+// that resolves (or is re-pointed) to a private address is caught too.
+func blockPrivateAddresses(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("webhook: cannot parse address %q: %w", address, err)
+	}
+	if ip := addrPort.Addr().Unmap(); !publicAddress(ip) {
+		return fmt.Errorf("webhook: refusing to connect to non-public address %s", ip)
+	}
+	return nil
+}
+
+// publicAddress reports whether an IP address is outside loopback, private, link-local,
+// multicast and unspecified ranges
+func publicAddress(ip netip.Addr) bool {
+	return ip.IsValid() && !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+// Run delivers due messages until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Keep draining while there is a backlog
+			for d.dispatchBatch(ctx) == batchSize && ctx.Err() == nil {
+				continue
+			}
+		}
+	}
+}
+
+// dispatchBatch delivers one batch of due messages concurrently and returns how many it claimed
+func (d *Dispatcher) dispatchBatch(ctx context.Context) int {
+	deliveries, err := d.store.ClaimDue(ctx, batchSize, leaseDuration)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Failed to claim webhook deliveries", "err", err)
+		}
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+// deliver posts one delivery and records the outcome
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) {
+	statusCode, err := d.post(ctx, delivery)
+	if ctx.Err() != nil {
+		// Shutting down; the lease expires and the delivery is retried
+		return
+	}
+
+	if err == nil {
+		if err := d.store.MarkDelivered(ctx, delivery.ID, statusCode); err != nil {
+			slog.Error("Failed to mark webhook delivery delivered", "delivery_id", delivery.ID, "err", err)
+		}
+		return
+	}
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+	var retryAt *time.Time
+	if delivery.Attempts < MaxAttempts {
+		next := time.Now().Add(Backoff(delivery.Attempts))
+		retryAt = &next
+	} else {
+		slog.Warn("Webhook delivery failed; giving up", "delivery_id", delivery.ID, "webhook_id", delivery.WebhookID, "attempts", delivery.Attempts, "err", err)
+	}
+
+	msg := err.Error()
+	if len(msg) > maxErrorLength {
+		msg = msg[:maxErrorLength]
+	}
+	if err := d.store.MarkFailed(ctx, delivery.ID, code, msg, retryAt); err != nil {
+		slog.Error("Failed to record webhook delivery failure", "delivery_id", delivery.ID, "err", err)
+	}
+}
+
+// post sends a delivery, returning the response status (0 if there was none)
+// Any status outside 2xx is an error
+func (d *Dispatcher) post(ctx context.Context, delivery models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", deliveryUserAgent)
+	req.Header.Set(HeaderDeliveryID, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderType, delivery.MessageType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the X-Webhook-Signature header value for a payload sent at a Unix timestamp:
+// "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<payload>" keyed with the webhook secret
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether a signature header matches a payload, for receivers written in Go
+func Verify(secret string, timestamp int64, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
+
+// Backoff returns how long to wait after a failed attempt before the next one:
+// 10s after the first, doubling up to an hour
+func Backoff(attempt int) time.Duration {
+	delay := 10 * time.Second
+	for i := 1; i < attempt && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	return min(delay, time.Hour)
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestPublicAddress(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false, // Cloud metadata endpoint
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"0.0.0.0":          false,
+		"::ffff:127.0.0.1": false,
+	} {
+		if got := publicAddress(netip.MustParseAddr(addr).Unmap()); got != want {
+			t.Errorf("publicAddress(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestBlockPrivateAddresses(t *testing.T) {
+	if err := blockPrivateAddresses("tcp4", "127.0.0.1:8080", nil); err == nil {
+		t.Error("expected a loopback dial to be refused")
+	}
+	if err := blockPrivateAddresses("tcp6", "[::ffff:10.0.0.1]:443", nil); err == nil {
+		t.Error("expected an IPv4-mapped private dial to be refused")
+	}
+	if err := blockPrivateAddresses("tcp4", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("expected a public dial to be allowed, got %v", err)
+	}
+}
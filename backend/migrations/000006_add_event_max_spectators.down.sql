@@ -0,0 +1,2 @@
+-- Remove spectator capacity from events
+ALTER TABLE events DROP COLUMN max_spectators;
@@ -0,0 +1,2 @@
+-- Add spectator capacity to events (spectators watch without joining as a team)
+ALTER TABLE events ADD COLUMN max_spectators INTEGER NOT NULL DEFAULT 20 CHECK (max_spectators >= 0);
@@ -0,0 +1,2 @@
+-- Remove commissioner from events
+ALTER TABLE events DROP COLUMN commissioner_user_id;
@@ -0,0 +1,2 @@
+-- Add commissioner to events (the team with moderation powers in the draft room)
+ALTER TABLE events ADD COLUMN commissioner_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
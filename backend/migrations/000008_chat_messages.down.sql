@@ -0,0 +1,2 @@
+-- Drop chat_messages table
+DROP TABLE IF EXISTS chat_messages;
@@ -0,0 +1,12 @@
+-- Create chat_messages table for in-draft chat
+CREATE TABLE chat_messages (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+-- Create index for loading the most recent messages of an event
+CREATE INDEX idx_chat_messages_event_created ON chat_messages(event_id, created_at DESC);
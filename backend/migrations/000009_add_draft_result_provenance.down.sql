@@ -0,0 +1,2 @@
+-- Remove pick provenance from draft_results
+ALTER TABLE draft_results DROP COLUMN provenance;
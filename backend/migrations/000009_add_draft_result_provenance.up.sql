@@ -0,0 +1,3 @@
+-- Record how each pick was made (manual, auto-drafted, admin-made or keeper)
+ALTER TABLE draft_results ADD COLUMN provenance VARCHAR(20) NOT NULL DEFAULT 'manual'
+    CHECK (provenance IN ('manual', 'auto', 'admin', 'keeper'));
@@ -0,0 +1,4 @@
+-- Remove external ID from players
+DROP INDEX IF EXISTS idx_players_name_country;
+DROP INDEX IF EXISTS idx_players_external_id;
+ALTER TABLE players DROP COLUMN external_id;
@@ -0,0 +1,6 @@
+-- Add an optional external ID (e.g. a tour or federation ID) used to dedupe player imports
+ALTER TABLE players ADD COLUMN external_id VARCHAR(100);
+CREATE UNIQUE INDEX idx_players_external_id ON players(external_id) WHERE external_id IS NOT NULL;
+
+-- Index for deduping imports on name + country
+CREATE INDEX idx_players_name_country ON players(LOWER(first_name), LOWER(last_name), country_code);
@@ -0,0 +1,4 @@
+-- Remove player search indexes and helpers
+DROP INDEX IF EXISTS idx_players_last_first;
+DROP INDEX IF EXISTS idx_players_name_search;
+DROP FUNCTION IF EXISTS f_unaccent(text);
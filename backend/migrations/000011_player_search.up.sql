@@ -0,0 +1,15 @@
+-- Accent- and case-insensitive player name search
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() is only STABLE, so wrap it in an IMMUTABLE function that can be indexed
+CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$;
+
+-- Trigram index so substring searches on the full name can use an index
+CREATE INDEX idx_players_name_search ON players
+    USING gin (f_unaccent(LOWER(first_name || ' ' || last_name)) gin_trgm_ops);
+
+-- Index for sorting by name
+CREATE INDEX idx_players_last_first ON players(last_name, first_name, id);
@@ -0,0 +1,6 @@
+-- Remove roster slots and sport-agnostic player data
+ALTER TABLE events DROP COLUMN roster_slots;
+DROP INDEX IF EXISTS idx_players_attributes;
+DROP INDEX IF EXISTS idx_players_positions;
+ALTER TABLE players DROP COLUMN attributes;
+ALTER TABLE players DROP COLUMN positions;
@@ -0,0 +1,10 @@
+-- Sport-agnostic player data: positions (e.g. QB, RB/WR) and free-form attributes
+ALTER TABLE players ADD COLUMN positions TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE players ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+CREATE INDEX idx_players_positions ON players USING gin (positions);
+CREATE INDEX idx_players_attributes ON players USING gin (attributes jsonb_path_ops);
+
+-- Roster slots every team must fill, e.g. [{"name": "QB", "count": 1}, {"name": "FLEX", "count": 1, "positions": ["RB", "WR", "TE"]}]
+-- An empty list means picks are not restricted by position
+ALTER TABLE events ADD COLUMN roster_slots JSONB NOT NULL DEFAULT '[]'::jsonb;
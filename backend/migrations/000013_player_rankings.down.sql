@@ -0,0 +1,4 @@
+-- Remove player rankings
+ALTER TABLE events DROP COLUMN IF EXISTS ranking_source;
+
+DROP TABLE IF EXISTS rankings;
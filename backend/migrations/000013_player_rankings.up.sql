@@ -0,0 +1,15 @@
+-- Player rankings from named sources (imported lists, and "adp" computed from completed drafts)
+CREATE TABLE rankings (
+    source VARCHAR(100) NOT NULL,
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    rank INTEGER NOT NULL CHECK (rank > 0),
+    tier INTEGER CHECK (tier > 0),
+    value DOUBLE PRECISION,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source, player_id)
+);
+
+CREATE INDEX idx_rankings_source_rank ON rankings(source, rank);
+
+-- The ranking an event's player pool is ordered by (NULL keeps the default order)
+ALTER TABLE events ADD COLUMN ranking_source VARCHAR(100);
@@ -0,0 +1,13 @@
+-- Audit trail of admin force-edits made after an event's draft has started
+-- event_id has no foreign key so entries outlive a deleted event
+CREATE TABLE audit_log (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER,
+    action VARCHAR(50) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    reason TEXT NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_event ON audit_log(event_id, created_at);
@@ -0,0 +1,4 @@
+-- Restore the plaintext passkey column; hashed passkeys cannot be recovered, so every event
+-- needs its passkey set again
+ALTER TABLE events ADD COLUMN passkey VARCHAR(100);
+ALTER TABLE events DROP COLUMN passkey_hash;
@@ -0,0 +1,13 @@
+-- Store event passkeys as salted hashes instead of plaintext
+-- Existing passkeys are hashed as sha256$<hex salt>$<hex sha256(salt || passkey)>; the server
+-- upgrades each one to PBKDF2 the next time it is used successfully
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+ALTER TABLE events ADD COLUMN passkey_hash TEXT;
+
+UPDATE events e
+SET passkey_hash = 'sha256$' || encode(s.salt, 'hex') || '$' || encode(digest(s.salt || convert_to(e.passkey, 'UTF8'), 'sha256'), 'hex')
+FROM (SELECT id, gen_random_bytes(16) AS salt FROM events WHERE passkey IS NOT NULL) s
+WHERE e.id = s.id;
+
+ALTER TABLE events DROP COLUMN passkey;
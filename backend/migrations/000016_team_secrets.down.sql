@@ -0,0 +1,3 @@
+-- Remove per-team secrets
+ALTER TABLE users DROP COLUMN rejoin_token_hash;
+ALTER TABLE users DROP COLUMN pin_hash;
@@ -0,0 +1,4 @@
+-- Optional per-team secrets required to reclaim a team from a new device
+-- pin_hash is a salted hash of a short PIN; rejoin_token_hash is the SHA-256 of a one-time rejoin link token
+ALTER TABLE users ADD COLUMN pin_hash TEXT;
+ALTER TABLE users ADD COLUMN rejoin_token_hash TEXT;
@@ -0,0 +1,5 @@
+-- Remove per-event team registration settings
+ALTER TABLE events DROP COLUMN late_registration;
+ALTER TABLE events DROP COLUMN registration_closes_at;
+ALTER TABLE events DROP COLUMN registration_opens_at;
+ALTER TABLE events DROP COLUMN max_teams;
@@ -0,0 +1,6 @@
+-- Per-event team registration settings: capacity, an optional open/close window, and whether
+-- new teams may still join after the draft starts
+ALTER TABLE events ADD COLUMN max_teams INTEGER NOT NULL DEFAULT 12 CHECK (max_teams > 0);
+ALTER TABLE events ADD COLUMN registration_opens_at TIMESTAMPTZ;
+ALTER TABLE events ADD COLUMN registration_closes_at TIMESTAMPTZ;
+ALTER TABLE events ADD COLUMN late_registration BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,7 @@
+-- Remove team profiles
+ALTER TABLE users DROP COLUMN avatar_updated_at;
+ALTER TABLE users DROP COLUMN avatar_content_type;
+ALTER TABLE users DROP COLUMN avatar;
+ALTER TABLE users DROP COLUMN email;
+ALTER TABLE users DROP COLUMN color;
+ALTER TABLE users DROP COLUMN display_name;
@@ -0,0 +1,8 @@
+-- Team profiles shown on the draft board: display name, color, contact email and an avatar image
+-- The avatar is stored in the database with its content type; avatar_updated_at versions its URL
+ALTER TABLE users ADD COLUMN display_name VARCHAR(50);
+ALTER TABLE users ADD COLUMN color VARCHAR(7);
+ALTER TABLE users ADD COLUMN email VARCHAR(254);
+ALTER TABLE users ADD COLUMN avatar BYTEA;
+ALTER TABLE users ADD COLUMN avatar_content_type VARCHAR(50);
+ALTER TABLE users ADD COLUMN avatar_updated_at TIMESTAMP;
@@ -0,0 +1,7 @@
+-- Remove leagues
+DROP INDEX IF EXISTS users_event_league_member_unique;
+ALTER TABLE users DROP COLUMN league_member_id;
+DROP INDEX IF EXISTS idx_events_league_id;
+ALTER TABLE events DROP COLUMN league_id;
+DROP TABLE IF EXISTS league_members;
+DROP TABLE IF EXISTS leagues;
@@ -0,0 +1,21 @@
+-- Leagues group events played by the same people; each member is linked to their team in every league event
+CREATE TABLE leagues (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE league_members (
+    id SERIAL PRIMARY KEY,
+    league_id INTEGER NOT NULL REFERENCES leagues(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(league_id, name)
+);
+
+ALTER TABLE events ADD COLUMN league_id INTEGER REFERENCES leagues(id) ON DELETE SET NULL;
+CREATE INDEX idx_events_league_id ON events(league_id);
+
+-- A member has at most one team per event
+ALTER TABLE users ADD COLUMN league_member_id INTEGER REFERENCES league_members(id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX users_event_league_member_unique ON users(event_id, league_member_id) WHERE league_member_id IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Remove event templates
+DROP TABLE IF EXISTS event_template_players;
+DROP TABLE IF EXISTS event_templates;
@@ -0,0 +1,20 @@
+-- Reusable event setups: settings, stipulations and a player pool to create new events from
+CREATE TABLE event_templates (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    max_picks_per_team INTEGER NOT NULL DEFAULT 6,
+    max_teams_per_player INTEGER NOT NULL DEFAULT 1,
+    max_spectators INTEGER NOT NULL DEFAULT 20 CHECK (max_spectators >= 0),
+    max_teams INTEGER NOT NULL DEFAULT 12 CHECK (max_teams > 0),
+    stipulations JSONB NOT NULL DEFAULT '{}'::jsonb,
+    roster_slots JSONB NOT NULL DEFAULT '[]'::jsonb,
+    ranking_source VARCHAR(100),
+    late_registration BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE event_template_players (
+    template_id INTEGER NOT NULL REFERENCES event_templates(id) ON DELETE CASCADE,
+    player_id INTEGER NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    PRIMARY KEY (template_id, player_id)
+);
@@ -0,0 +1,2 @@
+-- Remove turn notification preferences
+DROP TABLE IF EXISTS notification_preferences;
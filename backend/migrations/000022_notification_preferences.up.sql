@@ -0,0 +1,9 @@
+-- Per-team opt-in to turn notifications sent outside the draft room
+CREATE TABLE notification_preferences (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    email BOOLEAN NOT NULL DEFAULT FALSE,
+    webhook BOOLEAN NOT NULL DEFAULT FALSE,
+    on_deck_picks INTEGER NOT NULL DEFAULT 0 CHECK (on_deck_picks BETWEEN 0 AND 10),
+    clock_warning_seconds INTEGER NOT NULL DEFAULT 0 CHECK (clock_warning_seconds >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
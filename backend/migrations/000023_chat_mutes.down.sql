@@ -0,0 +1,2 @@
+-- Remove chat mutes
+DROP TABLE IF EXISTS chat_mutes;
@@ -0,0 +1,7 @@
+-- Users muted in an event's chat by the commissioner, kept across room restarts
+CREATE TABLE chat_mutes (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id)
+);
@@ -0,0 +1,2 @@
+-- Remove unclaimed teams
+ALTER TABLE users DROP COLUMN unclaimed;
@@ -0,0 +1,2 @@
+-- Teams copied from another event are unclaimed until the commissioner sets their PIN or releases them
+ALTER TABLE users ADD COLUMN unclaimed BOOLEAN NOT NULL DEFAULT false;
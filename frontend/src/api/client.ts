@@ -1,19 +1,27 @@
-import type { Event, Player, User } from '../types';
+import type { CloneEventRequest, Event, EventRoster, EventTemplate, JoinOptions, JoinResponse, League, LeagueEventRequest, LeagueEventResponse, LeagueMember, MemberHistory, NotificationPrefs, NotificationPrefsUpdate, Player, PlayerPage, PlayerQuery, Ranking, RankingSource, SpectateResponse, TeamProfile, TeamProfileUpdate, Webhook, WebhookCreated, WebhookDelivery, WebhookRequest } from '../types';
 
 const API_BASE = 'http://localhost:8080';
 
 interface FetchOptions {
   method?: 'GET' | 'POST' | 'PUT' | 'DELETE';
   body?: unknown;
+  adminToken?: string; // Sent as X-Admin-Token, for admin-only routes
+  sessionToken?: string; // Sent as X-Session-Token, for routes a team uses as itself
 }
 
 async function fetchJSON<T>(url: string, options: FetchOptions = {}): Promise<T> {
-  const { method = 'GET', body } = options;
+  const { method = 'GET', body, adminToken, sessionToken } = options;
 
+  // Blobs (e.g. avatar images) are sent as-is; everything else as JSON
+  const isBlob = body instanceof Blob;
+  const headers: Record<string, string> = {};
+  if (body) headers['Content-Type'] = isBlob ? body.type : 'application/json';
+  if (adminToken) headers['X-Admin-Token'] = adminToken;
+  if (sessionToken) headers['X-Session-Token'] = sessionToken;
   const response = await fetch(`${API_BASE}${url}`, {
     method,
-    headers: body ? { 'Content-Type': 'application/json' } : undefined,
-    body: body ? JSON.stringify(body) : undefined,
+    headers,
+    body: body ? (isBlob ? body : JSON.stringify(body)) : undefined,
   });
 
   if (!response.ok) {
@@ -33,8 +41,23 @@ export async function getEvent(id: number): Promise<Event> {
   return fetchJSON<Event>(`/events/${id}`);
 }
 
-export async function getPlayers(): Promise<Player[]> {
-  return fetchJSON<Player[]>('/players');
+function playerQueryString(query: PlayerQuery = {}): string {
+  const params = new URLSearchParams();
+  const { attributes, ...rest } = query;
+  for (const [key, value] of Object.entries(rest)) {
+    if (value !== undefined && value !== '') {
+      params.set(key, String(value));
+    }
+  }
+  for (const [key, value] of Object.entries(attributes ?? {})) {
+    params.set(`attr.${key}`, String(value));
+  }
+  const qs = params.toString();
+  return qs ? `?${qs}` : '';
+}
+
+export async function getPlayers(query?: PlayerQuery): Promise<PlayerPage> {
+  return fetchJSON<PlayerPage>(`/players${playerQueryString(query)}`);
 }
 
 export async function getPlayer(id: number): Promise<Player> {
@@ -49,13 +72,125 @@ export async function getUser(id: number): Promise<User> {
   return fetchJSON<User>(`/users/${id}`);
 }
 
-export async function joinDraft(teamName: string, passkey: string): Promise<User> {
-  return fetchJSON<User>(`/events/join`, {
+// Profile changes need the team's own session token; without it the profile's email is null
+export async function getTeamProfile(userID: number, sessionToken?: string): Promise<TeamProfile> {
+  return fetchJSON<TeamProfile>(`/users/${userID}/profile`, { sessionToken });
+}
+
+export async function updateTeamProfile(userID: number, profile: TeamProfileUpdate, sessionToken: string): Promise<TeamProfile> {
+  return fetchJSON<TeamProfile>(`/users/${userID}/profile`, { method: 'PUT', body: profile, sessionToken });
+}
+
+export async function uploadTeamAvatar(userID: number, image: Blob, sessionToken: string): Promise<TeamProfile> {
+  return fetchJSON<TeamProfile>(`/users/${userID}/avatar`, { method: 'PUT', body: image, sessionToken });
+}
+
+export async function deleteTeamAvatar(userID: number, sessionToken: string): Promise<TeamProfile> {
+  return fetchJSON<TeamProfile>(`/users/${userID}/avatar`, { method: 'DELETE', sessionToken });
+}
+
+// Notification preferences are only readable and writable with the team's session token
+export async function getNotificationPrefs(userID: number, sessionToken: string): Promise<NotificationPrefs> {
+  return fetchJSON<NotificationPrefs>(`/users/${userID}/notifications`, { sessionToken });
+}
+
+export async function updateNotificationPrefs(userID: number, prefs: NotificationPrefsUpdate, sessionToken: string): Promise<NotificationPrefs> {
+  return fetchJSON<NotificationPrefs>(`/users/${userID}/notifications`, { method: 'PUT', body: prefs, sessionToken });
+}
+
+// Avatar URLs from the API are relative to it
+export function avatarSrc(avatarURL: string): string {
+  return `${API_BASE}${avatarURL}`;
+}
+
+export async function getEventRoster(eventID: number): Promise<EventRoster> {
+  return fetchJSON<EventRoster>(`/events/${eventID}/roster`);
+}
+
+export async function cloneEvent(eventID: number, request: CloneEventRequest): Promise<Event> {
+  return fetchJSON<Event>(`/events/${eventID}/clone`, { method: 'POST', body: request });
+}
+
+export async function getTemplates(): Promise<EventTemplate[]> {
+  return fetchJSON<EventTemplate[]>('/templates');
+}
+
+export async function saveTemplate(eventID: number, name: string): Promise<EventTemplate> {
+  return fetchJSON<EventTemplate>('/templates', { method: 'POST', body: { eventID, name } });
+}
+
+export async function createEventFromTemplate(templateID: number, name: string, passkey?: string): Promise<Event> {
+  return fetchJSON<Event>(`/templates/${templateID}/events`, { method: 'POST', body: { name, passkey } });
+}
+
+// Webhook routes require the server's admin token
+export async function getWebhooks(eventID: number, adminToken: string): Promise<Webhook[]> {
+  return fetchJSON<Webhook[]>(`/events/${eventID}/webhooks`, { adminToken });
+}
+
+export async function createWebhook(eventID: number, webhook: WebhookRequest, adminToken: string): Promise<WebhookCreated> {
+  return fetchJSON<WebhookCreated>(`/events/${eventID}/webhooks`, { method: 'POST', body: webhook, adminToken });
+}
+
+export async function updateWebhook(eventID: number, webhookID: number, webhook: WebhookRequest, adminToken: string): Promise<Webhook> {
+  return fetchJSON<Webhook>(`/events/${eventID}/webhooks/${webhookID}`, { method: 'PUT', body: webhook, adminToken });
+}
+
+export async function getWebhookDeliveries(eventID: number, webhookID: number, adminToken: string): Promise<WebhookDelivery[]> {
+  return fetchJSON<WebhookDelivery[]>(`/events/${eventID}/webhooks/${webhookID}/deliveries`, { adminToken });
+}
+
+export async function pingWebhook(eventID: number, webhookID: number, adminToken: string): Promise<WebhookDelivery> {
+  return fetchJSON<WebhookDelivery>(`/events/${eventID}/webhooks/${webhookID}/ping`, { method: 'POST', adminToken });
+}
+
+export async function getLeagues(): Promise<League[]> {
+  return fetchJSON<League[]>('/leagues');
+}
+
+export async function getLeagueMembers(leagueID: number): Promise<LeagueMember[]> {
+  return fetchJSON<LeagueMember[]>(`/leagues/${leagueID}/members`);
+}
+
+export async function getLeagueEvents(leagueID: number): Promise<Event[]> {
+  return fetchJSON<Event[]>(`/leagues/${leagueID}/events`);
+}
+
+export async function createLeagueEvent(leagueID: number, event: LeagueEventRequest): Promise<LeagueEventResponse> {
+  return fetchJSON<LeagueEventResponse>(`/leagues/${leagueID}/events`, { method: 'POST', body: event });
+}
+
+export async function getLeagueHistory(leagueID: number): Promise<MemberHistory[]> {
+  return fetchJSON<MemberHistory[]>(`/leagues/${leagueID}/history`);
+}
+
+export async function getMemberHistory(leagueID: number, memberID: number): Promise<MemberHistory> {
+  return fetchJSON<MemberHistory>(`/leagues/${leagueID}/members/${memberID}/history`);
+}
+
+export async function joinDraft(eventID: number, teamName: string, passkey: string, options: JoinOptions = {}): Promise<JoinResponse> {
+  return fetchJSON<JoinResponse>(`/events/join`, {
+    method: 'POST',
+    body: { eventID, teamName, passkey, ...options },
+  });
+}
+
+export async function spectateDraft(eventID: number, passkey: string): Promise<SpectateResponse> {
+  return fetchJSON<SpectateResponse>(`/events/spectate`, {
     method: 'POST',
-    body: { teamName, passkey },
+    body: { eventID, passkey },
   });
 }
 
-export async function getEventPlayers(eventID: number): Promise<Player[]> {
-  return fetchJSON<Player[]>(`/events/${eventID}/players`);
+export async function getEventPlayers(eventID: number, query?: PlayerQuery): Promise<Player[]> {
+  const page = await fetchJSON<PlayerPage>(`/events/${eventID}/players${playerQueryString(query)}`);
+  return page.players;
+}
+
+export async function getRankingSources(): Promise<RankingSource[]> {
+  return fetchJSON<RankingSource[]>('/rankings');
+}
+
+export async function getRankings(source: string): Promise<Ranking[]> {
+  return fetchJSON<Ranking[]>(`/rankings/${encodeURIComponent(source)}`);
 }
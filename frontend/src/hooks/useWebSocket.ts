@@ -1,5 +1,7 @@
 import { useEffect, useRef, useCallback } from 'react';
 import { useDraftStore } from '../store/draftStore';
+import { useLocalStore } from '../store/localStore';
+import { PROTOCOL_SUBPROTOCOL } from '../types';
 import type { ClientMessage, ServerMessage } from '../types';
 
 const WS_URL = 'ws://localhost:8080/ws/draft';
@@ -14,8 +16,16 @@ export function useWebSocket() {
       return;
     }
 
+    // The server takes the team (or spectator) from the session token; without one it refuses the connection
+    const sessionToken = useLocalStore.getState().sessionToken;
+    if (!sessionToken) {
+      setConnectionStatus('disconnected');
+      return;
+    }
+    const url = `${WS_URL}?token=${encodeURIComponent(sessionToken)}`;
+
     setConnectionStatus('connecting');
-    const ws = new WebSocket(WS_URL);
+    const ws = new WebSocket(url, PROTOCOL_SUBPROTOCOL);
 
     ws.onopen = () => {
       setConnectionStatus('connected');
@@ -1,5 +1,4 @@
 import { useEffect } from 'react';
-import { useSearchParams } from 'react-router-dom';
 import { useWebSocket } from '../hooks/useWebSocket';
 import { useDraftStore } from '../store/draftStore';
 import { useLocalStore } from '../store/localStore';
@@ -7,22 +6,21 @@ import { usePlayerStore } from '../store/playerStore';
 import { PlayerList } from '../components/PlayerList';
 
 export function DraftRoom() {
-  // URL query params
-  const [searchParams] = useSearchParams();
-  const userId = Number(searchParams.get('userId'));
-
   // Custom hook - WebSocket connection methods
   const { connect, disconnect } = useWebSocket();
 
   // Zustand store selectors - each subscribes to a slice of global state
   const connectionStatus = useDraftStore((s) => s.connectionStatus);
   const eventID = useLocalStore((s) => s.eventID);
+  const userId = useLocalStore((s) => s.userID); // Set by joining; null when spectating
   const draftStatus = useDraftStore((s) => s.draftStatus);
   const currentTurn = useDraftStore((s) => s.currentTurn);
   const roundNumber = useDraftStore((s) => s.roundNumber);
   const pickHistory = useDraftStore((s) => s.pickHistory);
   const lastError = useDraftStore((s) => s.lastError);
   const turnDeadline = useDraftStore((s) => s.turnDeadline);
+  const currentPickIndex = useDraftStore((s) => s.currentPickIndex);
+  const turnAlert = useDraftStore((s) => s.turnAlert);
 
   const initializeEventPlayers = usePlayerStore((s) => s.setEventPlayers);
 
@@ -40,7 +38,10 @@ export function DraftRoom() {
   }, [eventID, initializeEventPlayers])
 
   // Computed value - derived from state, recalculates each render
-  const isMyTurn = currentTurn === userId;
+  const isMyTurn = userId != null && currentTurn === userId;
+
+  // An alert is stale once its pick has been made; it may arrive before or after turn_changed
+  const activeAlert = turnAlert && turnAlert.pickNumber > currentPickIndex ? turnAlert : null;
 
   return (
     <div className="min-h-screen bg-gray-900 text-white p-4">
@@ -70,7 +71,7 @@ export function DraftRoom() {
                 : 'Disconnected'}
             </span>
           </div>
-          <p className="text-xs text-gray-400 mt-1">Your User ID: {userId}</p>
+          <p className="text-xs text-gray-400 mt-1">Your User ID: {userId ?? 'Spectating'}</p>
         </div>
 
         {/* Error Display */}
@@ -80,6 +81,23 @@ export function DraftRoom() {
           </div>
         )}
 
+        {/* Turn Alert */}
+        {activeAlert && draftStatus === 'in_progress' && (
+          <div className="mb-4 p-3 bg-yellow-900/50 border border-yellow-600 rounded text-yellow-200">
+            {activeAlert.type === 'on_the_clock' ? (
+              <span className="font-bold">
+                You're on the clock! Make pick #{activeAlert.pickNumber} by{' '}
+                {new Date(activeAlert.turnDeadline * 1000).toLocaleTimeString()}.
+              </span>
+            ) : (
+              <span>
+                You're on deck: pick #{activeAlert.pickNumber} is{' '}
+                {activeAlert.pickNumber - currentPickIndex - 1} pick(s) away.
+              </span>
+            )}
+          </div>
+        )}
+
         {/* Draft Status */}
         <div className="mb-4 p-4 bg-gray-800 rounded">
           <h2 className="font-semibold mb-2">Draft Status</h2>
@@ -1,23 +1,34 @@
 import { useState } from 'react';
-import { useNavigate } from 'react-router-dom';
+import { useNavigate, useSearchParams } from 'react-router-dom';
 import { joinDraft } from '../api/client';
 import { useLocalStore } from '../store/localStore';
 
 export function JoinPage() {
   const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
   const setEventID = useLocalStore((state) => state.setEventID);
-  const [teamName, setTeamName] = useState<string>('');
+  const setSession = useLocalStore((state) => state.setSession);
+  // Invite links can carry the event ID: /?event=12
+  const [eventIDInput, setEventIDInput] = useState<string>(searchParams.get('event') ?? '');
+  const [teamName, setTeamName] = useState<string>(searchParams.get('team') ?? '');
   const [passKey, setPassKey] = useState<string>('');
+  const [pin, setPin] = useState<string>('');
   const [error, setError] = useState<string | null>(null);
+  // Rejoin links also carry the team and its one-time token: /?event=12&team=Alpha&token=...
+  const rejoinToken = searchParams.get('token') ?? undefined;
+
+  const eventID = Number(eventIDInput);
+  const canJoin = Number.isInteger(eventID) && eventID > 0 && teamName !== '' && passKey !== '' && (pin === '' || /^\d{4,8}$/.test(pin));
 
   function handleJoin() {
-    if (!teamName || !passKey) return;
+    if (!canJoin) return;
     // Clear out error before attempting to join draft
     setError(null);
-    joinDraft(teamName, passKey)
+    joinDraft(eventID, teamName, passKey, { pin: pin || undefined, rejoinToken })
       .then((user) => {
         // Set eventID so we can initialize event players when setting up the draft room
         setEventID(user.eventID);
+        setSession(user.id, user.sessionToken);
         navigate('/draft');
       })
       .catch((err: Error) => setError(err.message || 'Failed to join draft'));
@@ -31,6 +42,18 @@ export function JoinPage() {
       >
         <h1 className="text-2xl font-bold mb-6 text-center">Join Draft</h1>
 
+        <div className="mb-6">
+          <label className="block text-lg font-medium mb-2">Event ID</label>
+          <input
+            type="text"
+            inputMode="numeric"
+            value={eventIDInput}
+            onChange={(e) => setEventIDInput(e.target.value.trim())}
+            placeholder="Enter Event ID"
+            className="w-full px-4 py-3 bg-gray-700 border border-gray-600 rounded-lg text-white placeholder-gray-400 focus:outline-none focus:border-blue-500 focus:ring-1 focus:ring-blue-500"
+          />
+        </div>
+
         <div className="mb-6">
           <label className="block text-lg font-medium mb-2">Team Name</label>
           <input
@@ -53,6 +76,18 @@ export function JoinPage() {
           />
         </div>
 
+        <div className="mb-6">
+          <label className="block text-lg font-medium mb-2">PIN (optional)</label>
+          <input
+            type="password"
+            inputMode="numeric"
+            value={pin}
+            onChange={(e) => setPin(e.target.value.trim())}
+            placeholder="4-8 digits - protects your team on other devices"
+            className="w-full px-4 py-3 bg-gray-700 border border-gray-600 rounded-lg text-white placeholder-gray-400 focus:outline-none focus:border-blue-500 focus:ring-1 focus:ring-blue-500"
+          />
+        </div>
+
         {error && (
           <div className="mb-4 p-3 bg-red-900/50 border border-red-500 rounded-lg text-red-300 text-sm">
             {error}
@@ -61,7 +96,7 @@ export function JoinPage() {
 
         <button
           type="submit"
-          disabled={!(teamName && passKey)}
+          disabled={!canJoin}
           className="w-full bg-blue-600 hover:bg-blue-700 disabled:bg-gray-600 disabled:text-gray-400 disabled:cursor-not-allowed text-white font-medium py-2 px-4 rounded transition-colors"
         >
           Join
@@ -1,5 +1,5 @@
 import { create } from 'zustand';
-import type { Pick, ServerMessage } from '../types';
+import type { OnDeckMessage, OnTheClockMessage, Pick, ServerMessage } from '../types';
 
 type ConnectionStatus = 'disconnected' | 'connecting' | 'connected';
 type DraftStatus = 'idle' | 'in_progress' | 'paused' | 'completed';
@@ -20,6 +20,11 @@ interface DraftState {
   turnDeadline: number | null;
   remainingTime: number;
 
+  // Latest on_deck / on_the_clock alert for this connection's team
+  // It can arrive before or after the matching turn_changed, so compare its pickNumber
+  // with currentPickIndex + 1 rather than relying on message order
+  turnAlert: OnDeckMessage | OnTheClockMessage | null;
+
   // Error
   lastError: string | null;
 
@@ -41,6 +46,7 @@ const initialState = {
   pickHistory: [],
   turnDeadline: null,
   remainingTime: 0,
+  turnAlert: null,
   lastError: null,
 };
 
@@ -82,6 +88,7 @@ export const useDraftStore = create<DraftState>((set) => ({
 
       case 'pick_made':
         set((state) => ({
+          currentPickIndex: state.currentPickIndex + 1,
           pickHistory: [
             ...state.pickHistory,
             {
@@ -106,9 +113,15 @@ export const useDraftStore = create<DraftState>((set) => ({
         });
         break;
 
+      case 'on_deck':
+      case 'on_the_clock':
+        set({ turnAlert: message });
+        break;
+
       case 'draft_completed':
         set({
           draftStatus: 'completed',
+          turnAlert: null,
           totalRounds: message.totalRounds,
         });
         break;
@@ -117,6 +130,7 @@ export const useDraftStore = create<DraftState>((set) => ({
         set({
           draftStatus: 'paused',
           remainingTime: message.remainingTime,
+          ...(message.error ? { lastError: message.error } : {}),
         });
         break;
 
@@ -3,12 +3,17 @@ import { persist } from 'zustand/middleware';
 
 interface LocalState {
   eventID: number | null;
+  userID: number | null; // null for a spectator
+  sessionToken: string | null; // From POST /events/join or /events/spectate; the draft room connection identifies with it
   setEventID: (eventID: number) => void;
+  setSession: (userID: number | null, sessionToken: string) => void;
   clear: () => void;
 }
 
 const initialState = {
   eventID: null,
+  userID: null,
+  sessionToken: null,
 };
 
 export const useLocalStore = create<LocalState>()(
@@ -16,6 +21,7 @@ export const useLocalStore = create<LocalState>()(
     (set) => ({
       ...initialState,
       setEventID: (eventID) => set({ eventID }),
+      setSession: (userID, sessionToken) => set({ userID, sessionToken }),
       clear: () => set(initialState),
     }),
     { name: 'draft-local-store' },
@@ -5,6 +5,16 @@ export interface Event {
   name: string;
   maxPicksPerTeam: number;
   maxTeamsPerPlayer: number;
+  maxSpectators: number;
+  maxTeams: number;
+  registrationOpensAt: string | null;
+  registrationClosesAt: string | null;
+  lateRegistration: boolean; // Whether new teams may join after the draft starts
+  rosterSlots: RosterSlot[];
+  rankingSource: string | null;
+  hasPasskey: boolean; // The passkey itself is write-only
+  commissionerUserID?: number; // Read-only: set when the first team joins
+  leagueID: number | null;
   stipulations: Record<string, unknown>;
   status: 'pending' | 'in_progress' | 'completed';
   createdAt: string;
@@ -18,23 +28,253 @@ export interface Player {
   lastName: string;
   status: string;
   countryCode: string;
+  externalID?: string;
+  positions: string[];
+  attributes: Record<string, unknown>;
+  rank?: number; // Event player listings only, from the event's ranking source
+  tier?: number;
+}
+
+// A named group of roster spots; positions ['*'] accepts any player
+export interface RosterSlot {
+  name: string;
+  count: number;
+  positions: string[];
 }
 
 export interface User {
   id: number;
   eventID: number;
   username: string;
+  leagueMemberID: number | null; // Set for teams in a league event
+  displayName: string | null;
+  color: string | null; // #rrggbb
+  avatarURL?: string; // Relative to the API base; absent without an avatar
+  hasPin: boolean;
+  hasRejoinLink: boolean;
+  unclaimed: boolean; // Copied by a clone; cannot be joined until the commissioner resets it
   createdAt: string;
 }
 
+// GET/PUT /users/{id}/profile - the only place a team's contact email is returned
+export interface TeamProfile extends User {
+  email: string | null;
+}
+
+// Omitted or empty fields are cleared
+export interface TeamProfileUpdate {
+  displayName?: string;
+  color?: string;
+  email?: string;
+}
+
+// GET/PUT /users/{id}/notifications - every notification is off until a team opts in
+export interface NotificationPrefs {
+  userID: number;
+  email: boolean; // Needs the team profile's contact email
+  webhook: boolean; // Sent through the event's webhooks subscribed to on_deck / on_the_clock
+  onDeckPicks: number; // 0-10; notify when the team's turn is this many picks away; 0 for never
+  clockWarningSeconds: number; // 0-3600; notify when the turn has this many seconds left; 0 for never
+  updatedAt: string;
+}
+
+export type NotificationPrefsUpdate = Omit<NotificationPrefs, 'userID' | 'updatedAt'>;
+
+// How a team appears on the draft board (draft_state snapshots)
+export interface DraftTeam {
+  userID: number;
+  username: string;
+  displayName: string; // Falls back to the username
+  color?: string;
+  avatarURL?: string;
+}
+
+// POST /events/{id}/clone - nothing is copied unless asked for
+export interface CloneEventRequest {
+  name: string;
+  passkey?: string; // The source's passkey is never copied
+  copySettings?: boolean;
+  copyStipulations?: boolean;
+  copyPlayerPool?: boolean;
+  copyTeams?: boolean;
+}
+
+// A saved event setup to create events from
+export interface EventTemplate {
+  id: number;
+  name: string;
+  maxPicksPerTeam: number;
+  maxTeamsPerPlayer: number;
+  maxSpectators: number;
+  maxTeams: number;
+  stipulations: Record<string, unknown>;
+  rosterSlots: RosterSlot[];
+  rankingSource: string | null;
+  lateRegistration: boolean;
+  playerIDs: number[];
+  createdAt: string;
+}
+
+// Webhooks - draft messages posted to an event's subscriber URLs
+
+export type WebhookMessageType = 'draft_started' | 'pick_made' | 'turn_changed' | 'draft_paused' | 'draft_resumed' | 'draft_completed' | 'on_deck' | 'on_the_clock';
+
+export interface Webhook {
+  id: number;
+  eventID: number;
+  url: string;
+  messageTypes: WebhookMessageType[]; // Empty for every type
+  active: boolean;
+  createdAt: string;
+}
+
+// POST /events/{id}/webhooks - the only response that includes the signing secret
+export interface WebhookCreated extends Webhook {
+  secret: string;
+}
+
+export interface WebhookRequest {
+  url: string;
+  messageTypes?: WebhookMessageType[];
+  active?: boolean; // Defaults to true
+}
+
+export interface WebhookDelivery {
+  id: number;
+  webhookID: number;
+  messageType: WebhookMessageType | 'ping';
+  payload: { type: string; eventID: number; occurredAt: string; data: unknown };
+  status: 'pending' | 'delivered' | 'failed';
+  attempts: number;
+  nextAttemptAt: string;
+  lastStatusCode: number | null;
+  lastError: string | null;
+  createdAt: string;
+  deliveredAt: string | null;
+}
+
+// Leagues - the same people drafting across events
+
+export interface League {
+  id: number;
+  name: string;
+  createdAt: string;
+}
+
+export interface LeagueMember {
+  id: number;
+  leagueID: number;
+  name: string;
+  createdAt: string;
+}
+
+// POST /leagues/{id}/events - the remaining fields are the same as POST /events
+export interface LeagueEventRequest extends Partial<Omit<Event, 'id' | 'hasPasskey'>> {
+  name: string;
+  passkey?: string;
+  commissionerMemberID?: number; // Defaults to the league's first member
+}
+
+export interface LeagueEventResponse {
+  event: Event;
+  teams: User[];
+}
+
+// A pick with its player, as returned by the results and history endpoints
+export interface DraftPick {
+  id: number;
+  eventID: number;
+  userID: number;
+  playerID: number;
+  pickNumber: number;
+  round: number;
+  provenance: PickProvenance;
+  createdAt: string;
+  username: string;
+  player: Player;
+}
+
+export interface MemberTeam {
+  eventID: number;
+  eventName: string;
+  status: Event['status'];
+  startedAt?: string;
+  completedAt?: string;
+  userID: number;
+  teamName: string;
+  picks: DraftPick[];
+}
+
+// GET /leagues/{id}/history and /leagues/{id}/members/{memberID}/history
+export interface MemberHistory {
+  member: LeagueMember;
+  teams: MemberTeam[]; // Oldest event first
+}
+
+// GET /events/{id}/roster
+export interface EventRoster {
+  eventID: number;
+  status: Event['status'];
+  maxTeams: number;
+  registrationOpensAt: string | null;
+  registrationClosesAt: string | null;
+  lateRegistration: boolean;
+  registrationOpen: boolean;
+  closedReason: string; // Empty while registration is open
+  commissionerUserID: number | null;
+  teams: User[];
+}
+
+// POST /events/join response; rejoinToken is only present when a new rejoin link was issued
+export interface JoinResponse extends User {
+  rejoinToken?: string;
+  sessionToken: string; // Identifies the team's draft room connection: /ws/draft?token=...
+  sessionExpiresAt: string;
+}
+
+// POST /events/spectate response
+export interface SpectateResponse {
+  eventID: number;
+  eventName: string;
+  spectator: true;
+  maxSpectators: number;
+  sessionToken: string; // Lets the client watch: /ws/draft?token=... or the draft feed
+  sessionExpiresAt: string;
+}
+
+export interface JoinOptions {
+  pin?: string;
+  rejoinToken?: string;
+  rejoinLink?: boolean;
+}
+
+// Rankings
+
+export interface RankingSource {
+  name: string; // 'adp' is computed from completed drafts
+  players: number;
+  updatedAt: string;
+}
+
+export interface Ranking {
+  source: string;
+  playerID: number;
+  rank: number;
+  tier?: number;
+  value?: number; // Average pick number for 'adp'
+}
+
 // Draft State
 
+export type PickProvenance = 'manual' | 'auto' | 'admin' | 'keeper';
+
 export interface Pick {
   userID: number;
   playerID: number;
   pickNumber: number;
   round: number;
   autoDraft: boolean;
+  provenance: PickProvenance;
 }
 
 // Player List Sorting
@@ -47,21 +287,46 @@ export interface PlayerSort {
   sortDirection: SortDirection | null;
 }
 
+// Player List Queries (GET /players, GET /events/{id}/players)
+
+export interface PlayerQuery {
+  search?: string;
+  status?: 'professional' | 'amateur';
+  countryCode?: string;
+  position?: string;
+  attributes?: Record<string, string | number | boolean>; // Sent as attr.<key>=<value>
+  sort?: 'id' | PlayerSortField | 'status' | 'rank'; // rank: event players only (their default)
+  order?: SortDirection;
+  limit?: number;
+  offset?: number;
+  available?: boolean; // Event players only
+}
+
+export interface PlayerPage {
+  players: Player[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
 // WebSocket Messages: Client -> Server
+// Shapes mirror protocol.schema.json, which is generated from the backend (go generate ./internal/draft)
+
+export const PROTOCOL_VERSION = 1;
+export const PROTOCOL_SUBPROTOCOL = `draft.v${PROTOCOL_VERSION}`;
 
 export interface StartDraftMessage {
   type: 'start_draft';
-  eventID: number;
   pickOrder: number[];
   totalRounds: number;
   timerDuration: number;
-  availablePlayers: number[];
 }
 
 export interface MakePickMessage {
   type: 'make_pick';
   userID: number;
   playerID: number;
+  keeper?: boolean; // Commissioner only: the team keeps the player with this pick
 }
 
 export interface PauseDraftMessage {
@@ -72,14 +337,45 @@ export interface ResumeDraftMessage {
   type: 'resume_draft';
 }
 
+export interface SendChatMessage {
+  type: 'chat_message';
+  text: string;
+}
+
+export interface MuteUserMessage {
+  type: 'mute_user';
+  userID: number;
+  muted: boolean;
+}
+
+export interface DeleteChatMessageMessage {
+  type: 'delete_chat_message';
+  messageID: number;
+}
+
+export interface ResetTeamSecretMessage {
+  type: 'reset_team_secret';
+  userID: number;
+  pin?: string; // 4-8 digits; the team's new PIN, or omitted to clear it
+}
+
 export type ClientMessage =
   | StartDraftMessage
   | MakePickMessage
   | PauseDraftMessage
-  | ResumeDraftMessage;
+  | ResumeDraftMessage
+  | SendChatMessage
+  | MuteUserMessage
+  | DeleteChatMessageMessage
+  | ResetTeamSecretMessage;
 
 // WebSocket Messages: Server -> Client
 
+export interface WelcomeMessage {
+  type: 'welcome';
+  protocolVersion: number;
+}
+
 export interface DraftStartedMessage {
   type: 'draft_started';
   eventID: number;
@@ -92,8 +388,10 @@ export interface PickMadeMessage {
   type: 'pick_made';
   userID: number;
   playerID: number;
+  pickNumber: number;
   round: number;
   autoDraft: boolean;
+  provenance: PickProvenance;
 }
 
 export interface TurnChangedMessage {
@@ -103,6 +401,24 @@ export interface TurnChangedMessage {
   turnDeadline: number;
 }
 
+// Sent only to the connections of the team with the next pick
+export interface OnDeckMessage {
+  type: 'on_deck';
+  userID: number;
+  picksAway: number;
+  pickNumber: number;
+  round: number;
+}
+
+// Sent only to the connections of the team whose turn started or resumed
+export interface OnTheClockMessage {
+  type: 'on_the_clock';
+  userID: number;
+  pickNumber: number;
+  round: number;
+  turnDeadline: number;
+}
+
 export interface DraftCompletedMessage {
   type: 'draft_completed';
   eventID: number;
@@ -114,6 +430,7 @@ export interface DraftPausedMessage {
   type: 'draft_paused';
   eventID: number;
   remainingTime: number;
+  error?: string; // Set when the server paused the draft itself
 }
 
 export interface DraftResumedMessage {
@@ -137,6 +454,43 @@ export interface DraftStateMessage {
   turnDeadline: number;
   remainingTime: number;
   pickHistory: Pick[];
+  rosterSlots: RosterSlot[];
+  teams: DraftTeam[];
+  chatHistory: ChatEntry[];
+}
+
+export interface ChatEntry {
+  id: number;
+  userID: number;
+  text: string;
+  createdAt: number;
+}
+
+export interface ChatMessage extends ChatEntry {
+  type: 'chat_message';
+}
+
+export interface ChatMessageDeletedMessage {
+  type: 'chat_message_deleted';
+  messageID: number;
+}
+
+export interface UserMutedMessage {
+  type: 'user_muted';
+  userID: number;
+  muted: boolean;
+}
+
+export interface TeamSecretResetMessage {
+  type: 'team_secret_reset';
+  userID: number;
+}
+
+export interface PresenceMessage {
+  type: 'presence';
+  connectedUsers: number[];
+  spectators: number;
+  watchers: number;
 }
 
 export interface ErrorMessage {
@@ -145,11 +499,19 @@ export interface ErrorMessage {
 }
 
 export type ServerMessage =
+  | WelcomeMessage
   | DraftStartedMessage
   | PickMadeMessage
   | TurnChangedMessage
+  | OnDeckMessage
+  | OnTheClockMessage
   | DraftCompletedMessage
   | DraftPausedMessage
   | DraftResumedMessage
   | DraftStateMessage
+  | PresenceMessage
+  | ChatMessage
+  | ChatMessageDeletedMessage
+  | UserMutedMessage
+  | TeamSecretResetMessage
   | ErrorMessage;